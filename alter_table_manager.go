@@ -0,0 +1,188 @@
+package codex
+
+// AlterTableManager manages a tree that compiles to a SQL ALTER TABLE statement.
+// Actions are rendered comma separated in order of their calls.
+//
+//	users.AlterTable().
+//		AddColumn(&ColumnDef{Name: "nick", Type: "text", Nullable: true}).
+//		DropColumn("legacy").
+//		ToSql()
+//	// ALTER TABLE "users" ADD COLUMN "nick" text,DROP COLUMN "legacy"
+type AlterTableManager struct {
+	Tree    *AlterTableStatementNode // The AST for the SQL ALTER TABLE statement.
+	Adapter adapter                  // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
+}
+
+// IfExists renders ALTER TABLE IF EXISTS, Postgres only.
+func (self *AlterTableManager) IfExists() *AlterTableManager {
+	self = self.chain()
+	self.Tree.IfExists = true
+	return self
+}
+
+// AddColumn appends an ADD COLUMN action per column definition.
+func (self *AlterTableManager) AddColumn(columns ...*ColumnDef) *AlterTableManager {
+	self = self.chain()
+	for _, column := range columns {
+		if column == nil {
+			if self.err == nil {
+				self.err = unexpectedType("AlterTableManager.AddColumn() expected *ColumnDef but", column)
+			}
+			continue
+		}
+		self.Tree.Actions = append(self.Tree.Actions, &AddColumnNode{column})
+	}
+	return self
+}
+
+// DropColumn appends a DROP COLUMN action per name.
+func (self *AlterTableManager) DropColumn(names ...string) *AlterTableManager {
+	self = self.chain()
+	for _, name := range names {
+		self.Tree.Actions = append(self.Tree.Actions, &DropColumnNode{name})
+	}
+	return self
+}
+
+// RenameColumn appends a RENAME COLUMN action.
+// Postgres does not allow renaming combined with other actions.
+func (self *AlterTableManager) RenameColumn(name, newName string) *AlterTableManager {
+	self = self.chain()
+	self.Tree.Actions = append(self.Tree.Actions, &RenameColumnNode{name, newName})
+	return self
+}
+
+// AlterColumn changes type, nullability and default of the column named column.Name, see AlterColumnNode.
+func (self *AlterTableManager) AlterColumn(column *ColumnDef) *AlterTableManager {
+	self = self.chain()
+	if column == nil {
+		if self.err == nil {
+			self.err = unexpectedType("AlterTableManager.AlterColumn() expected *ColumnDef but", column)
+		}
+		return self
+	}
+	self.Tree.Actions = append(self.Tree.Actions, &AlterColumnNode{Column: column})
+	return self
+}
+
+// AlterColumnFrom is like AlterColumn but Postgres renders only the clauses changed from the definition from,
+// an unchanged column fails with ErrNothingToSet.
+func (self *AlterTableManager) AlterColumnFrom(from, column *ColumnDef) *AlterTableManager {
+	self = self.chain()
+	if from == nil || column == nil {
+		if self.err == nil {
+			self.err = unexpectedType("AlterTableManager.AlterColumnFrom() expected two *ColumnDef but", (*ColumnDef)(nil))
+		}
+		return self
+	}
+	self.Tree.Actions = append(self.Tree.Actions, &AlterColumnNode{Column: column, From: from})
+	return self
+}
+
+// SetDefault appends an ALTER COLUMN ... SET DEFAULT action, value is e.g. 0 or Literal("now()").
+func (self *AlterTableManager) SetDefault(name string, value interface{}) *AlterTableManager {
+	self = self.chain()
+	if value == nil {
+		return self.DropDefault(name)
+	}
+	self.Tree.Actions = append(self.Tree.Actions, &ColumnDefaultNode{name, value})
+	return self
+}
+
+// DropDefault appends an ALTER COLUMN ... DROP DEFAULT action.
+func (self *AlterTableManager) DropDefault(name string) *AlterTableManager {
+	self = self.chain()
+	self.Tree.Actions = append(self.Tree.Actions, &ColumnDefaultNode{Name: name})
+	return self
+}
+
+// AddConstraint appends an ADD action per constraint e.g. Unique("email").Named("users_email_key").
+func (self *AlterTableManager) AddConstraint(constraints ...interface{}) *AlterTableManager {
+	self = self.chain()
+	for _, constraint := range constraints {
+		if err := checkConstraint("AlterTableManager.AddConstraint()", constraint); err != nil {
+			if self.err == nil {
+				self.err = err
+			}
+			continue
+		}
+		self.Tree.Actions = append(self.Tree.Actions, &AddConstraintNode{constraint})
+	}
+	return self
+}
+
+// DropConstraint appends a DROP CONSTRAINT action per name.
+func (self *AlterTableManager) DropConstraint(names ...string) *AlterTableManager {
+	self = self.chain()
+	for _, name := range names {
+		self.Tree.Actions = append(self.Tree.Actions, &DropConstraintNode{name})
+	}
+	return self
+}
+
+// RenameTo appends a RENAME TO action renaming the table.
+// Postgres does not allow renaming combined with other actions.
+func (self *AlterTableManager) RenameTo(newName string) *AlterTableManager {
+	self = self.chain()
+	self.Tree.Actions = append(self.Tree.Actions, &RenameTableNode{newName})
+	return self
+}
+
+// Hooks adds hooks called by ToSql, see Hook.
+func (self *AlterTableManager) Hooks(hooks ...Hook) *AlterTableManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *AlterTableManager) Clone() *AlterTableManager {
+	m := *self
+	m.Tree = cloneAlterTableStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+func (self *AlterTableManager) Immutable() *AlterTableManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *AlterTableManager) chain() *AlterTableManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the statement. ToSql returns it as well.
+func (self *AlterTableManager) Err() error {
+	return self.err
+}
+
+// ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// Default values are inlined as literals, so no arguments are returned.
+func (self *AlterTableManager) ToSql() (string, []interface{}, error) {
+	return renderDdl(self.hooks, "ALTER TABLE", self.Tree.Table, self.Adapter, self.err, self.Tree)
+}
+
+func (self *AlterTableManager) Table() *TableNode {
+	return self.Tree.Table
+}
+
+// AlterTableManager factory method.
+func AlterTable(relation *TableNode) (m *AlterTableManager) {
+	m = new(AlterTableManager)
+	m.Tree = AlterTableStatement(relation)
+	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
+	return
+}
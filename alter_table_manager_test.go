@@ -0,0 +1,119 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlterTableManager(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	sql, args, err := users.AlterTable().IfExists().
+		AddColumn(&ColumnDef{Name: "nick", Type: "text", Nullable: true}).
+		DropColumn("legacy", "old").
+		AlterColumn(&ColumnDef{Name: "score", Type: "numeric(10,2)", Default: 0}).
+		AlterColumn(&ColumnDef{Name: "bio", Type: "text", Nullable: true}).
+		SetDefault("active", true).
+		DropDefault("role").
+		AddConstraint(Unique("email").Named("users_email_key")).
+		DropConstraint("users_nick_check").
+		ToSql()
+
+	assert.Nil(t, err)
+	assert.Nil(t, args)
+	assert.Equal(t, `ALTER TABLE IF EXISTS "users" `+
+		`ADD COLUMN "nick" text,`+
+		`DROP COLUMN "legacy",DROP COLUMN "old",`+
+		`ALTER COLUMN "score" TYPE numeric(10,2),ALTER COLUMN "score" SET NOT NULL,ALTER COLUMN "score" SET DEFAULT 0,`+
+		`ALTER COLUMN "bio" TYPE text,ALTER COLUMN "bio" DROP NOT NULL,ALTER COLUMN "bio" DROP DEFAULT,`+
+		`ALTER COLUMN "active" SET DEFAULT TRUE,`+
+		`ALTER COLUMN "role" DROP DEFAULT,`+
+		`ADD CONSTRAINT "users_email_key" UNIQUE ("email"),`+
+		`DROP CONSTRAINT "users_nick_check"`, sql)
+}
+
+func TestAlterTableManagerRename(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	sql, _, err := users.AlterTable().RenameColumn("name", "full_name").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `ALTER TABLE "users" RENAME COLUMN "name" TO "full_name"`, sql)
+
+	sql, _, err = users.AlterTable().RenameTo("accounts").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `ALTER TABLE "users" RENAME TO "accounts"`, sql)
+
+	_, _, err = users.AlterTable().RenameTo("accounts").DropColumn("legacy").ToSql()
+	assert.True(t, errors.Is(err, ErrUnsupportedByDialect))
+
+	sql, _, err = Dialect(MYSQL).Table("users").AlterTable().RenameColumn("name", "full_name").RenameTo("accounts").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "ALTER TABLE `users` RENAME COLUMN `name` TO `full_name`,RENAME TO `accounts`", sql)
+}
+
+func TestAlterTableManagerAlterColumnFrom(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	from := &ColumnDef{Name: "score", Type: "int", Default: 0}
+	sql, _, err := users.AlterTable().
+		AlterColumnFrom(from, &ColumnDef{Name: "score", Type: "bigint", Default: 0}).
+		AlterColumnFrom(from, &ColumnDef{Name: "score", Type: "int", Nullable: true}).
+		AlterColumn(&ColumnDef{Name: "id", Type: "bigint", Identity: true}).
+		ToSql()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `ALTER TABLE "users" `+
+		`ALTER COLUMN "score" TYPE bigint,`+
+		`ALTER COLUMN "score" DROP NOT NULL,ALTER COLUMN "score" DROP DEFAULT,`+
+		`ALTER COLUMN "id" TYPE bigint,ALTER COLUMN "id" SET NOT NULL`, sql)
+
+	_, _, err = users.AlterTable().AlterColumnFrom(from, from).ToSql()
+	assert.True(t, errors.Is(err, ErrNothingToSet))
+
+	_, _, err = users.AlterTable().AlterColumnFrom(nil, from).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+}
+
+func TestAlterTableManagerMySql(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+	sql, _, err := users.AlterTable().
+		AddColumn(&ColumnDef{Name: "team_id", Type: "int", Nullable: true, References: &ForeignKey{Table: "teams", Column: "id", OnDelete: "SET NULL"}}).
+		AlterColumn(&ColumnDef{Name: "id", Type: "bigint unsigned", Identity: true}).
+		AlterColumn(&ColumnDef{Name: "role", Type: "varchar(20)", Default: "user"}).
+		AlterColumnFrom(&ColumnDef{Name: "n", Type: "int"}, &ColumnDef{Name: "n", Type: "bigint", Identity: true, Default: 1}).
+		ToSql()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ALTER TABLE `users` "+
+		"ADD COLUMN `team_id` int,"+
+		"ADD FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`) ON DELETE SET NULL,"+
+		"MODIFY COLUMN `id` bigint unsigned NOT NULL AUTO_INCREMENT,"+
+		"MODIFY COLUMN `role` varchar(20) NOT NULL DEFAULT 'user',"+
+		"MODIFY COLUMN `n` bigint NOT NULL AUTO_INCREMENT", sql)
+
+	_, _, err = users.AlterTable().IfExists().DropColumn("legacy").ToSql()
+	assert.True(t, errors.Is(err, ErrUnsupportedByDialect))
+}
+
+func TestAlterTableManagerErrors(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	_, _, err := users.AlterTable().ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, _, err = users.AlterTable().AlterColumn(&ColumnDef{Name: "id"}).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+
+	m := users.AlterTable().AddColumn(nil).AddConstraint(42)
+	assert.True(t, errors.Is(m.Err(), ErrUnexpectedType))
+}
+
+func TestAlterTableManagerImmutable(t *testing.T) {
+	base := Dialect(POSTGRES).Table("users").AlterTable().DropColumn("a").Immutable()
+	other := base.DropColumn("b")
+
+	sql, _, _ := base.ToSql()
+	assert.Equal(t, `ALTER TABLE "users" DROP COLUMN "a"`, sql)
+	sql, _, _ = other.ToSql()
+	assert.Equal(t, `ALTER TABLE "users" DROP COLUMN "a",DROP COLUMN "b"`, sql)
+}
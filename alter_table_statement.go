@@ -0,0 +1,125 @@
+package codex
+
+// AlterTableStatementNode is the base node for SQL ALTER TABLE statements.
+type AlterTableStatementNode struct {
+	Table    *TableNode    // Pointer to the Table to alter.
+	IfExists bool          // ALTER TABLE IF EXISTS
+	Actions  []interface{} // Actions e.g. *AddColumnNode, *DropColumnNode or *AddConstraintNode.
+}
+
+// AlterTableStatementNode factory method.
+func AlterTableStatement(relation *TableNode) (statement *AlterTableStatementNode) {
+	statement = new(AlterTableStatementNode)
+	statement.Table = relation
+	return
+}
+
+// AddColumnNode adds a column: ADD COLUMN "name" type ...
+type AddColumnNode struct {
+	Column *ColumnDef
+}
+
+// DropColumnNode drops a column: DROP COLUMN "name"
+type DropColumnNode struct {
+	Name string
+}
+
+// RenameColumnNode renames a column: RENAME COLUMN "name" TO "new_name"
+type RenameColumnNode struct {
+	Name, NewName string
+}
+
+// AlterColumnNode changes type, nullability and default of a column to the ones of Column.
+// Other attributes of Column e.g. PrimaryKey are ignored, so is the default of identity columns.
+// Postgres renders ALTER COLUMN "name" TYPE ..., only the clauses differing from From if set.
+// MySQL renders the full definition MODIFY COLUMN "name" ...
+type AlterColumnNode struct {
+	Column *ColumnDef
+	From   *ColumnDef // former definition, nil for all clauses
+}
+
+// ColumnDefaultNode sets or drops the default of a column:
+// ALTER COLUMN "name" SET DEFAULT value or ALTER COLUMN "name" DROP DEFAULT if Default is nil.
+type ColumnDefaultNode struct {
+	Name    string
+	Default interface{}
+}
+
+// AddConstraintNode adds a table constraint: ADD [CONSTRAINT "name" ]UNIQUE (...)
+type AddConstraintNode struct {
+	Constraint interface{} // e.g. *UniqueNode or *ForeignKeyNode
+}
+
+// DropConstraintNode drops a named constraint: DROP CONSTRAINT "name"
+type DropConstraintNode struct {
+	Name string
+}
+
+// RenameTableNode renames the table: RENAME TO "new_name"
+type RenameTableNode struct {
+	NewName string
+}
+
+var (
+	_ SqlNode = (*AddColumnNode)(nil)
+	_ SqlNode = (*DropColumnNode)(nil)
+	_ SqlNode = (*RenameColumnNode)(nil)
+	_ SqlNode = (*ColumnDefaultNode)(nil)
+	_ SqlNode = (*AddConstraintNode)(nil)
+	_ SqlNode = (*DropConstraintNode)(nil)
+	_ SqlNode = (*RenameTableNode)(nil)
+)
+
+// Render appends ADD COLUMN followed by the column definition.
+func (self *AddColumnNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("ADD COLUMN ")
+	return visitor.Visit(self.Column, visitor)
+}
+
+// Render appends DROP COLUMN "name".
+func (self *DropColumnNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("DROP COLUMN ")
+	return visitor.QuoteColumnName(self.Name, visitor)
+}
+
+// Render appends RENAME COLUMN "name" TO "new_name".
+func (self *RenameColumnNode) Render(visitor VisitorInterface) (err error) {
+	visitor.AppendSqlStr("RENAME COLUMN ")
+	if err = visitor.QuoteColumnName(self.Name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr(" TO ")
+	return visitor.QuoteColumnName(self.NewName, visitor)
+}
+
+// Render appends ALTER COLUMN "name" SET DEFAULT value or DROP DEFAULT.
+func (self *ColumnDefaultNode) Render(visitor VisitorInterface) (err error) {
+	visitor.AppendSqlStr("ALTER COLUMN ")
+	if err = visitor.QuoteColumnName(self.Name, visitor); err != nil {
+		return
+	}
+	if self.Default == nil {
+		visitor.AppendSqlStr(" DROP DEFAULT")
+		return
+	}
+	visitor.AppendSqlStr(" SET DEFAULT ")
+	return visitor.Visit(self.Default, visitor)
+}
+
+// Render appends ADD followed by the constraint.
+func (self *AddConstraintNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("ADD ")
+	return visitor.Visit(self.Constraint, visitor)
+}
+
+// Render appends DROP CONSTRAINT "name".
+func (self *DropConstraintNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("DROP CONSTRAINT ")
+	return visitor.QuoteColumnName(self.Name, visitor)
+}
+
+// Render appends RENAME TO "new_name".
+func (self *RenameTableNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("RENAME TO ")
+	return visitor.QuoteTableName(self.NewName, visitor)
+}
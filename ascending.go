@@ -3,6 +3,8 @@ package codex
 // AscendingNode is a UnaryNode struct.
 type AscendingNode UnaryNode
 
+var _ SqlNode = (*AscendingNode)(nil)
+
 // Returns a Grouping node with an expression containing a
 // reference to an Or node of the Ascending and other.
 func (self *AscendingNode) Or(other interface{}) *GroupingNode {
@@ -21,6 +23,13 @@ func (self *AscendingNode) Not() *NotNode {
 	return Not(self)
 }
 
+// Render appends the expression followed by ASC.
+func (self *AscendingNode) Render(visitor VisitorInterface) (err error) {
+	err = visitor.Visit(self.Expr, visitor)
+	visitor.AppendSqlStr(" ASC")
+	return
+}
+
 // AscendingNode factory method.
 func Ascending(expr interface{}) (ascending *AscendingNode) {
 	ascending = new(AscendingNode)
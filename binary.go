@@ -12,8 +12,11 @@ type InnerJoinNode BinaryNode     // InnerJoinNode is a BinaryNode struct.
 type OuterJoinNode BinaryNode     // OuterJoinNode is a BinaryNode struct.
 type AssignmentNode BinaryNode    // AssignmentNode is a BinaryNode struct.
 type UnionNode BinaryNode         // UnionNode is a BinaryNode struct.
+type UnionAllNode BinaryNode      // UnionAllNode is a BinaryNode struct.
 type IntersectNode BinaryNode     // IntersectNode is a BinaryNode struct.
+type IntersectAllNode BinaryNode  // IntersectAllNode is a BinaryNode struct.
 type ExceptNode BinaryNode        // ExceptNode is a BinaryNode struct.
+type ExceptAllNode BinaryNode     // ExceptAllNode is a BinaryNode struct.
 type BinaryLiteralNode BinaryNode // see AttributeNode.Literal() and table_test.go TestTableColLiteral()
 
 // AsNode factory method.
@@ -57,6 +60,8 @@ func Assignment(left, right interface{}) (assignment *AssignmentNode) {
 }
 
 // UnionNode factory method.
+// Left may be nil if the node is part of a SelectStatementNode's Combinators,
+// the left operand then is the preceding part of the statement.
 func Union(left, right interface{}) (union *UnionNode) {
 	union = new(UnionNode)
 	union.Left = left
@@ -64,6 +69,14 @@ func Union(left, right interface{}) (union *UnionNode) {
 	return
 }
 
+// UnionAllNode factory method.
+func UnionAll(left, right interface{}) (union *UnionAllNode) {
+	union = new(UnionAllNode)
+	union.Left = left
+	union.Right = right
+	return
+}
+
 // IntersectNode factory method.
 func Intersect(left, right interface{}) (intersect *IntersectNode) {
 	intersect = new(IntersectNode)
@@ -72,6 +85,14 @@ func Intersect(left, right interface{}) (intersect *IntersectNode) {
 	return
 }
 
+// IntersectAllNode factory method.
+func IntersectAll(left, right interface{}) (intersect *IntersectAllNode) {
+	intersect = new(IntersectAllNode)
+	intersect.Left = left
+	intersect.Right = right
+	return
+}
+
 // ExceptNode factory method.
 func Except(left, right interface{}) (except *ExceptNode) {
 	except = new(ExceptNode)
@@ -79,3 +100,11 @@ func Except(left, right interface{}) (except *ExceptNode) {
 	except.Right = right
 	return
 }
+
+// ExceptAllNode factory method.
+func ExceptAll(left, right interface{}) (except *ExceptAllNode) {
+	except = new(ExceptAllNode)
+	except.Left = left
+	except.Right = right
+	return
+}
@@ -0,0 +1,356 @@
+package codex
+
+// CloneNode returns a deep copy of the AST node o.
+// TableNodes and argument values (anything not being a node) are shared
+// between the original and the copy, all other nodes, column definitions and slices are copied.
+func CloneNode(o interface{}) interface{} {
+	switch o := o.(type) {
+	case nil:
+		return nil
+
+	// Unary nodes.
+	case *GroupingNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *NotNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *OnNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *ColumnNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *StarNode:
+		n := *o
+		return &n
+	case *BindingNode:
+		n := *o
+		return &n
+	case *LimitNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *OffsetNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *HavingNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *AscendingNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *DescendingNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+	case *LiteralNode:
+		n := *o
+		n.Args = cloneSlice(o.Args)
+		return &n
+
+	// Binary nodes.
+	case *AsNode:
+		return (*AsNode)(cloneBinary((*BinaryNode)(o)))
+	case *BetweenNode:
+		return (*BetweenNode)(cloneBinary((*BinaryNode)(o)))
+	case *AssignmentNode:
+		return (*AssignmentNode)(cloneBinary((*BinaryNode)(o)))
+	case *EqualNode:
+		return (*EqualNode)(cloneBinary((*BinaryNode)(o)))
+	case *NotEqualNode:
+		return (*NotEqualNode)(cloneBinary((*BinaryNode)(o)))
+	case *GreaterThanNode:
+		return (*GreaterThanNode)(cloneBinary((*BinaryNode)(o)))
+	case *GreaterThanOrEqualNode:
+		return (*GreaterThanOrEqualNode)(cloneBinary((*BinaryNode)(o)))
+	case *LessThanNode:
+		return (*LessThanNode)(cloneBinary((*BinaryNode)(o)))
+	case *LessThanOrEqualNode:
+		return (*LessThanOrEqualNode)(cloneBinary((*BinaryNode)(o)))
+	case *InNode:
+		return (*InNode)(cloneBinary((*BinaryNode)(o)))
+	case *LikeNode:
+		return (*LikeNode)(cloneBinary((*BinaryNode)(o)))
+	case *UnlikeNode:
+		return (*UnlikeNode)(cloneBinary((*BinaryNode)(o)))
+	case *OrNode:
+		return (*OrNode)(cloneBinary((*BinaryNode)(o)))
+	case *AndNode:
+		return (*AndNode)(cloneBinary((*BinaryNode)(o)))
+	case *InnerJoinNode:
+		return (*InnerJoinNode)(cloneBinary((*BinaryNode)(o)))
+	case *OuterJoinNode:
+		return (*OuterJoinNode)(cloneBinary((*BinaryNode)(o)))
+	case *UnionNode:
+		return (*UnionNode)(cloneBinary((*BinaryNode)(o)))
+	case *UnionAllNode:
+		return (*UnionAllNode)(cloneBinary((*BinaryNode)(o)))
+	case *IntersectNode:
+		return (*IntersectNode)(cloneBinary((*BinaryNode)(o)))
+	case *IntersectAllNode:
+		return (*IntersectAllNode)(cloneBinary((*BinaryNode)(o)))
+	case *ExceptNode:
+		return (*ExceptNode)(cloneBinary((*BinaryNode)(o)))
+	case *ExceptAllNode:
+		return (*ExceptAllNode)(cloneBinary((*BinaryNode)(o)))
+	case *BinaryLiteralNode:
+		return (*BinaryLiteralNode)(cloneBinary((*BinaryNode)(o)))
+	case *BinaryNode:
+		return cloneBinary(o)
+
+	// Tables, columns and sources.
+	case *TableNode:
+		return o
+	case *AttributeNode:
+		n := *o
+		n.Name = CloneNode(o.Name)
+		return &n
+	case *JoinSourceNode:
+		n := *o
+		n.Left = CloneNode(o.Left)
+		n.Right = cloneSlice(o.Right)
+		return &n
+	case *ValuesNode:
+		n := *o
+		n.Expressions = cloneSlice(o.Expressions)
+		n.Columns = cloneSlice(o.Columns)
+		if o.Rows != nil {
+			n.Rows = make([][]interface{}, len(o.Rows))
+			for i, row := range o.Rows {
+				n.Rows[i] = cloneSlice(row)
+			}
+		}
+		return &n
+	case *DerivedTableNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr).(*SelectStatementNode)
+		return &n
+	case *ValuesTableNode:
+		n := *o
+		n.Rows = make([][]interface{}, len(o.Rows))
+		for i, row := range o.Rows {
+			n.Rows[i] = cloneSlice(row)
+		}
+		n.Columns = append([]string(nil), o.Columns...)
+		n.Types = append([]string(nil), o.Types...)
+		return &n
+	case *FunctionNode:
+		n := *o
+		n.Args = cloneSlice(o.Args)
+		n.Alias = CloneNode(o.Alias)
+		return &n
+
+	// Statements.
+	case *SelectStatementNode:
+		return cloneSelectStatement(o)
+	case *InsertStatementNode:
+		return cloneInsertStatement(o)
+	case *UpdateStatementNode:
+		return cloneUpdateStatement(o)
+	case *DeleteStatementNode:
+		return cloneDeleteStatement(o)
+	case *SelectManager:
+		return o.Clone()
+
+	// DDL statements, column definitions, actions and constraints.
+	case *CreateTableStatementNode:
+		n := cloneCreateTableStatement(o)
+		for i, column := range n.Columns {
+			n.Columns[i] = cloneColumnDef(column)
+		}
+		return n
+	case *AlterTableStatementNode:
+		return cloneAlterTableStatement(o)
+	case *CreateIndexStatementNode:
+		return cloneCreateIndexStatement(o)
+	case *DropTableStatementNode:
+		n := *o
+		n.Tables = append([]*TableNode(nil), o.Tables...)
+		return &n
+	case *DropIndexStatementNode:
+		n := *o
+		return &n
+	case *ColumnDef:
+		return cloneColumnDef(o)
+	case *AddColumnNode:
+		return &AddColumnNode{Column: cloneColumnDef(o.Column)}
+	case *AlterColumnNode:
+		return &AlterColumnNode{Column: cloneColumnDef(o.Column), From: cloneColumnDef(o.From)}
+	case *ColumnDefaultNode:
+		n := *o
+		n.Default = CloneNode(o.Default)
+		return &n
+	case *AddConstraintNode:
+		return &AddConstraintNode{Constraint: CloneNode(o.Constraint)}
+	case *DropColumnNode:
+		n := *o
+		return &n
+	case *RenameColumnNode:
+		n := *o
+		return &n
+	case *DropConstraintNode:
+		n := *o
+		return &n
+	case *RenameTableNode:
+		n := *o
+		return &n
+	case *PrimaryKeyNode:
+		n := *o
+		n.Columns = append([]string(nil), o.Columns...)
+		return &n
+	case *UniqueNode:
+		n := *o
+		n.Columns = append([]string(nil), o.Columns...)
+		return &n
+	case *ForeignKeyNode:
+		n := *o
+		n.Columns = append([]string(nil), o.Columns...)
+		n.RefColumns = append([]string(nil), o.RefColumns...)
+		return &n
+	case *CheckNode:
+		n := *o
+		n.Expr = CloneNode(o.Expr)
+		return &n
+
+	case []interface{}:
+		return cloneSlice(o)
+
+	// Argument values.
+	default:
+		return o
+	}
+}
+
+func cloneBinary(o *BinaryNode) *BinaryNode {
+	return &BinaryNode{
+		Left:  CloneNode(o.Left),
+		Right: CloneNode(o.Right),
+	}
+}
+
+// cloneSlice deep copies a slice of nodes, nil stays nil.
+func cloneSlice(s []interface{}) []interface{} {
+	if s == nil {
+		return nil
+	}
+	c := make([]interface{}, len(s))
+	for i, o := range s {
+		c[i] = CloneNode(o)
+	}
+	return c
+}
+
+func cloneSelectStatement(o *SelectStatementNode) *SelectStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	if o.Source != nil {
+		n.Source = CloneNode(o.Source).(*JoinSourceNode)
+	}
+	n.Cols = cloneSlice(o.Cols)
+	n.Wheres = cloneSlice(o.Wheres)
+	n.Groups = cloneSlice(o.Groups)
+	n.Having = CloneNode(o.Having)
+	n.Orders = cloneSlice(o.Orders)
+	n.Combinators = cloneSlice(o.Combinators)
+	n.Limit = cloneLimit(o.Limit)
+	if o.Offset != nil {
+		n.Offset = CloneNode(o.Offset).(*OffsetNode)
+	}
+	return &n
+}
+
+func cloneInsertStatement(o *InsertStatementNode) *InsertStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Columns = cloneSlice(o.Columns)
+	n.Returning = CloneNode(o.Returning)
+	if o.Values != nil {
+		n.Values = CloneNode(o.Values).(*ValuesNode)
+	}
+	return &n
+}
+
+func cloneUpdateStatement(o *UpdateStatementNode) *UpdateStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Values = cloneSlice(o.Values)
+	n.Froms = cloneSlice(o.Froms)
+	n.Wheres = cloneSlice(o.Wheres)
+	n.Limit = cloneLimit(o.Limit)
+	return &n
+}
+
+func cloneDeleteStatement(o *DeleteStatementNode) *DeleteStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Wheres = cloneSlice(o.Wheres)
+	n.Limit = cloneLimit(o.Limit)
+	return &n
+}
+
+func cloneCreateTableStatement(o *CreateTableStatementNode) *CreateTableStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Columns = append([]*ColumnDef(nil), o.Columns...)
+	n.Constraints = cloneSlice(o.Constraints)
+	return &n
+}
+
+func cloneAlterTableStatement(o *AlterTableStatementNode) *AlterTableStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Actions = cloneSlice(o.Actions)
+	return &n
+}
+
+func cloneCreateIndexStatement(o *CreateIndexStatementNode) *CreateIndexStatementNode {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Columns = cloneSlice(o.Columns)
+	n.Wheres = cloneSlice(o.Wheres)
+	return &n
+}
+
+// cloneColumnDef deep copies a column definition, nil stays nil.
+func cloneColumnDef(o *ColumnDef) *ColumnDef {
+	if o == nil {
+		return nil
+	}
+	n := *o
+	n.Default = CloneNode(o.Default)
+	if o.References != nil {
+		ref := *o.References
+		n.References = &ref
+	}
+	return &n
+}
+
+// cloneLimit deep copies a LimitNode, nil stays nil.
+func cloneLimit(o *LimitNode) *LimitNode {
+	if o == nil {
+		return nil
+	}
+	return CloneNode(o).(*LimitNode)
+}
@@ -0,0 +1,42 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCloneNodeBinary(t *testing.T) {
+	users := Table("users")
+	eq := users.Col("id").Eq(1)
+
+	c := CloneNode(eq).(*EqualNode)
+	assert.Equal(t, eq, c)
+	assert.False(t, eq == c)
+	assert.False(t, eq.Left == c.Left)
+	// tables are shared
+	assert.True(t, eq.Left.(*AttributeNode).Table == c.Left.(*AttributeNode).Table)
+}
+
+func TestCloneNodeLiteralArgs(t *testing.T) {
+	l := Literal("id IN(?...)", 1, 2)
+	c := CloneNode(l).(*LiteralNode)
+	c.Args[0] = 3
+	assert.Equal(t, []interface{}{1, 2}, l.Args)
+}
+
+func TestCloneNodeNil(t *testing.T) {
+	assert.Nil(t, CloneNode(nil))
+	assert.Equal(t, 1, CloneNode(1))
+}
+
+func TestCloneNodeSelectStatement(t *testing.T) {
+	users := Table("users")
+	stm := users.Where(users.Col("id").In(1, 2)).Order(users.Col("id").Desc()).Limit(3).Tree
+
+	c := CloneNode(stm).(*SelectStatementNode)
+	assert.Equal(t, stm, c)
+	c.Wheres = append(c.Wheres, Literal("x"))
+	c.Limit.Expr = 5
+	assert.Len(t, stm.Wheres, 1)
+	assert.Equal(t, 3, stm.Limit.Expr)
+}
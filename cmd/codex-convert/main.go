@@ -0,0 +1,46 @@
+// codex-convert converts a SQL statement to Go code building it with codex:
+//
+//	codex-convert -dialect postgres "SELECT id, name FROM users WHERE id = \$1"
+//
+// or reading the statement from stdin:
+//
+//	codex-convert -dialect mysql < query.sql
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/janmentzel/codex/gen"
+)
+
+func main() {
+	dialect := flag.String("dialect", "postgres", "dialect of the statement: postgres or mysql")
+	defaultSchema := flag.String("default-schema", "public", "tables of this schema are not qualified")
+	flag.Parse()
+
+	if err := run(flag.Args(), *dialect, *defaultSchema); err != nil {
+		fmt.Fprintln(os.Stderr, "codex-convert:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, dialect, defaultSchema string) error {
+	sql := strings.Join(args, " ")
+	if len(args) == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		sql = string(b)
+	}
+	src, err := gen.Convert(sql, gen.ConvertOptions{Dialect: strings.ToUpper(dialect), DefaultSchema: defaultSchema})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(src)
+	return err
+}
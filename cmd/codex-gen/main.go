@@ -0,0 +1,52 @@
+// codex-gen generates Go table accessors and row structs from a schema file,
+// a SQL DDL file or a JSON/YAML export of information_schema:
+//
+//	codex-gen -in schema.sql -dialect postgres -package models -out models/tables_gen.go
+//
+// or with go generate:
+//
+//	//go:generate go run github.com/janmentzel/codex/cmd/codex-gen -in schema.sql -package models -out tables_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/janmentzel/codex/gen"
+)
+
+func main() {
+	in := flag.String("in", "", "schema file: .sql DDL, .json or .yaml information_schema export")
+	format := flag.String("format", "", "format of the schema file: ddl, json or yaml (default: by file extension)")
+	dialect := flag.String("dialect", "postgres", "dialect of the tables: postgres or mysql")
+	pkg := flag.String("package", "models", "package name of the generated file")
+	defaultSchema := flag.String("default-schema", "public", "tables of this schema are not qualified")
+	out := flag.String("out", "", "output file (default: stdout)")
+	flag.Parse()
+
+	if err := run(*in, *format, *dialect, *pkg, *defaultSchema, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "codex-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, format, dialect, pkg, defaultSchema, out string) error {
+	if in == "" {
+		return fmt.Errorf("missing -in schema file")
+	}
+	schema, err := gen.LoadFile(in, format)
+	if err != nil {
+		return err
+	}
+	src, err := gen.Generate(schema, gen.Options{Package: pkg, Dialect: strings.ToUpper(dialect), DefaultSchema: defaultSchema})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0644)
+}
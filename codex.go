@@ -12,23 +12,28 @@ const (
 	POSTGRES
 )
 
+// strictAdapter flags the adapters of strict dialects, see DbDialect.Strict.
+const strictAdapter adapter = 0x80
+
+// dialect returns the adapter without flags e.g. POSTGRES for the adapter of Dialect(POSTGRES).Strict().
+func (a adapter) dialect() adapter {
+	return a &^ strictAdapter
+}
+
+// VALID_COL_NAME_PATTERN and VALID_TABLE_NAME_PATTERN are applied by strict visitors only,
+// see DbDialect.Strict.
 var VALID_COL_NAME_PATTERN *regexp.Regexp
 var VALID_TABLE_NAME_PATTERN *regexp.Regexp
 
 func init() {
 	var err error
-	VALID_COL_NAME_PATTERN, err = regexp.Compile(`(?i)^[a-z][a-z0-9_\$]*$`)
+	VALID_COL_NAME_PATTERN, err = regexp.Compile(`(?i)^[a-z_][a-z0-9_\$]*$`)
 	if err != nil {
 		panic(err)
 	}
 	VALID_TABLE_NAME_PATTERN = VALID_COL_NAME_PATTERN
 }
 
-// ToggleDebugMode toggles debugger variable for managers package.
-func ToggleDebugMode() {
-	DEBUG = !DEBUG
-}
-
 type DbDialect func(string) *AttributeNode
 
 func (db DbDialect) Table(name string) *TableNode {
@@ -44,6 +49,44 @@ func Dialect(adapter adapter) DbDialect {
 	}
 }
 
+// InSchema returns a DbDialect whose tables belong to `schema` by default,
+// similar to postgres' search_path. Tables can still be moved with TableNode.InSchema().
+//
+//	psql := Dialect(POSTGRES).InSchema("analytics")
+//	psql.Table("events").Select("id") // SELECT "analytics"."events"."id" FROM "analytics"."events"
+func (db DbDialect) InSchema(schema string) DbDialect {
+	return func(tableName string) *AttributeNode {
+		attr := db(tableName)
+		if attr.Table.Schema == "" {
+			attr.Table.Schema = schema
+		}
+		return attr
+	}
+}
+
+// Strict returns a DbDialect whose managers reject table and column names not matching
+// VALID_TABLE_NAME_PATTERN / VALID_COL_NAME_PATTERN and reserved words of the dialect, see IsReserved.
+// ToSql returns an *IdentifierError for those.
+//
+//	mysql := Dialect(MYSQL).Strict()
+//	mysql.Table("users").Select("key").ToSql() // reserved word as column name: 'key'
+func (db DbDialect) Strict() DbDialect {
+	return func(tableName string) *AttributeNode {
+		attr := db(tableName)
+		attr.Table.Adapter |= strictAdapter
+		return attr
+	}
+}
+
+// Hooks returns a DbDialect whose tables have `hooks`, see Hook.
+func (db DbDialect) Hooks(hooks ...Hook) DbDialect {
+	return func(tableName string) *AttributeNode {
+		attr := db(tableName)
+		attr.Table.Hooks(hooks...)
+		return attr
+	}
+}
+
 // // deprecated
 // // Table returns an Accessor from the managers package for
 // // generating SQL to interact with existing tables.
@@ -66,3 +109,18 @@ type Scoper interface {
 
 // ScopeFunc is implemented by DB layer 'models'
 type ScopeFunc func(Scoper)
+
+// whereExpr converts the arguments of the managers' Where methods to a condition node
+//
+//	whereExpr("a = ?", 123)                    // Group(Literal("a = ?", 123))
+//	whereExpr(Equal(Column("a"), Column("b"))) // Group(Equal(Column("a"), Column("b")))
+func whereExpr(expr interface{}, args ...interface{}) interface{} {
+	if str, ok := expr.(string); ok {
+		expr = Literal(str, args...)
+	}
+	// enclose expr in Grouping - except if expr is already a Grouping
+	if _, ok := expr.(*GroupingNode); !ok {
+		expr = Grouping(expr)
+	}
+	return expr
+}
@@ -26,3 +26,30 @@ func TestCodexDialectMysql(t *testing.T) {
 	assert.Equal(t, "SELECT `users`.* FROM `users` WHERE (id = ?)", sql)
 	assert.Equal(t, []interface{}{2}, args)
 }
+
+func TestCodexDialectInSchemaPostgres(t *testing.T) {
+	psql := Dialect(POSTGRES).InSchema("analytics")
+	events := psql.Table("events")
+	q := events.Insert(1).Into("id")
+
+	sql, args, err := q.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "analytics"."events" ("id") VALUES ($1)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	other := psql.Table("users").InSchema("public")
+	sql, _, err = other.Selection().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "public"."users".* FROM "public"."users"`, sql)
+}
+
+func TestCodexDialectInSchemaMysql(t *testing.T) {
+	mysql := Dialect(MYSQL).InSchema("shop")
+	users := mysql.Table("users")
+	q := users.Set("name").To("Jon").Where(users.Col("id").Eq(2))
+
+	sql, args, err := q.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE `shop`.`users` SET `name`=? WHERE (`shop`.`users`.`id`=?)", sql)
+	assert.Equal(t, []interface{}{"Jon", 2}, args)
+}
@@ -60,34 +60,36 @@ type PostgresCollector struct {
 
 var _ CollectorInterface = (*PostgresCollector)(nil)
 
+// AppendSqlStr appends s replacing the ? placeholders by $1, $2 ... $n.
+// Question marks within quoted strings, quoted identifiers and comments are kept.
+// "??" is appended as literal question mark e.g. the JSONB operator,
+// the JSONB operators "?|" and "?&" are kept as well, see questionAt.
 func (c *PostgresCollector) AppendSqlStr(s string) {
-	if strings.ContainsRune(s, QUESTION) {
-
-		// pregrow buffer to avoid iterating reallocation
-		n := strings.Count(s, string(QUESTION))
-
-		factor := 1
-		if c.iArg+n > 9 {
-			factor++
-		}
-		if c.iArg+n > 99 {
-			factor++
-		}
-
-		c.sqlBuf.Grow(len(s) + n*factor)
-
-		for _, r := range s {
-			if r == QUESTION {
-				c.iArg++
-				c.sqlBuf.WriteRune('$')
-				c.sqlBuf.WriteString(strconv.Itoa(c.iArg))
-			} else {
-				c.sqlBuf.WriteRune(r)
-			}
-		}
-	} else {
+	if !strings.ContainsRune(s, QUESTION) {
 		c.sqlBuf.WriteString(s)
+		return
+	}
+
+	// pregrow buffer to avoid iterating reallocation
+	n := strings.Count(s, string(QUESTION))
+
+	factor := 1
+	if c.iArg+n > 9 {
+		factor++
+	}
+	if c.iArg+n > 99 {
+		factor++
 	}
+
+	c.sqlBuf.Grow(len(s) + n*factor)
+
+	scanPlaceholders(s, true, func(text string) {
+		c.sqlBuf.WriteString(text)
+	}, func() {
+		c.iArg++
+		c.sqlBuf.WriteByte('$')
+		c.sqlBuf.WriteString(strconv.Itoa(c.iArg))
+	})
 }
 
 func (c *PostgresCollector) AppendSqlByte(b byte) {
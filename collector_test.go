@@ -2,6 +2,8 @@ package codex
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -100,3 +102,93 @@ func TestPostgresCollectorAppendSqlStrWithoutPlaceholder(t *testing.T) {
 	assert.Equal(t, "WHERE id=foo_id AND bar=bla", c.String())
 	assert.Empty(t, c.Args())
 }
+
+func TestPostgresCollectorAppendSqlStrKeepsQuotedQuestionMarks(t *testing.T) {
+	for sql, expected := range map[string]string{
+		`a = ? AND b = '?' AND "c?" = ?`:           `a = $1 AND b = '?' AND "c?" = $2`,
+		`a = 'it''s ?' AND b = ?`:                  `a = 'it''s ?' AND b = $1`,
+		`a = E'it\'s ?' AND b = ?`:                 `a = E'it\'s ?' AND b = $1`,
+		`a = $$ ? $$ AND b = $x$ ?$$? $x$ AND c=?`: `a = $$ ? $$ AND b = $x$ ?$$? $x$ AND c=$1`,
+		"a = ? -- b = ?\nAND c = ?":                "a = $1 -- b = ?\nAND c = $2",
+		`a = ? /* b = ? /* ? */ ? */ AND c = ?`:    `a = $1 /* b = ? /* ? */ ? */ AND c = $2`,
+		`data ?? 'key' AND a = ?`:                  `data ? 'key' AND a = $1`,
+		`data ?| array['a'] AND data ?& ?`:         `data ?| array['a'] AND data ?& $1`,
+		`a = ?||'x'`:                               `a = $1||'x'`,
+		`a = 'unterminated ?`:                      `a = 'unterminated ?`,
+	} {
+		c := NewPostgresCollector()
+		c.AppendSqlStr(sql)
+		assert.Equal(t, expected, c.String(), sql)
+	}
+}
+
+func TestPostgresCollectorQuotedStateDoesNotLeak(t *testing.T) {
+	c := NewPostgresCollector()
+	c.AppendSqlStr(`a = 'unterminated ?`)
+	c.AppendSqlByte(COMMA)
+	c.AppendSqlStr(`?`)
+	c.AppendSqlByte(QUESTION)
+	assert.Equal(t, `a = 'unterminated ?,$1$2`, c.String())
+}
+
+func TestPostgresCollectorLiteral(t *testing.T) {
+	products := Dialect(POSTGRES).Table("products")
+	sql, args, err := products.Where(`attrs ?? 'color' AND attrs ?| array['a','b?'] AND name <> '?' AND id IN(?...)`, 1, 2).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "products".* FROM "products" WHERE (attrs ? 'color' AND attrs ?| array['a','b?'] AND name <> '?' AND id IN($1,$2))`, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func FuzzPostgresCollectorPlaceholders(f *testing.F) {
+	for _, seed := range []string{
+		`a = ?`,
+		`a = ? AND b = '?' AND "c?" = ?`,
+		`a = E'it\'s ?' AND b = ?`,
+		`$$ ? $$ ? $x$ ? $x$`,
+		"-- ?\n?",
+		`/* ? /* ? */ */ ?`,
+		`data ?? 'key' AND data ?| ? AND data ?& ?`,
+		`id IN(?...) AND x = :name`,
+		`?||?&&?`,
+		`'unterminated ?`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, sql string) {
+		n := 0
+		for _, p := range scanLiteralParams(sql) {
+			if p.name == "" {
+				n++
+			}
+		}
+		args := make([]interface{}, n)
+		for i := range args {
+			args[i] = i
+		}
+
+		literal := Literal(sql, args...)
+		if literal.Err != nil {
+			t.Fatalf("Literal(%q) error: %v", sql, literal.Err)
+		}
+
+		c := NewPostgresCollector()
+		v := NewToSqlVisitor(c)
+		out, outArgs, err := v.Accept(literal)
+		if err != nil {
+			t.Fatalf("Accept(%q) error: %v", sql, err)
+		}
+		if len(outArgs) != n || c.iArg != n {
+			t.Fatalf("%q renders %q with %d placeholders for %d args", sql, out, c.iArg, len(outArgs))
+		}
+		// placeholders are numbered in order
+		rest := out
+		for i := 1; i <= n; i++ {
+			k := strings.Index(rest, "$"+strconv.Itoa(i))
+			if k < 0 {
+				t.Fatalf("%q renders %q without $%d", sql, out, i)
+			}
+			rest = rest[k+1:]
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+// run with `go test -race` to detect data races while rendering shared trees
+
+const concurrentRenderings = 32
+
+type toSqler interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// assertConcurrentToSql renders m repeatedly and concurrently
+// and expects the result of the first rendering every time.
+func assertConcurrentToSql(t *testing.T, m toSqler) {
+	sql, args, err := m.ToSql()
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentRenderings; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, a, e := m.ToSql()
+			assert.Nil(t, e)
+			assert.Equal(t, sql, s)
+			assert.Equal(t, args, a)
+		}()
+	}
+	wg.Wait()
+
+	s, a, e := m.ToSql()
+	assert.Nil(t, e)
+	assert.Equal(t, sql, s)
+	assert.Equal(t, args, a)
+}
+
+func TestConcurrentToSqlSelectDefaultStar(t *testing.T) {
+	users := Table("users")
+	m := users.Where(users.Col("id").Gt(1))
+
+	assertConcurrentToSql(t, m)
+	assert.Empty(t, m.Tree.Cols)
+}
+
+func TestConcurrentToSqlSelectCombined(t *testing.T) {
+	for _, adapter := range []adapter{0, MYSQL, POSTGRES} {
+		db := Dialect(adapter)
+		users := db.Table("users")
+		admins := db.Table("admins")
+		orders := db.Table("orders")
+		sub := From(orders.Select(orders.Col("user_id")).Where(orders.Col("total").Gt(10)), "o")
+
+		m := users.Select(users.Col("id")).
+			InnerJoin(sub).On(sub.Col("user_id").Eq(users.Col("id"))).
+			Where("users.name LIKE ?", "a%").
+			Union(admins.Select(admins.Col("id")).Where(admins.Col("level").In(1, 2, 3))).
+			UnionAll(admins.Select(admins.Col("id")).Limit(1)).
+			Order("id").Limit(10).Offset(5)
+
+		assertConcurrentToSql(t, m)
+		assert.Len(t, m.Tree.Combinators, 2)
+	}
+}
+
+func TestConcurrentToSqlInsertUpdateDelete(t *testing.T) {
+	for _, adapter := range []adapter{0, MYSQL, POSTGRES} {
+		db := Dialect(adapter)
+		users := db.Table("users")
+		v := ValuesTable("v", "id", "name").Row(1, "a").Row(2, "b")
+
+		ins := users.Insert(1, "a").Into("id", "name")
+		if adapter != MYSQL { // MySQL does not support RETURNING
+			ins.Returning("id")
+		}
+		assertConcurrentToSql(t, ins)
+		assertConcurrentToSql(t, users.Set("name").To(v.Col("name")).From(v).Where(users.Col("id").Eq(v.Col("id"))))
+		assertConcurrentToSql(t, users.Delete(users.Col("id").In(1, 2)).Limit(1))
+	}
+}
+
+func TestConcurrentToSqlSharedTemplate(t *testing.T) {
+	users := Table("users")
+	template := users.Where(users.Col("active").Eq(true)).Immutable()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentRenderings; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			sql, args, err := template.Where(users.Col("id").Eq(id)).ToSql()
+			assert.Nil(t, err)
+			assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."active"=?) AND ("users"."id"=?)`, sql)
+			assert.Equal(t, []interface{}{true, id}, args)
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, template.Tree.Wheres, 1)
+}
@@ -0,0 +1,219 @@
+package codex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrimaryKeyNode is a PRIMARY KEY table constraint, see CreateTableManager.Constraints.
+type PrimaryKeyNode struct {
+	Name    string // optional constraint name
+	Columns []string
+}
+
+// UniqueNode is a UNIQUE table constraint.
+type UniqueNode struct {
+	Name    string // optional constraint name
+	Columns []string
+}
+
+// ForeignKeyNode is a FOREIGN KEY table constraint.
+type ForeignKeyNode struct {
+	Name         string // optional constraint name
+	Columns      []string
+	Table        *TableNode // referenced table
+	RefColumns   []string   // referenced columns, empty for the primary key of Table
+	DeleteAction string     // ON DELETE e.g. "CASCADE", empty for the database default
+	UpdateAction string     // ON UPDATE e.g. "CASCADE", empty for the database default
+}
+
+// CheckNode is a CHECK table constraint.
+type CheckNode struct {
+	Name string // optional constraint name
+	Expr interface{}
+}
+
+var (
+	_ SqlNode = (*PrimaryKeyNode)(nil)
+	_ SqlNode = (*UniqueNode)(nil)
+	_ SqlNode = (*ForeignKeyNode)(nil)
+	_ SqlNode = (*CheckNode)(nil)
+)
+
+// PrimaryKeyNode factory method.
+func PrimaryKey(columns ...string) *PrimaryKeyNode {
+	return &PrimaryKeyNode{Columns: columns}
+}
+
+// Named sets the name of the constraint.
+func (self *PrimaryKeyNode) Named(name string) *PrimaryKeyNode {
+	self.Name = name
+	return self
+}
+
+// Render appends [CONSTRAINT "name" ]PRIMARY KEY ("col1","col2").
+func (self *PrimaryKeyNode) Render(visitor VisitorInterface) (err error) {
+	if err = visitConstraintName(self.Name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr("PRIMARY KEY ")
+	return visitColumnList(self.Columns, visitor)
+}
+
+// UniqueNode factory method.
+func Unique(columns ...string) *UniqueNode {
+	return &UniqueNode{Columns: columns}
+}
+
+// Named sets the name of the constraint.
+func (self *UniqueNode) Named(name string) *UniqueNode {
+	self.Name = name
+	return self
+}
+
+// Render appends [CONSTRAINT "name" ]UNIQUE ("col1","col2").
+func (self *UniqueNode) Render(visitor VisitorInterface) (err error) {
+	if err = visitConstraintName(self.Name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr("UNIQUE ")
+	return visitColumnList(self.Columns, visitor)
+}
+
+// Foreign returns a FOREIGN KEY constraint of the columns, see References.
+//
+//	Foreign("user_id").References(users, "id").OnDelete("CASCADE")
+func Foreign(columns ...string) *ForeignKeyNode {
+	return &ForeignKeyNode{Columns: columns}
+}
+
+// Named sets the name of the constraint.
+func (self *ForeignKeyNode) Named(name string) *ForeignKeyNode {
+	self.Name = name
+	return self
+}
+
+// References sets the referenced table and columns.
+func (self *ForeignKeyNode) References(table *TableNode, columns ...string) *ForeignKeyNode {
+	self.Table = table
+	self.RefColumns = columns
+	return self
+}
+
+// OnDelete sets the ON DELETE action e.g. "CASCADE" or "SET NULL".
+func (self *ForeignKeyNode) OnDelete(action string) *ForeignKeyNode {
+	self.DeleteAction = action
+	return self
+}
+
+// OnUpdate sets the ON UPDATE action e.g. "CASCADE".
+func (self *ForeignKeyNode) OnUpdate(action string) *ForeignKeyNode {
+	self.UpdateAction = action
+	return self
+}
+
+// Render appends [CONSTRAINT "name" ]FOREIGN KEY ("col") REFERENCES "table" ("ref")[ ON DELETE action][ ON UPDATE action].
+func (self *ForeignKeyNode) Render(visitor VisitorInterface) (err error) {
+	if self.Table == nil {
+		return fmt.Errorf("%w: FOREIGN KEY (%s) without referenced table", ErrInvalidSchema, strings.Join(self.Columns, ","))
+	}
+	if err = visitConstraintName(self.Name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr("FOREIGN KEY ")
+	if err = visitColumnList(self.Columns, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr(" REFERENCES ")
+	if err = visitor.Visit(self.Table, visitor); err != nil {
+		return
+	}
+	if 0 < len(self.RefColumns) {
+		visitor.AppendSqlByte(SPACE)
+		if err = visitColumnList(self.RefColumns, visitor); err != nil {
+			return
+		}
+	}
+	if err = visitReferentialAction("ON DELETE", self.DeleteAction, visitor); err != nil {
+		return
+	}
+	return visitReferentialAction("ON UPDATE", self.UpdateAction, visitor)
+}
+
+// Check returns a CHECK constraint, expr is handled like the expression of Where.
+//
+//	Check("price >= ?", 0)
+func Check(expr interface{}, args ...interface{}) *CheckNode {
+	return &CheckNode{Expr: whereExpr(expr, args...)}
+}
+
+// Named sets the name of the constraint.
+func (self *CheckNode) Named(name string) *CheckNode {
+	self.Name = name
+	return self
+}
+
+// Render appends [CONSTRAINT "name" ]CHECK (expr).
+func (self *CheckNode) Render(visitor VisitorInterface) (err error) {
+	if err = visitConstraintName(self.Name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr("CHECK ")
+	return visitor.Visit(whereExpr(self.Expr), visitor)
+}
+
+// visitConstraintName appends CONSTRAINT "name" followed by a space unless name is empty.
+func visitConstraintName(name string, visitor VisitorInterface) (err error) {
+	if name == "" {
+		return
+	}
+	visitor.AppendSqlStr("CONSTRAINT ")
+	if err = visitor.QuoteColumnName(name, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+	return
+}
+
+// visitColumnList appends the quoted column names enclosed in parentheses.
+func visitColumnList(columns []string, visitor VisitorInterface) (err error) {
+	if 0 == len(columns) {
+		return fmt.Errorf("%w: empty column list", ErrArgumentCount)
+	}
+	visitor.AppendSqlByte('(')
+	for index, column := range columns {
+		if index != 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		if err = visitor.QuoteColumnName(column, visitor); err != nil {
+			return
+		}
+	}
+	visitor.AppendSqlByte(')')
+	return
+}
+
+// referentialActions are the actions of ON DELETE and ON UPDATE.
+var referentialActions = map[string]bool{
+	"CASCADE":     true,
+	"RESTRICT":    true,
+	"NO ACTION":   true,
+	"SET NULL":    true,
+	"SET DEFAULT": true,
+}
+
+// visitReferentialAction appends e.g. " ON DELETE CASCADE" unless action is empty.
+func visitReferentialAction(clause, action string, visitor VisitorInterface) error {
+	if action == "" {
+		return nil
+	}
+	upper := strings.ToUpper(strings.Join(strings.Fields(action), " "))
+	if !referentialActions[upper] {
+		return fmt.Errorf("%w: %s '%s'", ErrInvalidLiteral, clause, action)
+	}
+	visitor.AppendSqlByte(SPACE)
+	visitor.AppendSqlStr(clause)
+	visitor.AppendSqlByte(SPACE)
+	visitor.AppendSqlStr(upper)
+	return nil
+}
@@ -0,0 +1,134 @@
+package codex
+
+// CreateIndexManager manages a tree that compiles to a SQL CREATE INDEX statement.
+//
+//	users.CreateIndex("users_email_idx", Function("lower", users.Col("email"))).
+//		Unique().
+//		Concurrently().
+//		Where(users.Col("deleted_at").Eq(nil)).
+//		ToSql()
+//	// CREATE UNIQUE INDEX CONCURRENTLY "users_email_idx" ON "users" ((lower("users"."email"))) WHERE ("users"."deleted_at" IS NULL)
+type CreateIndexManager struct {
+	Tree    *CreateIndexStatementNode // The AST for the SQL CREATE INDEX statement.
+	Adapter adapter                   // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
+}
+
+// Unique renders CREATE UNIQUE INDEX.
+func (self *CreateIndexManager) Unique() *CreateIndexManager {
+	self = self.chain()
+	self.Tree.Unique = true
+	return self
+}
+
+// Concurrently renders CREATE INDEX CONCURRENTLY, Postgres only.
+func (self *CreateIndexManager) Concurrently() *CreateIndexManager {
+	self = self.chain()
+	self.Tree.Concurrently = true
+	return self
+}
+
+// IfNotExists renders CREATE INDEX IF NOT EXISTS, Postgres only.
+func (self *CreateIndexManager) IfNotExists() *CreateIndexManager {
+	self = self.chain()
+	self.Tree.IfNotExists = true
+	return self
+}
+
+// Using sets the index method e.g. "gin" (Postgres) or "HASH" (MySQL).
+func (self *CreateIndexManager) Using(method string) *CreateIndexManager {
+	self = self.chain()
+	self.Tree.Using = method
+	return self
+}
+
+// On appends key parts: column names, attributes, Ascending/Descending nodes or expressions.
+//
+//	On("tenant_id", Descending(users.Col("created_at")), Function("lower", users.Col("email")))
+func (self *CreateIndexManager) On(columns ...interface{}) *CreateIndexManager {
+	self = self.chain()
+	self.Tree.Columns = append(self.Tree.Columns, columns...)
+	return self
+}
+
+// Where appends a condition of a partial index like SelectManager.Where, Postgres only.
+func (self *CreateIndexManager) Where(expr interface{}, args ...interface{}) *CreateIndexManager {
+	self = self.chain()
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
+	return self
+}
+
+// Hooks adds hooks called by ToSql, see Hook.
+func (self *CreateIndexManager) Hooks(hooks ...Hook) *CreateIndexManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a deep copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *CreateIndexManager) Clone() *CreateIndexManager {
+	m := *self
+	m.Tree = cloneCreateIndexStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+func (self *CreateIndexManager) Immutable() *CreateIndexManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *CreateIndexManager) chain() *CreateIndexManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the statement. ToSql returns it as well.
+func (self *CreateIndexManager) Err() error {
+	return self.err
+}
+
+// ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// Values of the conditions are inlined as literals, so no arguments are returned.
+func (self *CreateIndexManager) ToSql() (string, []interface{}, error) {
+	return renderDdl(self.hooks, "CREATE INDEX", self.Tree.Table, self.Adapter, self.err, self.Tree)
+}
+
+func (self *CreateIndexManager) Table() *TableNode {
+	return self.Tree.Table
+}
+
+// CreateIndexManager factory method, name may be empty for Postgres to let it choose one.
+func CreateIndex(relation *TableNode, name string, columns ...interface{}) (m *CreateIndexManager) {
+	m = new(CreateIndexManager)
+	m.Tree = CreateIndexStatement(name, relation)
+	m.Tree.Columns = columns
+	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
+	return
+}
+
+// CreateIndexes returns a CreateIndexManager per index of the table.
+func (t *TableDef) CreateIndexes() []*CreateIndexManager {
+	managers := make([]*CreateIndexManager, len(t.Indexes))
+	for i, index := range t.Indexes {
+		m := CreateIndex(t.Table, index.Name).On(index.Columns...).Using(index.Using)
+		if index.Unique {
+			m = m.Unique()
+		}
+		if index.Where != nil {
+			m = m.Where(index.Where)
+		}
+		managers[i] = m
+	}
+	return managers
+}
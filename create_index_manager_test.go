@@ -0,0 +1,61 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndexManager(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	sql, args, err := users.CreateIndex("users_tenant_created_idx", "tenant_id", Descending(users.Col("created_at"))).ToSql()
+	assert.Nil(t, err)
+	assert.Nil(t, args)
+	assert.Equal(t, `CREATE INDEX "users_tenant_created_idx" ON "users" ("tenant_id","created_at" DESC)`, sql)
+
+	sql, _, err = users.CreateIndex("users_email_idx").
+		On(Function("lower", users.Col("email"))).
+		Unique().
+		Concurrently().
+		IfNotExists().
+		Where(users.Col("deleted_at").Eq(nil)).
+		Where("role <> ?", "bot").
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "users_email_idx" ON "users" ((lower("users"."email"))) `+
+		`WHERE ("users"."deleted_at" IS NULL) AND (role <> 'bot')`, sql)
+
+	sql, _, err = users.CreateIndex("", "tags").Using("gin").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE INDEX ON "users" USING gin ("tags")`, sql)
+}
+
+func TestCreateIndexManagerMySql(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+
+	sql, _, err := users.CreateIndex("users_email_idx", users.Col("email")).Unique().Using("BTREE").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "CREATE UNIQUE INDEX `users_email_idx` USING BTREE ON `users` (`email`)", sql)
+
+	for _, mgr := range []*CreateIndexManager{
+		users.CreateIndex("idx", "email").Concurrently(),
+		users.CreateIndex("idx", "email").IfNotExists(),
+		users.CreateIndex("idx", "email").Where("deleted_at IS NULL"),
+		users.CreateIndex("", "email"),
+	} {
+		_, _, err = mgr.ToSql()
+		assert.True(t, errors.Is(err, ErrUnsupportedByDialect))
+	}
+}
+
+func TestCreateIndexManagerErrors(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	_, _, err := users.CreateIndex("idx").ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, _, err = users.CreateIndex("idx", "email").Using("gin; DROP TABLE users").ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidLiteral))
+}
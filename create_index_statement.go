@@ -0,0 +1,21 @@
+package codex
+
+// CreateIndexStatementNode is the base node for SQL CREATE INDEX statements.
+type CreateIndexStatementNode struct {
+	Name         string        // Name of the index, may be empty for Postgres.
+	Table        *TableNode    // Pointer to the indexed Table.
+	Unique       bool          // CREATE UNIQUE INDEX
+	Concurrently bool          // CREATE INDEX CONCURRENTLY, Postgres only
+	IfNotExists  bool          // CREATE INDEX IF NOT EXISTS, Postgres only
+	Using        string        // Index method e.g. "gin" or "BTREE", empty for the default.
+	Columns      []interface{} // Column names, *AttributeNodes, Ascending/Descending nodes or expressions.
+	Wheres       []interface{} // Conditions of a partial index, Postgres only.
+}
+
+// CreateIndexStatementNode factory method.
+func CreateIndexStatement(name string, relation *TableNode) (statement *CreateIndexStatementNode) {
+	statement = new(CreateIndexStatementNode)
+	statement.Name = name
+	statement.Table = relation
+	return
+}
@@ -0,0 +1,127 @@
+package codex
+
+// CreateTableManager manages a tree that compiles to a SQL CREATE TABLE statement.
+//
+//	users := Dialect(POSTGRES).Table("users")
+//	users.CreateTable().IfNotExists().Columns(
+//		&ColumnDef{Name: "id", Type: "bigint", Identity: true, PrimaryKey: true},
+//		&ColumnDef{Name: "email", Type: "varchar(255)", Unique: true},
+//	).ToSql()
+//	// CREATE TABLE IF NOT EXISTS "users" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,"email" varchar(255) NOT NULL UNIQUE)
+type CreateTableManager struct {
+	Tree    *CreateTableStatementNode // The AST for the SQL CREATE TABLE statement.
+	Adapter adapter                   // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
+}
+
+// IfNotExists renders CREATE TABLE IF NOT EXISTS.
+func (self *CreateTableManager) IfNotExists() *CreateTableManager {
+	self = self.chain()
+	self.Tree.IfNotExists = true
+	return self
+}
+
+// Columns appends column definitions. Several PrimaryKey columns render a composite PRIMARY KEY constraint.
+func (self *CreateTableManager) Columns(columns ...*ColumnDef) *CreateTableManager {
+	self = self.chain()
+	for _, column := range columns {
+		if column == nil {
+			if self.err == nil {
+				self.err = unexpectedType("CreateTableManager.Columns() expected *ColumnDef but", column)
+			}
+			continue
+		}
+		self.Tree.Columns = append(self.Tree.Columns, column)
+	}
+	return self
+}
+
+// Constraints appends table constraints e.g. PrimaryKey, Unique, Foreign or Check.
+//
+//	Constraints(Unique("tenant_id", "email"), Foreign("tenant_id").References(tenants, "id"))
+func (self *CreateTableManager) Constraints(constraints ...interface{}) *CreateTableManager {
+	self = self.chain()
+	for _, constraint := range constraints {
+		if err := checkConstraint("CreateTableManager.Constraints()", constraint); err != nil {
+			if self.err == nil {
+				self.err = err
+			}
+			continue
+		}
+		self.Tree.Constraints = append(self.Tree.Constraints, constraint)
+	}
+	return self
+}
+
+// Hooks adds hooks called by ToSql, see Hook.
+func (self *CreateTableManager) Hooks(hooks ...Hook) *CreateTableManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a copy of its Tree.
+// Column definitions are shared, modifying the clone does not affect the original and vice versa.
+func (self *CreateTableManager) Clone() *CreateTableManager {
+	m := *self
+	m.Tree = cloneCreateTableStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+func (self *CreateTableManager) Immutable() *CreateTableManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *CreateTableManager) chain() *CreateTableManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the statement. ToSql returns it as well.
+func (self *CreateTableManager) Err() error {
+	return self.err
+}
+
+// ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// Default values are inlined as literals, so no arguments are returned.
+func (self *CreateTableManager) ToSql() (string, []interface{}, error) {
+	return renderDdl(self.hooks, "CREATE TABLE", self.Tree.Table, self.Adapter, self.err, self.Tree)
+}
+
+func (self *CreateTableManager) Table() *TableNode {
+	return self.Tree.Table
+}
+
+// CreateTableManager factory method.
+func CreateTable(relation *TableNode) (m *CreateTableManager) {
+	m = new(CreateTableManager)
+	m.Tree = CreateTableStatement(relation)
+	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
+	return
+}
+
+// CreateTable returns a CreateTableManager for the table, its columns and constraints.
+// Indexes are created by CreateIndexes.
+func (t *TableDef) CreateTable() *CreateTableManager {
+	return CreateTable(t.Table).Columns(t.Columns...).Constraints(t.Constraints...)
+}
+
+// checkConstraint returns an ErrUnexpectedType error unless o is a table constraint.
+func checkConstraint(where string, o interface{}) error {
+	switch o.(type) {
+	case *PrimaryKeyNode, *UniqueNode, *ForeignKeyNode, *CheckNode, *LiteralNode:
+		return nil
+	}
+	return unexpectedType(where+" expected constraint but", o)
+}
@@ -0,0 +1,137 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableManager(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	mgr := users.CreateTable().IfNotExists().Columns(
+		&ColumnDef{Name: "id", Type: "bigint", Identity: true, PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "varchar(255)", Unique: true},
+		&ColumnDef{Name: "nick", Type: "text", Nullable: true, Default: "o'neil"},
+		&ColumnDef{Name: "active", Type: "boolean", Default: true},
+		&ColumnDef{Name: "created_at", Type: "timestamptz", Default: Literal("now()")},
+	).Constraints(Check("length(nick) > ?", 2).Named("users_nick_check"))
+
+	sql, args, err := mgr.ToSql()
+	assert.Nil(t, err)
+	assert.Nil(t, args)
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS "users" (`+
+		`"id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,`+
+		`"email" varchar(255) NOT NULL UNIQUE,`+
+		`"nick" text DEFAULT 'o''neil',`+
+		`"active" boolean NOT NULL DEFAULT TRUE,`+
+		`"created_at" timestamptz NOT NULL DEFAULT now(),`+
+		`CONSTRAINT "users_nick_check" CHECK (length(nick) > 2))`, sql)
+}
+
+func TestCreateTableManagerConstraints(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	tenants := psql.Table("tenants")
+	memberships := psql.Table("memberships").InSchema("app")
+	mgr := memberships.CreateTable().Columns(
+		&ColumnDef{Name: "tenant_id", Type: "bigint", PrimaryKey: true, References: &ForeignKey{Table: "tenants", Column: "id", OnDelete: "cascade"}},
+		&ColumnDef{Name: "user_id", Type: "bigint", PrimaryKey: true, References: &ForeignKey{Table: "auth.users", Column: "id"}},
+		&ColumnDef{Name: "email", Type: "text"},
+	).Constraints(
+		Unique("tenant_id", "email"),
+		Foreign("tenant_id", "email").References(tenants, "id", "owner_email").OnDelete("SET NULL").OnUpdate("CASCADE").Named("fk_owner"),
+	)
+
+	sql, _, err := mgr.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE "app"."memberships" (`+
+		`"tenant_id" bigint NOT NULL REFERENCES "tenants" ("id") ON DELETE CASCADE,`+
+		`"user_id" bigint NOT NULL REFERENCES "auth"."users" ("id"),`+
+		`"email" text NOT NULL,`+
+		`PRIMARY KEY ("tenant_id","user_id"),`+
+		`UNIQUE ("tenant_id","email"),`+
+		`CONSTRAINT "fk_owner" FOREIGN KEY ("tenant_id","email") REFERENCES "tenants" ("id","owner_email") ON DELETE SET NULL ON UPDATE CASCADE)`, sql)
+
+	// the column definitions are not modified
+	assert.True(t, mgr.Tree.Columns[0].PrimaryKey)
+}
+
+func TestCreateTableManagerMySql(t *testing.T) {
+	users := Dialect(MYSQL).Table("logins")
+	sql, args, err := users.CreateTable().Columns(
+		&ColumnDef{Name: "id", Type: "bigint unsigned", Identity: true, PrimaryKey: true},
+		&ColumnDef{Name: "user_id", Type: "int", References: &ForeignKey{Table: "users", Column: "id", OnDelete: "CASCADE"}},
+		&ColumnDef{Name: "at", Type: "datetime(3)", Default: Literal("CURRENT_TIMESTAMP(3)")},
+		&ColumnDef{Name: "note", Type: "text", Nullable: true, Default: `a\b`},
+	).Constraints(Unique("user_id", "at")).ToSql()
+
+	assert.Nil(t, err)
+	assert.Nil(t, args)
+	assert.Equal(t, "CREATE TABLE `logins` ("+
+		"`id` bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,"+
+		"`user_id` int NOT NULL,"+
+		"`at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),"+
+		"`note` text DEFAULT 'a\\\\b',"+
+		"FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,"+
+		"UNIQUE (`user_id`,`at`))", sql)
+}
+
+func TestCreateTableManagerFromTableDef(t *testing.T) {
+	schema := NewDefinitions()
+	users := schema.Define(Dialect(POSTGRES).Table("users"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "varchar(255)"},
+	)
+
+	sql, _, err := users.CreateTable().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE "users" ("id" bigserial NOT NULL PRIMARY KEY,"email" varchar(255) NOT NULL)`, sql)
+}
+
+func TestCreateTableManagerErrors(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	_, _, err := users.CreateTable().Columns(&ColumnDef{Name: "id"}).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+
+	_, _, err = users.CreateTable().Columns(nil).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	_, _, err = users.CreateTable().Columns(&ColumnDef{Name: "id", Type: "int"}).Constraints("UNIQUE (email)").ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	_, _, err = users.CreateTable().ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, _, err = Dialect(MYSQL).Table("users").CreateTable().Constraints(Unique("email")).ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, _, err = users.CreateTable().Columns(&ColumnDef{Name: "id", Type: "int"}).Constraints(Foreign("id")).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+
+	_, _, err = users.CreateTable().Columns(
+		&ColumnDef{Name: "id", Type: "int", References: &ForeignKey{Table: "users", Column: "id", OnDelete: "DROP TABLE users"}},
+	).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidLiteral))
+}
+
+func TestCreateTableManagerImmutable(t *testing.T) {
+	base := Dialect(POSTGRES).Table("users").CreateTable().Columns(&ColumnDef{Name: "id", Type: "int"}).Immutable()
+	other := base.IfNotExists().Columns(&ColumnDef{Name: "email", Type: "text"})
+
+	sql, _, _ := base.ToSql()
+	assert.Equal(t, `CREATE TABLE "users" ("id" int NOT NULL)`, sql)
+	sql, _, _ = other.ToSql()
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS "users" ("id" int NOT NULL,"email" text NOT NULL)`, sql)
+}
+
+func TestCreateTableManagerHooks(t *testing.T) {
+	var statement string
+	users := Dialect(POSTGRES).Hooks(HookFuncs{After: func(e *RenderEvent) {
+		statement = e.Statement
+	}}).Table("users")
+
+	_, _, err := users.CreateTable().Columns(&ColumnDef{Name: "id", Type: "int"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "CREATE TABLE", statement)
+}
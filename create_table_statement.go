@@ -0,0 +1,16 @@
+package codex
+
+// CreateTableStatementNode is the base node for SQL CREATE TABLE statements.
+type CreateTableStatementNode struct {
+	Table       *TableNode    // Pointer to the Table to create.
+	IfNotExists bool          // CREATE TABLE IF NOT EXISTS
+	Columns     []*ColumnDef  // Column definitions, several PrimaryKey columns render a composite PRIMARY KEY constraint.
+	Constraints []interface{} // Table constraints e.g. *UniqueNode, *ForeignKeyNode or *CheckNode.
+}
+
+// CreateTableStatementNode factory method.
+func CreateTableStatement(relation *TableNode) (statement *CreateTableStatementNode) {
+	statement = new(CreateTableStatementNode)
+	statement.Table = relation
+	return
+}
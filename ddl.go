@@ -0,0 +1,22 @@
+package codex
+
+// DDL statements take no bind parameters, so their managers render values
+// e.g. the DEFAULT of a column as SQL literals of the adapter's dialect.
+// Only pass trusted values - column defaults and index conditions are part of the schema, not user input.
+
+// renderDdl renders the DDL statement tree between the hooks and inlines its arguments.
+func renderDdl(hooks []Hook, statement string, table *TableNode, adapter adapter, err error, tree interface{}) (string, []interface{}, error) {
+	return render(hooks, statement, table, func() (string, []interface{}, error) {
+		if err != nil {
+			return "", nil, err
+		}
+		sql, args, err := VisitorWith(adapter, NewPlaceholderCollector(QUESTION_MARK)).Accept(tree)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql, err = Interpolate(sql, args, adapter); err != nil {
+			return "", nil, err
+		}
+		return sql, nil, nil
+	})
+}
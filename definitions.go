@@ -0,0 +1,233 @@
+package codex
+
+import (
+	"fmt"
+)
+
+// Definitions holds table definitions. The managers of tables attached to the definitions
+// check in ToSql that every referenced table and column is defined:
+//
+//	defs := NewDefinitions()
+//	users := defs.Define(Dialect(POSTGRES).Table("users"),
+//		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+//		&ColumnDef{Name: "email", Type: "varchar(255)"},
+//		&ColumnDef{Name: "nick", Type: "text", Nullable: true},
+//		&ColumnDef{Name: "created_at", Type: "timestamptz", Default: Literal("now()")},
+//	)
+//	users.Table.Select(users.Col("emial")).ToSql() // ErrUnknownColumn
+//
+// Keep the ColumnDefs in variables to reference columns by Go identifiers, see TableDef.Attr.
+//
+// Define all tables before rendering concurrently.
+type Definitions struct {
+	tables map[string]*TableDef
+	order  []*TableDef
+}
+
+// TableDef is the definition of a table, see Definitions.Define.
+type TableDef struct {
+	Table       *TableNode // attached to the definitions
+	Columns     []*ColumnDef
+	Constraints []interface{} // table constraints e.g. Unique("a", "b").Named("t_a_b_key"), see Diff
+	Indexes     []*IndexDef
+}
+
+// ColumnDef is the definition of a column.
+type ColumnDef struct {
+	Name       string
+	Type       string      // SQL type e.g. "varchar(255)"
+	Nullable   bool        // NOT NULL unless true
+	Default    interface{} // value or e.g. Literal("now()"), nil for none
+	PrimaryKey bool        // several primary key columns form a composite key
+	Unique     bool        // UNIQUE, see CreateTableManager
+	Identity   bool        // auto increment: AUTO_INCREMENT (MySQL) or GENERATED BY DEFAULT AS IDENTITY
+	References *ForeignKey // nil for none
+}
+
+// IndexDef is the definition of an index, see TableDef.Indexes.
+type IndexDef struct {
+	Name    string
+	Columns []interface{} // column names or expressions, see CreateIndexManager.On
+	Unique  bool
+	Using   string      // index method e.g. "gin", empty for the default
+	Where   interface{} // condition of a partial index like the one of Where, nil for none
+}
+
+// ForeignKey references the column of another table.
+type ForeignKey struct {
+	Table    string // name of the referenced table, qualified if it belongs to a schema e.g. "analytics.events"
+	Column   string
+	OnDelete string // e.g. "CASCADE", empty for the database default
+}
+
+// Definitions factory method.
+func NewDefinitions() *Definitions {
+	return &Definitions{tables: map[string]*TableDef{}}
+}
+
+// Define attaches the table to the definitions and defines its columns.
+// A table defined again replaces the former definition.
+func (s *Definitions) Define(table *TableNode, columns ...*ColumnDef) *TableDef {
+	def := &TableDef{Table: s.Attach(table), Columns: columns}
+	key := tableKey(table)
+	if old, ok := s.tables[key]; ok {
+		for i, d := range s.order {
+			if d == old {
+				s.order[i] = def
+			}
+		}
+	} else {
+		s.order = append(s.order, def)
+	}
+	s.tables[key] = def
+	return def
+}
+
+// Attach attaches the table to the definitions without defining it,
+// rendering its managers fails unless it is defined.
+func (s *Definitions) Attach(table *TableNode) *TableNode {
+	table.definitions = s
+	return table
+}
+
+// Lookup returns the definition of the table by its (schema qualified) name.
+func (s *Definitions) Lookup(table *TableNode) (*TableDef, bool) {
+	def, ok := s.tables[tableKey(table)]
+	return def, ok
+}
+
+// Tables returns the table definitions in order of definition.
+func (s *Definitions) Tables() []*TableDef {
+	return append([]*TableDef(nil), s.order...)
+}
+
+// Validate checks the definitions: column names must be unique within a table
+// and foreign keys must reference defined columns.
+func (s *Definitions) Validate() error {
+	for _, def := range s.order {
+		seen := map[string]bool{}
+		for _, c := range def.Columns {
+			if seen[c.Name] {
+				return fmt.Errorf("%w: column '%s' of '%s' is defined twice", ErrInvalidSchema, c.Name, tableKey(def.Table))
+			}
+			seen[c.Name] = true
+
+			if ref := c.References; ref != nil {
+				target, ok := s.tables[ref.Table]
+				if !ok || target.Column(ref.Column) == nil {
+					return fmt.Errorf("%w: column '%s' of '%s' references undefined '%s.%s'", ErrInvalidSchema, c.Name, tableKey(def.Table), ref.Table, ref.Column)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Col returns the column of the table, undefined ones fail in ToSql.
+func (t *TableDef) Col(name string) *AttributeNode {
+	return t.Table.Col(name)
+}
+
+// Attr returns the column handle of a definition passed to Define, so columns are referenced
+// by Go identifiers the compiler checks instead of names:
+//
+//	email := &ColumnDef{Name: "email", Type: "varchar(255)"}
+//	users := defs.Define(Dialect(POSTGRES).Table("users"), id, email)
+//	users.Table.Select(users.Attr(email)).Where(users.Attr(email).Eq("jon@example.com"))
+//
+// Like Col, a column not defined for the table fails in ToSql. See codex-gen for generated tables.
+func (t *TableDef) Attr(col *ColumnDef) *AttributeNode {
+	return t.Table.Col(col.Name)
+}
+
+// Column returns the definition of the column, nil if undefined.
+func (t *TableDef) Column(name string) *ColumnDef {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the names of the primary key columns.
+func (t *TableDef) PrimaryKey() (names []string) {
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			names = append(names, c.Name)
+		}
+	}
+	return
+}
+
+// WithDefinitions returns a DbDialect whose tables are attached to `defs`, see Definitions.Attach.
+func (db DbDialect) WithDefinitions(defs *Definitions) DbDialect {
+	return func(tableName string) *AttributeNode {
+		attr := db(tableName)
+		defs.Attach(attr.Table)
+		return attr
+	}
+}
+
+// tableKey returns the name of the table qualified by its schema.
+func tableKey(t *TableNode) string {
+	if t.Schema != "" {
+		return t.Schema + "." + t.Name
+	}
+	return t.Name
+}
+
+// validateSchema returns an ErrUnknownTable or ErrUnknownColumn error
+// for the first undefined reference within tree to a table attached to definitions.
+// Errors recorded by nested managers and derived tables are returned as well.
+func validateSchema(tree interface{}) (err error) {
+	Walk(tree, func(o interface{}) bool {
+		switch o := o.(type) {
+		case *SelectManager:
+			err = o.err
+		case *DerivedTableNode:
+			err = o.Err
+		case *TableNode:
+			err = checkColumn(o, nil)
+		case *AttributeNode:
+			err = checkColumn(o.Table, o.Name)
+		case *InsertStatementNode:
+			for _, column := range o.Columns {
+				if err = checkColumn(o.Table, column); err != nil {
+					break
+				}
+			}
+		case *UpdateStatementNode:
+			for _, value := range o.Values {
+				if a, ok := value.(*AssignmentNode); ok {
+					value = a.Left
+				}
+				if err = checkColumn(o.Table, value); err != nil {
+					break
+				}
+			}
+		}
+		return err == nil
+	})
+	return
+}
+
+// checkColumn checks table and column, a string or ColumnNode, if the table is attached to definitions.
+// Other columns e.g. StarNodes and nil are not checked.
+func checkColumn(table *TableNode, column interface{}) error {
+	if table == nil || table.definitions == nil {
+		return nil
+	}
+	def, ok := table.definitions.Lookup(table)
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrUnknownTable, tableKey(table))
+	}
+
+	if c, ok := column.(*ColumnNode); ok {
+		column = c.Expr
+	}
+	if name, ok := column.(string); ok && def.Column(name) == nil {
+		return fmt.Errorf("%w: '%s' of '%s'", ErrUnknownColumn, name, tableKey(table))
+	}
+	return nil
+}
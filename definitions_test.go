@@ -0,0 +1,116 @@
+package codex
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testSchema() (*Definitions, *TableDef, *TableDef) {
+	schema := NewDefinitions()
+	users := schema.Define(Dialect(POSTGRES).Table("users"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "varchar(255)"},
+		&ColumnDef{Name: "nick", Type: "text", Nullable: true},
+	)
+	posts := schema.Define(Dialect(POSTGRES).Table("posts"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "user_id", Type: "bigint", References: &ForeignKey{Table: "users", Column: "id", OnDelete: "CASCADE"}},
+		&ColumnDef{Name: "title", Type: "text"},
+	)
+	return schema, users, posts
+}
+
+func TestSchemaDefine(t *testing.T) {
+	schema, users, posts := testSchema()
+
+	assert.Equal(t, []*TableDef{users, posts}, schema.Tables())
+	def, ok := schema.Lookup(Table("users"))
+	assert.True(t, ok)
+	assert.Equal(t, users, def)
+	assert.Equal(t, "text", users.Column("nick").Type)
+	assert.Nil(t, users.Column("name"))
+	assert.Equal(t, []string{"id"}, posts.PrimaryKey())
+	assert.Nil(t, schema.Validate())
+
+	again := schema.Define(Dialect(POSTGRES).Table("users"), &ColumnDef{Name: "id", Type: "bigint"})
+	assert.Equal(t, []*TableDef{again, posts}, schema.Tables())
+}
+
+func TestSchemaAttr(t *testing.T) {
+	schema := NewDefinitions()
+	id := &ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true}
+	title := &ColumnDef{Name: "title", Type: "text"}
+	articles := schema.Define(Dialect(POSTGRES).Table("articles"), id, title)
+
+	sql, args, err := articles.Table.Select(articles.Attr(id)).Where(articles.Attr(title).Eq("go")).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "articles"."id" FROM "articles" WHERE ("articles"."title"=$1)`, sql)
+	assert.Equal(t, []interface{}{"go"}, args)
+
+	// a definition of another table
+	_, _, err = articles.Table.Select(articles.Attr(&ColumnDef{Name: "body"})).ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema, _, _ := testSchema()
+	schema.Define(Table("tags"), &ColumnDef{Name: "id"}, &ColumnDef{Name: "id"})
+	assert.True(t, errors.Is(schema.Validate(), ErrInvalidSchema))
+
+	schema, _, _ = testSchema()
+	schema.Define(Table("comments"), &ColumnDef{Name: "post_id", References: &ForeignKey{Table: "posts", Column: "uuid"}})
+	err := schema.Validate()
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+	assert.Contains(t, err.Error(), "posts.uuid")
+}
+
+func TestSchemaToSql(t *testing.T) {
+	_, users, posts := testSchema()
+
+	sql, args, err := users.Table.Select(users.Col("id"), users.Col("email")).
+		InnerJoin(posts.Table).On(posts.Col("user_id").Eq(users.Col("id"))).
+		Where(posts.Col("title").Like("%go%")).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id","users"."email" FROM "users" INNER JOIN "posts" ON "posts"."user_id"="users"."id" WHERE ("posts"."title" ILIKE $1)`, sql)
+	assert.Equal(t, []interface{}{"%go%"}, args)
+
+	_, _, err = users.Table.Select().ToSql()
+	assert.Nil(t, err)
+	_, _, err = users.Table.Insert("a@example.com").Into("email").ToSql()
+	assert.Nil(t, err)
+	_, _, err = users.Table.Set("nick").To("jon").Where(users.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+	_, _, err = users.Table.Delete(users.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+}
+
+func TestSchemaToSqlUnknown(t *testing.T) {
+	schema, users, posts := testSchema()
+
+	_, _, err := users.Table.Select("emial").ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+	assert.Contains(t, err.Error(), "'emial' of 'users'")
+
+	_, _, err = users.Table.Where(posts.Col("body").Eq("x")).ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+
+	_, _, err = users.Table.Insert("jon").Into("name").ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+
+	_, _, err = users.Table.Set("name").To("jon").ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+
+	_, err = users.Table.Delete(users.Col("name").Eq("jon")).ToDebugSql()
+	assert.True(t, errors.Is(err, ErrUnknownColumn))
+
+	psql := Dialect(POSTGRES).WithDefinitions(schema)
+	_, _, err = psql.Table("tags").Select("id").ToSql()
+	assert.True(t, errors.Is(err, ErrUnknownTable))
+	_, _, err = psql.Table("users").Select("id").ToSql()
+	assert.Nil(t, err)
+
+	// tables without schema are not checked
+	_, _, err = Table("users").Select("emial").ToSql()
+	assert.Nil(t, err)
+}
@@ -4,19 +4,26 @@ package codex
 type DeleteManager struct {
 	Tree    *DeleteStatementNode // The AST for the SQL DELETE statement.
 	Adapter adapter              // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
 }
 
 var _ Scoper = (*DeleteManager)(nil)
 
 func (self *DeleteManager) Scopes(scopes ...ScopeFunc) *DeleteManager {
+	self = self.chain()
 	for _, scope := range scopes {
 		scope(self)
 	}
 	return self
 }
 
+// Scope appends a WHERE condition like Where.
+// It always modifies the manager - even in immutable mode - as it is called by the ScopeFuncs of Scopes.
 func (self *DeleteManager) Scope(expr interface{}, args ...interface{}) {
-	self.Where(expr, args...)
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 }
 
 // Delete appends the expression to the Trees Wheres slice.
@@ -32,42 +39,39 @@ func (self *DeleteManager) Delete(expr interface{}, args ...interface{}) *Delete
 //   Where("a = ? AND b = ?", 123, true)    // with args -> Group(Literal("a = ? AND b = ?", 123, true))
 //   Where(Equal(Column("a"), Column("b"))) // no   args -> Group(Equal(Column("a"), Column("b")))
 func (self *DeleteManager) Where(expr interface{}, args ...interface{}) *DeleteManager {
-
-	if str, ok := expr.(string); ok {
-		expr = Literal(str, args...)
-	}
-	// enclose expr in Grouping - except if expr is already a Grouping
-	if _, ok := expr.(*GroupingNode); !ok {
-		expr = Grouping(expr)
-	}
-
-	self.Tree.Wheres = append(self.Tree.Wheres, expr)
+	self = self.chain()
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 	return self
 }
 
 // Limit Sets the Tree's Limit to the given integer.
 func (self *DeleteManager) Limit(expr interface{}) *DeleteManager {
+	self = self.chain()
 	self.Tree.Limit = Limit(expr)
 	return self
 }
 
 // Selection returns a *SelectManager while keeping
-// wheres, limit and adapter
+// copies of wheres, limit and adapter
 func (self *DeleteManager) Selection() *SelectManager {
 	m := Selection(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
 // Modification returns an *UpdateManager while keeping
-// wheres, limit and adapter
+// copies of wheres, limit and adapter
 func (self *DeleteManager) Modification() *UpdateManager {
 	m := Modification(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
@@ -76,12 +80,83 @@ func (self *DeleteManager) Modification() *UpdateManager {
 func (self *DeleteManager) Insertion() *InsertManager {
 	m := Insertion(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
+// Hooks adds hooks called by ToSql and ToSqlWith, see Hook.
+func (self *DeleteManager) Hooks(hooks ...Hook) *DeleteManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a deep copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *DeleteManager) Clone() *DeleteManager {
+	m := *self
+	m.Tree = cloneDeleteStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+// Thus a base query can be branched into several variants.
+func (self *DeleteManager) Immutable() *DeleteManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *DeleteManager) chain() *DeleteManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the query e.g. an unexpected argument type.
+// ToSql returns it as well.
+func (self *DeleteManager) Err() error {
+	return self.err
+}
+
 // ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// The Tree is not modified, so ToSql is safe to be called repeatedly and concurrently.
+// On error neither SQL nor arguments are returned.
 func (self *DeleteManager) ToSql() (string, []interface{}, error) {
-	return VisitorFor(self.Adapter).Accept(self.Tree)
+	return render(self.hooks, "DELETE", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorFor(self.Adapter))
+	})
+}
+
+// ToSqlWith is like ToSql but renders the placeholders in `style` e.g. COLON_NUMBER for Oracle.
+func (self *DeleteManager) ToSqlWith(style PlaceholderStyle) (string, []interface{}, error) {
+	return render(self.hooks, "DELETE", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(style)))
+	})
+}
+
+// ToDebugSql returns the SQL with the args inlined for logs and EXPLAIN copy-paste, see Interpolate.
+// NEVER execute it - use ToSql instead. Hooks are not called.
+func (self *DeleteManager) ToDebugSql() (string, error) {
+	sql, args, err := self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(QUESTION_MARK)))
+	if err != nil {
+		return "", err
+	}
+	return Interpolate(sql, args, self.Adapter)
+}
+
+func (self *DeleteManager) accept(visitor VisitorInterface) (string, []interface{}, error) {
+	if self.err != nil {
+		return "", nil, self.err
+	}
+	if err := validateSchema(self.Tree); err != nil {
+		return "", nil, err
+	}
+	return visitor.Accept(self.Tree)
 }
 
 func (self *DeleteManager) Table() *TableNode {
@@ -93,5 +168,6 @@ func Deletion(relation *TableNode) (m *DeleteManager) {
 	m = new(DeleteManager)
 	m.Tree = DeleteStatement(relation)
 	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
 	return
 }
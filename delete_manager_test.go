@@ -117,3 +117,27 @@ func TestDeleteManagerModification(t *testing.T) {
 	assert.Equal(t, `UPDATE "users" SET "name"=? WHERE ("users"."owner_id"=?) AND (id > ?) LIMIT ?`, sql)
 	assert.Equal(t, []interface{}{"new Name", 77, 2, 1}, args)
 }
+
+func TestDeleteManagerImmutable(t *testing.T) {
+	users := Table("users")
+	base := users.Delete(users.Col("active").Eq(false)).Immutable()
+	a := base.Where(users.Col("id").Eq(1))
+
+	sql, args, err := base.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."active"=?)`, sql)
+	assert.Equal(t, []interface{}{false}, args)
+
+	sql, args, err = a.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."active"=?) AND ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{false, 1}, args)
+}
+
+func TestDeleteManagerClone(t *testing.T) {
+	users := Table("users")
+	m := users.Delete(users.Col("id").Eq(1))
+	c := m.Clone().Limit(1)
+	assert.Nil(t, m.Tree.Limit)
+	assert.NotNil(t, c.Tree.Limit)
+}
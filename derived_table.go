@@ -0,0 +1,80 @@
+package codex
+
+// DerivedTableNode is a sub select used as table source,
+// renders to `(SELECT ...) AS "alias"` in FROM and JOIN clauses.
+type DerivedTableNode struct {
+	Expr  *SelectStatementNode // The sub select.
+	Table *TableNode           // Table named by the alias, columns of the derived table are scoped to it.
+	Err   error                // error recorded by the manager of the sub select, returned when visited
+}
+
+// Col returns a Column scoped to the derived table e.g. renders to '"alias"."name"' sql
+func (d *DerivedTableNode) Col(name string) *AttributeNode {
+	return d.Table.Col(name)
+}
+
+// Star returns a * scoped to the derived table e.g. renders to '"alias".*' sql
+func (d *DerivedTableNode) Star() *AttributeNode {
+	return d.Table.Star()
+}
+
+// Select returns a SelectManager selecting from the derived table
+// appends the columns
+func (d *DerivedTableNode) Select(cols ...interface{}) *SelectManager {
+	// convert string to AttributeNode
+	for i, col := range cols {
+		if str, ok := col.(string); ok {
+			cols[i] = d.Col(str)
+		}
+	}
+
+	return d.Selection().Select(cols...)
+}
+
+// Where Returns a pointer to a SelectManager with the initial filter provided.
+// see SelectManager.Where()
+func (d *DerivedTableNode) Where(expr interface{}, args ...interface{}) *SelectManager {
+	return d.Selection().Where(expr, args...)
+}
+
+// Returns a pointer to a SelectManager with an initial InnerJoinNode.
+func (d *DerivedTableNode) InnerJoin(expr interface{}) *SelectManager {
+	return d.Selection().InnerJoin(expr)
+}
+
+// Returns a pointer to a SelectManager with an initial OuterJoinNode.
+func (d *DerivedTableNode) OuterJoin(expr interface{}) *SelectManager {
+	return d.Selection().OuterJoin(expr)
+}
+
+// Returns a pointer to a SelectManager selecting from the derived table
+func (d *DerivedTableNode) Selection() *SelectManager {
+	m := Selection(d.Table)
+	m.Tree.Source.Left = d
+	m.err = d.Err
+	return m
+}
+
+// DerivedTableNode factory method.
+func DerivedTable(expr *SelectStatementNode, alias string) *DerivedTableNode {
+	return &DerivedTableNode{
+		Expr:  expr,
+		Table: Table(alias),
+	}
+}
+
+// From returns a derived table of a copy of the sub select `manager` named `alias`,
+// modifying `manager` afterwards does not affect it. The derived table keeps the adapter,
+// hooks and error of `manager`, managers selecting from or joining it record the error, see Err().
+//
+//	sub := orders.Select(orders.Col("user_id"), Sum(orders.Col("total")).As("total")).Group(orders.Col("user_id"))
+//	t := From(sub, "t")
+//	t.Select(t.Col("total")).Where(t.Col("total").Gt(100))
+//	// SELECT "t"."total" FROM (SELECT ... GROUP BY "orders"."user_id") AS "t" WHERE "t"."total">?
+func From(manager *SelectManager, alias string) *DerivedTableNode {
+	d := DerivedTable(cloneSelectStatement(manager.Tree), alias)
+	d.Err = manager.err
+	d.Table.Adapter = manager.Adapter
+	d.Table.hooks = manager.hooks
+	return d
+}
@@ -0,0 +1,105 @@
+package codex
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDerivedTableFactory(t *testing.T) {
+	sub := Table("orders").Select("user_id")
+	d := From(sub, "t")
+	assert.Equal(t, sub.Tree, d.Expr)
+	assert.Equal(t, "t", d.Table.Name)
+}
+
+func TestDerivedTableSelect(t *testing.T) {
+	orders := Table("orders")
+	sub := orders.Select(orders.Col("user_id"), Sum(orders.Col("total")).As("total")).
+		Where(orders.Col("state").Eq("paid")).
+		Group(orders.Col("user_id"))
+	d := From(sub, "t")
+
+	sql, args, err := d.Select("user_id", "total").Where(d.Col("total").Gt(100)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "t"."user_id","t"."total" FROM (SELECT "orders"."user_id",SUM("orders"."total") AS "total" FROM "orders" WHERE ("orders"."state"=?) GROUP BY "orders"."user_id") AS "t" WHERE ("t"."total">?)`, sql)
+	assert.Equal(t, []interface{}{"paid", 100}, args)
+}
+
+func TestDerivedTableDefaultStar(t *testing.T) {
+	d := From(Table("orders").Where("id > ?", 1), "t")
+
+	sql, args, err := d.Selection().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "t".* FROM (SELECT * FROM "orders" WHERE (id > ?)) AS "t"`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestDerivedTableInnerJoin(t *testing.T) {
+	users := Table("users")
+	orders := Table("orders")
+	sub := orders.Select(orders.Col("user_id")).Where(orders.Col("total").Gt(50))
+	d := From(sub, "big")
+
+	sql, args, err := users.Where(users.Col("active").Eq(true)).
+		InnerJoin(d).On(d.Col("user_id").Eq(users.Col("id"))).
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" INNER JOIN (SELECT "orders"."user_id" FROM "orders" WHERE ("orders"."total">?)) AS "big" ON "big"."user_id"="users"."id" WHERE ("users"."active"=?)`, sql)
+	assert.Equal(t, []interface{}{50, true}, args)
+}
+
+func TestDerivedTablePostgresArgOrder(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+	orders := psql.Table("orders")
+	d := From(orders.Where("total > ?", 50), "o")
+
+	sql, args, err := users.Select(users.Col("id")).
+		Where("users.id > ?", 1).
+		OuterJoin(d).On(d.Col("user_id").Eq(users.Col("id"))).
+		Limit(3).
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" LEFT OUTER JOIN (SELECT * FROM "orders" WHERE (total > $1)) AS "o" ON "o"."user_id"="users"."id" WHERE (users.id > $2) LIMIT $3`, sql)
+	assert.Equal(t, []interface{}{50, 1, 3}, args)
+}
+
+func TestDerivedTableKeepsAdapter(t *testing.T) {
+	mysql := Dialect(MYSQL)
+	d := From(mysql.Table("orders").Select("id"), "t")
+
+	sql, args, err := d.Select("id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `t`.`id` FROM (SELECT `orders`.`id` FROM `orders`) AS `t`", sql)
+	assert.Empty(t, args)
+}
+
+func TestDerivedTableCopiesSubSelect(t *testing.T) {
+	sub := Table("orders").Select("user_id")
+	d := From(sub, "t")
+	sub.Where("total > ?", 50)
+
+	sql, args, err := d.Select("user_id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "t"."user_id" FROM (SELECT "orders"."user_id" FROM "orders") AS "t"`, sql)
+	assert.Empty(t, args)
+}
+
+func TestDerivedTableError(t *testing.T) {
+	users := Table("users")
+	d := From(users.Select("id").InnerJoin(42), "t")
+	assert.True(t, errors.Is(d.Err, ErrUnexpectedType))
+
+	for _, m := range []*SelectManager{
+		d.Select("id"), d.Where(d.Col("id").Eq(1)), d.Selection(),
+		users.Select("id").InnerJoin(d).On(d.Col("id").Eq(users.Col("id"))),
+		users.Select("id").OuterJoin(d).On(d.Col("id").Eq(users.Col("id"))),
+	} {
+		assert.True(t, errors.Is(m.Err(), ErrUnexpectedType))
+		sql, args, err := m.ToSql()
+		assert.True(t, errors.Is(err, ErrUnexpectedType))
+		assert.Equal(t, "", sql)
+		assert.Nil(t, args)
+	}
+}
@@ -3,6 +3,8 @@ package codex
 // DescendingNode is a UnaryNode struct
 type DescendingNode UnaryNode
 
+var _ SqlNode = (*DescendingNode)(nil)
+
 // Returns a Grouping node with an expression containing a
 // reference to an Or node of the Descending and other.
 func (self *DescendingNode) Or(other interface{}) *GroupingNode {
@@ -21,6 +23,13 @@ func (self *DescendingNode) Not() *NotNode {
 	return Not(self)
 }
 
+// Render appends the expression followed by DESC.
+func (self *DescendingNode) Render(visitor VisitorInterface) (err error) {
+	err = visitor.Visit(self.Expr, visitor)
+	visitor.AppendSqlStr(" DESC")
+	return
+}
+
 // DescendingNode factory method.
 func Descending(expr interface{}) (descending *DescendingNode) {
 	descending = new(DescendingNode)
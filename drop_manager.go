@@ -0,0 +1,159 @@
+package codex
+
+// DropTableManager manages a tree that compiles to a SQL DROP TABLE statement.
+type DropTableManager struct {
+	Tree    *DropTableStatementNode // The AST for the SQL DROP TABLE statement.
+	Adapter adapter                 // The SQL adapter.
+
+	immutable bool // chained calls return a modified clone, see Immutable()
+	hooks     []Hook
+}
+
+// IfExists renders DROP TABLE IF EXISTS.
+func (self *DropTableManager) IfExists() *DropTableManager {
+	self = self.chain()
+	self.Tree.IfExists = true
+	return self
+}
+
+// Cascade renders DROP TABLE ... CASCADE, MySQL ignores it.
+func (self *DropTableManager) Cascade() *DropTableManager {
+	self = self.chain()
+	self.Tree.Cascade = true
+	return self
+}
+
+// Hooks adds hooks called by ToSql, see Hook.
+func (self *DropTableManager) Hooks(hooks ...Hook) *DropTableManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a copy of its Tree.
+func (self *DropTableManager) Clone() *DropTableManager {
+	m := *self
+	tree := *self.Tree
+	tree.Tables = append([]*TableNode(nil), self.Tree.Tables...)
+	m.Tree = &tree
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+func (self *DropTableManager) Immutable() *DropTableManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *DropTableManager) chain() *DropTableManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+func (self *DropTableManager) ToSql() (string, []interface{}, error) {
+	return renderDdl(self.hooks, "DROP TABLE", self.Table(), self.Adapter, nil, self.Tree)
+}
+
+// Table returns the first table to drop, nil if there is none.
+func (self *DropTableManager) Table() *TableNode {
+	if 0 == len(self.Tree.Tables) {
+		return nil
+	}
+	return self.Tree.Tables[0]
+}
+
+// DropTableManager factory method. Adapter and hooks are the ones of the first table.
+func DropTable(relations ...*TableNode) (m *DropTableManager) {
+	m = new(DropTableManager)
+	m.Tree = DropTableStatement(relations...)
+	if 0 < len(relations) {
+		m.Adapter = relations[0].Adapter
+		m.hooks = relations[0].hooks
+	}
+	return
+}
+
+// DropIndexManager manages a tree that compiles to a SQL DROP INDEX statement.
+type DropIndexManager struct {
+	Tree    *DropIndexStatementNode // The AST for the SQL DROP INDEX statement.
+	Adapter adapter                 // The SQL adapter.
+
+	immutable bool // chained calls return a modified clone, see Immutable()
+	hooks     []Hook
+}
+
+// IfExists renders DROP INDEX IF EXISTS, Postgres only.
+func (self *DropIndexManager) IfExists() *DropIndexManager {
+	self = self.chain()
+	self.Tree.IfExists = true
+	return self
+}
+
+// Concurrently renders DROP INDEX CONCURRENTLY, Postgres only.
+func (self *DropIndexManager) Concurrently() *DropIndexManager {
+	self = self.chain()
+	self.Tree.Concurrently = true
+	return self
+}
+
+// Cascade renders DROP INDEX ... CASCADE, Postgres only.
+func (self *DropIndexManager) Cascade() *DropIndexManager {
+	self = self.chain()
+	self.Tree.Cascade = true
+	return self
+}
+
+// Hooks adds hooks called by ToSql, see Hook.
+func (self *DropIndexManager) Hooks(hooks ...Hook) *DropIndexManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a copy of its Tree.
+func (self *DropIndexManager) Clone() *DropIndexManager {
+	m := *self
+	tree := *self.Tree
+	m.Tree = &tree
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+func (self *DropIndexManager) Immutable() *DropIndexManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *DropIndexManager) chain() *DropIndexManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+func (self *DropIndexManager) ToSql() (string, []interface{}, error) {
+	return renderDdl(self.hooks, "DROP INDEX", self.Tree.Table, self.Adapter, nil, self.Tree)
+}
+
+func (self *DropIndexManager) Table() *TableNode {
+	return self.Tree.Table
+}
+
+// DropIndexManager factory method.
+func DropIndex(relation *TableNode, name string) (m *DropIndexManager) {
+	m = new(DropIndexManager)
+	m.Tree = DropIndexStatement(name, relation)
+	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
+	return
+}
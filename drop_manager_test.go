@@ -0,0 +1,48 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropTableManager(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users, posts := psql.Table("users"), psql.Table("posts").InSchema("blog")
+
+	sql, args, err := users.DropTable().ToSql()
+	assert.Nil(t, err)
+	assert.Nil(t, args)
+	assert.Equal(t, `DROP TABLE "users"`, sql)
+
+	sql, _, err = DropTable(posts, users).IfExists().Cascade().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DROP TABLE IF EXISTS "blog"."posts","users" CASCADE`, sql)
+
+	_, _, err = DropTable().ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+}
+
+func TestDropIndexManager(t *testing.T) {
+	posts := Dialect(POSTGRES).Table("posts").InSchema("blog")
+
+	sql, _, err := posts.DropIndex("posts_title_idx").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DROP INDEX "blog"."posts_title_idx"`, sql)
+
+	sql, _, err = posts.DropIndex("posts_title_idx").Concurrently().IfExists().Cascade().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DROP INDEX CONCURRENTLY IF EXISTS "blog"."posts_title_idx" CASCADE`, sql)
+}
+
+func TestDropIndexManagerMySql(t *testing.T) {
+	posts := Dialect(MYSQL).Table("posts")
+
+	sql, _, err := posts.DropIndex("posts_title_idx").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "DROP INDEX `posts_title_idx` ON `posts`", sql)
+
+	_, _, err = posts.DropIndex("posts_title_idx").IfExists().ToSql()
+	assert.True(t, errors.Is(err, ErrUnsupportedByDialect))
+}
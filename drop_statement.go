@@ -0,0 +1,32 @@
+package codex
+
+// DropTableStatementNode is the base node for SQL DROP TABLE statements.
+type DropTableStatementNode struct {
+	Tables   []*TableNode // Tables to drop.
+	IfExists bool         // DROP TABLE IF EXISTS
+	Cascade  bool         // DROP TABLE ... CASCADE
+}
+
+// DropIndexStatementNode is the base node for SQL DROP INDEX statements.
+type DropIndexStatementNode struct {
+	Name         string     // Name of the index.
+	Table        *TableNode // Postgres qualifies the index by the schema of Table, MySQL renders ON table.
+	IfExists     bool       // DROP INDEX IF EXISTS, Postgres only
+	Concurrently bool       // DROP INDEX CONCURRENTLY, Postgres only
+	Cascade      bool       // DROP INDEX ... CASCADE, Postgres only
+}
+
+// DropTableStatementNode factory method.
+func DropTableStatement(relations ...*TableNode) (statement *DropTableStatementNode) {
+	statement = new(DropTableStatementNode)
+	statement.Tables = relations
+	return
+}
+
+// DropIndexStatementNode factory method.
+func DropIndexStatement(name string, relation *TableNode) (statement *DropIndexStatementNode) {
+	statement = new(DropIndexStatementNode)
+	statement.Name = name
+	statement.Table = relation
+	return
+}
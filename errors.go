@@ -0,0 +1,51 @@
+package codex
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by ToSql() and the visitors, usable with errors.Is.
+var (
+	ErrInvalidIdentifier    = errors.New("invalid identifier")
+	ErrUnsupportedByDialect = errors.New("unsupported by dialect")
+	ErrUnexpectedType       = errors.New("unexpected type")
+	ErrInvalidLiteral       = errors.New("invalid literal")
+	ErrArgumentCount        = errors.New("wrong number of arguments")
+	ErrNothingToSet         = errors.New("nothing to set")      // e.g. SetStruct without changed fields
+	ErrUnknownTable         = errors.New("unknown table")       // not defined in the schema the table is attached to
+	ErrUnknownColumn        = errors.New("unknown column")      // not defined in the schema the table is attached to
+	ErrInvalidSchema        = errors.New("invalid schema")      // see Definitions.Validate
+	ErrSyntax               = errors.New("syntax error")        // see DbDialect.Parse
+	ErrNoChanges            = errors.New("no changes")          // schemas do not differ, see WriteGooseMigration
+	ErrMissingPrimaryKey    = errors.New("missing primary key") // zero pk field, see UpdateManager.SetStruct
+)
+
+// IdentifierError is returned for table and column names which can not be used,
+// see DbDialect.Strict. It matches ErrInvalidIdentifier with errors.Is.
+type IdentifierError struct {
+	Kind     string // "table" or "column"
+	Name     string
+	Reserved bool // Name is a reserved word
+}
+
+func (e *IdentifierError) Error() string {
+	if e.Reserved {
+		return fmt.Sprintf("reserved word as %s name: '%s'", e.Kind, e.Name)
+	}
+	return fmt.Sprintf("invalid %s name: '%s'", e.Kind, e.Name)
+}
+
+func (e *IdentifierError) Unwrap() error {
+	return ErrInvalidIdentifier
+}
+
+// unexpectedType returns an ErrUnexpectedType error naming the method `where` and the value o.
+func unexpectedType(where string, o interface{}) error {
+	return fmt.Errorf("%w: %s got %T %#v", ErrUnexpectedType, where, o, o)
+}
+
+// unsupportedByDialect returns an ErrUnsupportedByDialect error for `feature`.
+func unsupportedByDialect(dialect, feature string) error {
+	return fmt.Errorf("%w: %s does not support %s", ErrUnsupportedByDialect, dialect, feature)
+}
@@ -0,0 +1,93 @@
+package codex
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorsInvalidIdentifier(t *testing.T) {
+	v := NewToSqlVisitor()
+	v.Strict = true
+	sql, args, err := v.Accept(Table("users").Select("from").Tree)
+	assert.True(t, errors.Is(err, ErrInvalidIdentifier))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	var identErr *IdentifierError
+	assert.True(t, errors.As(err, &identErr))
+	assert.Equal(t, "column", identErr.Kind)
+	assert.Equal(t, "from", identErr.Name)
+	assert.True(t, identErr.Reserved)
+}
+
+func TestErrorsNoPartialSql(t *testing.T) {
+	users := Table("users")
+	sql, args, err := users.Where(users.Col("id").Eq(1)).Where(&InNode{Left: users.Col("id"), Right: 1}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	sql, args, err = Dialect(POSTGRES).Table("users").Where(&InNode{Left: Column("id"), Right: "x"}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
+
+func TestErrorsUnexpectedJoinType(t *testing.T) {
+	users := Table("users")
+	m := users.Where(users.Col("id").Eq(1)).InnerJoin("orders").On(users.Col("id").Eq(1))
+	assert.True(t, errors.Is(m.Err(), ErrUnexpectedType))
+
+	sql, args, err := m.ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	assert.Contains(t, err.Error(), "SelectManager.InnerJoin()")
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	_, _, err = users.OuterJoin(42).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	// kept by Count and clones
+	_, _, err = m.Count("id").ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	_, _, err = m.Clone().ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+}
+
+func TestErrorsFirstErrorWins(t *testing.T) {
+	users := Table("users")
+	m := users.InnerJoin(1).OuterJoin("x")
+	assert.Contains(t, m.Err().Error(), "SelectManager.InnerJoin()")
+}
+
+func TestErrorsUpdateToMoreValuesThanColumns(t *testing.T) {
+	users := Table("users")
+	m := users.Set("name").To("Jon", 42)
+	assert.True(t, errors.Is(m.Err(), ErrArgumentCount))
+
+	sql, args, err := m.ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
+
+func TestErrorsUnsupportedByDialect(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+	sql, args, err := users.Insert("Jon").Into("name").Returning("id").ToSql()
+	assert.True(t, errors.Is(err, ErrUnsupportedByDialect))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	sql, _, err = users.Insert("Jon").Into("name").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`name`) VALUES (?)", sql)
+}
+
+func TestErrorsNoErr(t *testing.T) {
+	users := Table("users")
+	assert.Nil(t, users.Selection().Err())
+	assert.Nil(t, users.Insertion().Err())
+	assert.Nil(t, users.Modification().Err())
+	assert.Nil(t, users.Deletion().Err())
+}
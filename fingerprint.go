@@ -0,0 +1,160 @@
+package codex
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Fingerprint returns a stable hash and the normalized SQL of the statement built by the manager m,
+// a *SelectManager, *InsertManager, *UpdateManager or *DeleteManager, e.g. to group slow queries or label metrics.
+//
+// The normalized SQL is rendered from the AST with "?" placeholders and without args, so statements
+// differing in their args or placeholder style only have the same fingerprint. IN lists and VALUES rows
+// repeating the same expression are collapsed to a single one, IN(?,?,?) is normalized to IN(?).
+// Number and string constants within literal SQL e.g. of Literal or Parse are normalized to "?" as well,
+// lists of placeholders e.g. of an expanded Literal("id IN(?...)", ids) are collapsed the same way.
+// Hooks are not called.
+//
+//	hash, sql, err := Fingerprint(users.Select().Where(users.Col("id").In(1, 2, 3)))
+//	// sql = SELECT "users".* FROM "users" WHERE ("users"."id" IN(?))
+func Fingerprint(m interface{}) (hash string, sql string, err error) {
+	switch m := m.(type) {
+	case *SelectManager:
+		n := *m
+		n.Tree = Rewrite(m.Tree, normalizer(m.Adapter)).(*SelectStatementNode)
+		sql, _, err = n.accept(fingerprintVisitor(m.Adapter))
+	case *InsertManager:
+		n := *m
+		n.Tree = Rewrite(m.Tree, normalizer(m.Adapter)).(*InsertStatementNode)
+		sql, _, err = n.accept(fingerprintVisitor(m.Adapter))
+	case *UpdateManager:
+		n := *m
+		n.Tree = Rewrite(m.Tree, normalizer(m.Adapter)).(*UpdateStatementNode)
+		sql, _, err = n.accept(fingerprintVisitor(m.Adapter))
+	case *DeleteManager:
+		n := *m
+		n.Tree = Rewrite(m.Tree, normalizer(m.Adapter)).(*DeleteStatementNode)
+		sql, _, err = n.accept(fingerprintVisitor(m.Adapter))
+	default:
+		return "", "", unexpectedType("Fingerprint() requires a manager but", m)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(sql))
+	return fmt.Sprintf("%016x", h.Sum64()), sql, nil
+}
+
+func fingerprintVisitor(adapter adapter) VisitorInterface {
+	return VisitorWith(adapter, NewPlaceholderCollector(QUESTION_MARK))
+}
+
+// normalizer returns the Rewrite func normalizing literals and collapsing IN lists and VALUES rows
+// whose elements render the same SQL for the adapter.
+func normalizer(adapter adapter) func(interface{}) interface{} {
+	// render returns the SQL of the elements joined by commas, ok is false on error
+	render := func(elements []interface{}) (sql string, ok bool) {
+		for i, e := range elements {
+			s, _, err := fingerprintVisitor(adapter).Accept(e)
+			if err != nil {
+				return "", false
+			}
+			if i > 0 {
+				sql += ","
+			}
+			sql += s
+		}
+		return sql, true
+	}
+	// same reports whether all rows render the same SQL
+	same := func(rows [][]interface{}) bool {
+		first, ok := render(rows[0])
+		for _, row := range rows[1:] {
+			if sql, _ := render(row); !ok || sql != first {
+				return false
+			}
+		}
+		return ok
+	}
+
+	return func(o interface{}) interface{} {
+		switch o := o.(type) {
+		case *LiteralNode:
+			return normalizeLiteral(o, adapter)
+		case *InNode:
+			vals, ok := o.Right.([]interface{})
+			if !ok || len(vals) < 2 {
+				break
+			}
+			rows := make([][]interface{}, len(vals))
+			for i, val := range vals {
+				rows[i] = []interface{}{val}
+			}
+			if same(rows) {
+				o.Right = vals[:1]
+			}
+		case *ValuesNode:
+			// Expressions is the first row
+			if 0 < len(o.Rows) && same(append([][]interface{}{o.Expressions}, o.Rows...)) {
+				o.Rows = nil
+			}
+		case *ValuesTableNode:
+			if 1 < len(o.Rows) && same(o.Rows) {
+				o.Rows = o.Rows[:1]
+			}
+		}
+		return o
+	}
+}
+
+// normalizeLiteral returns the literal with number and string constants replaced by "?" and without args.
+// A comma separated list of placeholders is collapsed to a single one if it is parenthesized
+// or the whole literal e.g. "id IN(1, 2, ?)" is normalized to "id IN(?)".
+// Literals which fail to tokenize are kept.
+func normalizeLiteral(o *LiteralNode, adapter adapter) *LiteralNode {
+	if o.Err != nil {
+		return o
+	}
+	tokens, err := tokenizeSql(o.Sql, adapter)
+	if err != nil {
+		return o
+	}
+
+	placeholder := func(tok sqlToken) bool {
+		return tok.kind == sqlParam || tok.kind == sqlNumber || tok.kind == sqlString
+	}
+	// listEnd returns the index after the list of placeholders starting at tokens[i], i if there is none
+	listEnd := func(i int) int {
+		j := i
+		for j < len(tokens) && placeholder(tokens[j]) {
+			if j+2 < len(tokens) && tokens[j+1].text == "," && placeholder(tokens[j+2]) {
+				j += 2
+				continue
+			}
+			return j + 1
+		}
+		return j
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if i > 0 && tok.start > tokens[i-1].end {
+			b.WriteByte(SPACE)
+		}
+		if !placeholder(tok) {
+			b.WriteString(o.Sql[tok.start:tok.end])
+			continue
+		}
+		b.WriteByte(QUESTION)
+		// keep "a = ?, b = ?" but collapse "(?, ?)"
+		end := listEnd(i)
+		if (i == 0 || tokens[i-1].text == "(") && (end == len(tokens) || tokens[end].text == ")") {
+			i = end - 1
+		}
+	}
+	return &LiteralNode{Sql: b.String()}
+}
@@ -0,0 +1,138 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	a := users.Select(users.Col("id")).Where(users.Col("id").In(1, 2, 3)).Where(users.Col("name").Eq("Jon")).Limit(10)
+	b := users.Select(users.Col("id")).Where(users.Col("id").In(4)).Where(users.Col("name").Eq("Ann")).Limit(20)
+
+	hashA, sqlA, err := Fingerprint(a)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."id" IN(?)) AND ("users"."name"=?) LIMIT ?`, sqlA)
+	assert.Len(t, hashA, 16)
+
+	hashB, sqlB, err := Fingerprint(b)
+	assert.Nil(t, err)
+	assert.Equal(t, sqlA, sqlB)
+	assert.Equal(t, hashA, hashB)
+
+	// the manager is untouched
+	sql, args, err := a.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."id" IN($1,$2,$3)) AND ("users"."name"=$4) LIMIT $5`, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, "Jon", 10}, args)
+
+	// other statements have other fingerprints
+	hash, _, err := Fingerprint(users.Select(users.Col("id")).Where(users.Col("id").In(1, 2)))
+	assert.Nil(t, err)
+	assert.NotEqual(t, hashA, hash)
+}
+
+func TestFingerprintParsed(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+	m, err := psql.Parse(`SELECT "users"."id" FROM "users" WHERE "users"."state" IN ($2, $1) AND "users"."name" = $3`, "a", "b", "x")
+	assert.Nil(t, err)
+
+	hash, sql, err := Fingerprint(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."state" IN(?)) AND ("users"."name"=?)`, sql)
+
+	built, _, err := Fingerprint(users.Select(users.Col("id")).Where(users.Col("state").In("c")).Where(users.Col("name").Eq("y")))
+	assert.Nil(t, err)
+	assert.Equal(t, hash, built)
+}
+
+func TestFingerprintLiteralExpansion(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	two, sql, err := Fingerprint(users.Select(users.Col("id")).Where("id IN(?...) AND state = ?", []int{1, 2}, "new"))
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE (id IN(?) AND state = ?)`, sql)
+
+	three, _, err := Fingerprint(users.Select(users.Col("id")).Where("id IN(?...) AND state = ?", []int{1, 2, 3}, "old"))
+	assert.Nil(t, err)
+	assert.Equal(t, two, three)
+
+	// lists of several placeholders outside of parentheses are kept
+	_, sql, err = Fingerprint(users.Set(Literal("a = ?, b = ?", 1, 2)).Where(users.Col("id").Eq(1)))
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET a = ?, b = ? WHERE ("users"."id"=?)`, sql)
+
+	// a whole literal list is collapsed
+	_, sql, err = Fingerprint(users.Select(users.Col("id")).Where(users.Col("id").In(Literal("?...", 1, 2, 3))))
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."id" IN(?))`, sql)
+}
+
+func TestFingerprintConstants(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+	parsed, err := psql.Parse(`SELECT "users"."id" FROM "users" WHERE "users"."id" IN (1, 2, 3) AND "users"."name" = 'a' LIMIT 10`)
+	assert.Nil(t, err)
+	hash, sql, err := Fingerprint(parsed)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."id" IN(?)) AND ("users"."name"=?) LIMIT ?`, sql)
+
+	built, _, err := Fingerprint(users.Select(users.Col("id")).Where(users.Col("id").In(4, 5)).Where(users.Col("name").Eq("b")).Limit(20))
+	assert.Nil(t, err)
+	assert.Equal(t, hash, built)
+
+	// constants within literals, quoted identifiers and words are kept
+	_, sql, err = Fingerprint(users.Select(Literal(`"2fa" AS n, E'it\'s', 3.5e2`)).Where(`status = 'new' AND x::int > -1`))
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "2fa" AS n, ?, ? FROM "users" WHERE (status = ? AND x::int > -?)`, sql)
+
+	// invalid SQL is kept as it is
+	_, sql, err = Fingerprint(users.Select(Literal("`a` = 1")))
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `a` = 1 FROM \"users\"", sql)
+}
+
+func TestFingerprintKeepsDifferentElements(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+	_, sql, err := Fingerprint(users.Select(users.Col("id")).Where(users.Col("id").In(users.Col("parent_id"), 1, 2)))
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.`id` FROM `users` WHERE (`users`.`id` IN(`users`.`parent_id`,?,?))", sql)
+
+	_, sql, err = Fingerprint(users.Select(users.Col("id")).Where(users.Col("id").In(Lower("A"), Lower("B"))))
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.`id` FROM `users` WHERE (`users`.`id` IN(LOWER(?)))", sql)
+}
+
+func TestFingerprintStatements(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+
+	_, sql, err := Fingerprint(users.Insert(1, "Jon").Into("id", "name").Row(2, "Ann").Row(3, "Bob"))
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name") VALUES (?,?)`, sql)
+
+	_, sql, err = Fingerprint(users.Insert(1, "Jon").Into("id", "name").Row(2, Literal("DEFAULT")))
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name") VALUES (?,?),(?,DEFAULT)`, sql)
+
+	v := ValuesTable("v", "id", "qty").Cast("int", "").Row(1, 10).Row(2, 20)
+	_, sql, err = Fingerprint(users.Set("qty").To(v.Col("qty")).From(v).Where(users.Col("id").Eq(v.Col("id"))))
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "qty"="v"."qty" FROM (VALUES (CAST(? AS int),?)) AS "v"("id","qty") WHERE ("users"."id"="v"."id")`, sql)
+
+	_, sql, err = Fingerprint(users.Delete(users.Col("id").In(1, 2)))
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."id" IN(?))`, sql)
+}
+
+func TestFingerprintErrors(t *testing.T) {
+	_, _, err := Fingerprint(Table("users"))
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	users := Table("users")
+	hash, sql, err := Fingerprint(users.Select(users.Col("id")).InnerJoin(42))
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	assert.Equal(t, "", hash)
+	assert.Equal(t, "", sql)
+}
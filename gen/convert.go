@@ -0,0 +1,755 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/janmentzel/codex"
+)
+
+// ConvertOptions configure Convert.
+type ConvertOptions struct {
+	Dialect       string // "POSTGRES" or "MYSQL"
+	DefaultSchema string // tables of this schema are not qualified e.g. "public"
+}
+
+// Convert returns Go statements building the SELECT, INSERT, UPDATE or DELETE statement sql
+// with codex, see codex.DbDialect.Parse for the supported SQL:
+//
+//	psql := codex.Dialect(codex.POSTGRES)
+//	users := psql.Table("users")
+//	query := users.Select(users.Col("id"), users.Col("name")).
+//		Where(users.Col("active").Eq(true)).
+//		Where(users.Col("id").Eq(args[0]))
+//
+// Inline literals compared with, inserted into or set to columns and the values of LIMIT and OFFSET
+// become bound arguments, the placeholders of sql become args[i] of the args passed along with sql.
+// Unqualified columns of a statement with a single table are qualified with it.
+// Table aliases are replaced with their tables, a table used twice with aliases returns an error.
+// Expressions without builder e.g. CASE are kept as codex.Literal.
+func Convert(sql string, opts ConvertOptions) ([]byte, error) {
+	var db codex.DbDialect
+	var dialect string
+	switch opts.Dialect {
+	case "POSTGRES":
+		db, dialect = codex.Dialect(codex.POSTGRES), "psql"
+	case "MYSQL":
+		db, dialect = codex.Dialect(codex.MYSQL), "mysql"
+	default:
+		return nil, fmt.Errorf("unknown dialect %q", opts.Dialect)
+	}
+
+	n, err := db.Placeholders(sql)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = convertArg(i)
+	}
+	m, err := db.Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &converter{
+		opts:    opts,
+		dialect: dialect,
+		vars:    map[*codex.TableNode]string{},
+		aliases: map[*codex.TableNode]*codex.TableNode{},
+		names:   map[string]bool{dialect: true, "args": true, "query": true, "codex": true},
+	}
+	var query string
+	switch m := m.(type) {
+	case *codex.SelectManager:
+		c.findAliases(m.Tree)
+		query = c.selection(m.Tree, "\n\t")
+	case *codex.InsertManager:
+		query = c.insertion(m.Tree)
+	case *codex.UpdateManager:
+		c.findAliases(m.Tree)
+		query = c.modification(m.Tree)
+	case *codex.DeleteManager:
+		query = c.deletion(m.Tree)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s := codex.Dialect(codex.%s)\n", dialect, opts.Dialect)
+	for _, decl := range c.decls {
+		fmt.Fprintln(&b, decl)
+	}
+	fmt.Fprintf(&b, "query := %s\n", query)
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// convertArg stands for the argument of the placeholder i while converting.
+type convertArg int
+
+type converter struct {
+	opts    ConvertOptions
+	dialect string                                // variable of the dialect
+	decls   []string                              // declarations of the tables
+	vars    map[*codex.TableNode]string           // variables of the declared tables
+	aliases map[*codex.TableNode]*codex.TableNode // tables of the alias tables
+	names   map[string]bool                       // names of the declared variables
+	scopes  []convertScope
+	err     error // first error, see fail
+}
+
+// convertScope is the scope of the columns of a statement.
+type convertScope struct {
+	table   string          // variable of the single table of the statement, empty if there are several
+	aliases map[string]bool // aliases of the SELECT columns, never qualified
+}
+
+func (c *converter) fail(o interface{}) string {
+	if c.err == nil {
+		c.err = fmt.Errorf("unsupported node %T", o)
+	}
+	return "nil"
+}
+
+// findAliases maps the alias tables of `FROM table alias` to their tables.
+func (c *converter) findAliases(tree interface{}) {
+	var aliases []*codex.TableNode
+	sources := map[*codex.TableNode]int{}
+	source := func(o interface{}) {
+		switch o := o.(type) {
+		case *codex.TableNode:
+			sources[o]++
+		case *codex.AsNode:
+			table, ok := o.Left.(*codex.TableNode)
+			alias, isTable := o.Right.(*codex.TableNode)
+			if ok && isTable {
+				sources[table]++
+				c.aliases[alias] = table
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+	codex.Walk(tree, func(o interface{}) bool {
+		switch o := o.(type) {
+		case *codex.JoinSourceNode:
+			source(o.Left)
+			for _, join := range o.Right {
+				switch join := join.(type) {
+				case *codex.InnerJoinNode:
+					source(join.Left)
+				case *codex.OuterJoinNode:
+					source(join.Left)
+				}
+			}
+		case *codex.UpdateStatementNode:
+			source(o.Table)
+			for _, from := range o.Froms {
+				source(from)
+			}
+		}
+		return true
+	})
+
+	for _, alias := range aliases {
+		if table := c.aliases[alias]; 1 < sources[table] && c.err == nil {
+			c.err = fmt.Errorf("table %s is used twice, alias %s is not supported", table.Name, alias.Name)
+		}
+	}
+}
+
+// declare declares a variable named after the SQL name and returns it.
+func (c *converter) declare(sqlName, code string) string {
+	name := goName(sqlName)
+	name = strings.ToLower(name[:1]) + name[1:]
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil {
+		name += "Table"
+	}
+	for i := 2; c.names[name]; i++ {
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+	c.names[name] = true
+	c.decls = append(c.decls, name+" := "+code)
+	return name
+}
+
+// table returns the variable of the table, declared on first use.
+func (c *converter) table(t *codex.TableNode) string {
+	if table, ok := c.aliases[t]; ok {
+		t = table
+	}
+	if name, ok := c.vars[t]; ok {
+		return name
+	}
+	code := fmt.Sprintf("%s.Table(%q)", c.dialect, t.Name)
+	if t.Name == "" {
+		return code
+	}
+	if t.Schema != "" && (t.Schema != c.opts.DefaultSchema || t.Catalog != "") {
+		code += fmt.Sprintf(".InSchema(%q)", t.Schema)
+	}
+	if t.Catalog != "" {
+		code += fmt.Sprintf(".InCatalog(%q)", t.Catalog)
+	}
+	c.vars[t] = c.declare(t.Name, code)
+	return c.vars[t]
+}
+
+// source returns the variable of a table, a derived table or a VALUES list of FROM or JOIN.
+func (c *converter) source(o interface{}) string {
+	switch o := o.(type) {
+	case *codex.TableNode:
+		return c.table(o)
+	case *codex.AsNode:
+		if table, ok := o.Left.(*codex.TableNode); ok {
+			return c.table(table)
+		}
+	case *codex.DerivedTableNode:
+		c.vars[o.Table] = c.declare(o.Table.Name, fmt.Sprintf("codex.From(%s, %q)", c.selection(o.Expr, ""), o.Table.Name))
+		return c.vars[o.Table]
+	case *codex.ValuesTableNode:
+		code := "codex.ValuesTable(" + quoteAll(append([]string{o.Table.Name}, o.Columns...)) + ")"
+		if o.Types != nil {
+			code += ".Cast(" + quoteAll(o.Types) + ")"
+		}
+		for _, row := range o.Rows {
+			code += ".Row(" + c.list(row, c.value) + ")"
+		}
+		c.vars[o.Table] = c.declare(o.Table.Name, code)
+		return c.vars[o.Table]
+	}
+	return c.fail(o)
+}
+
+func (c *converter) scope() convertScope {
+	if 0 == len(c.scopes) {
+		return convertScope{}
+	}
+	return c.scopes[len(c.scopes)-1]
+}
+
+// selection returns the SelectManager of stm, its method calls are separated by sep.
+func (c *converter) selection(stm *codex.SelectStatementNode, sep string) string {
+	from := c.source(stm.Source.Left)
+	scope := convertScope{aliases: map[string]bool{}}
+	switch source := stm.Source.Left.(type) {
+	case *codex.TableNode:
+		if source.Name != "" && 0 == len(stm.Source.Right) {
+			scope.table = from
+		}
+	case *codex.ValuesTableNode:
+		// the manager of a VALUES list gets the adapter of its table
+		c.decls = append(c.decls, fmt.Sprintf("%s.Table = %s.Table(%q)", from, c.dialect, source.Table.Name))
+		if 0 == len(stm.Source.Right) {
+			scope.table = from
+		}
+	default:
+		if 0 == len(stm.Source.Right) {
+			scope.table = from
+		}
+	}
+	c.scopes = append(c.scopes, scope)
+	defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+
+	// the sources are declared before their columns are used
+	var joins []string
+	for _, join := range stm.Source.Right {
+		switch join := join.(type) {
+		case *codex.InnerJoinNode:
+			joins = append(joins, "InnerJoin("+c.source(join.Left)+")"+c.on(join.Right))
+		case *codex.OuterJoinNode:
+			joins = append(joins, "OuterJoin("+c.source(join.Left)+")"+c.on(join.Right))
+		default:
+			c.fail(join)
+		}
+	}
+
+	calls := append([]string{"Select(" + c.list(stm.Cols, c.expr) + ")"}, joins...)
+	calls = append(calls, c.wheres(stm.Wheres)...)
+
+	// GROUP BY, HAVING and ORDER BY may refer to the aliases of the columns
+	for _, col := range stm.Cols {
+		if as, ok := col.(*codex.AsNode); ok {
+			if alias, ok := as.Right.(*codex.ColumnNode); ok {
+				scope.aliases[fmt.Sprint(alias.Expr)] = true
+			}
+		}
+	}
+	if 0 < len(stm.Groups) {
+		calls = append(calls, "Group("+c.list(stm.Groups, c.expr)+")")
+	}
+	if having, ok := stm.Having.(*codex.HavingNode); ok {
+		calls = append(calls, "Having("+c.expr(having.Expr)+")")
+	}
+	for _, combinator := range stm.Combinators {
+		calls = append(calls, c.combinator(combinator))
+	}
+	if 0 < len(stm.Combinators) {
+		// ORDER BY of the combined result refers to its columns
+		c.scopes = append(c.scopes, convertScope{})
+		defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+	}
+	for _, order := range stm.Orders {
+		calls = append(calls, "Order("+c.expr(order)+")")
+	}
+	if stm.Limit != nil {
+		calls = append(calls, "Limit("+c.count(stm.Limit.Expr)+")")
+	}
+	if stm.Offset != nil {
+		calls = append(calls, "Offset("+c.count(stm.Offset.Expr)+")")
+	}
+	return from + "." + strings.Join(calls, "."+sep)
+}
+
+func (c *converter) on(o interface{}) string {
+	if on, ok := o.(*codex.OnNode); ok {
+		return ".On(" + c.expr(on.Expr) + ")"
+	}
+	return ""
+}
+
+func (c *converter) wheres(wheres []interface{}) (calls []string) {
+	for _, where := range wheres {
+		// Where encloses the condition in parentheses again
+		if grouping, ok := where.(*codex.GroupingNode); ok {
+			where = grouping.Expr
+		}
+		calls = append(calls, "Where("+c.expr(where)+")")
+	}
+	return
+}
+
+func (c *converter) combinator(o interface{}) string {
+	var method string
+	var right interface{}
+	switch o := o.(type) {
+	case *codex.UnionNode:
+		method, right = "Union", o.Right
+	case *codex.UnionAllNode:
+		method, right = "UnionAll", o.Right
+	case *codex.IntersectNode:
+		method, right = "Intersect", o.Right
+	case *codex.IntersectAllNode:
+		method, right = "IntersectAll", o.Right
+	case *codex.ExceptNode:
+		method, right = "Except", o.Right
+	case *codex.ExceptAllNode:
+		method, right = "ExceptAll", o.Right
+	default:
+		return c.fail(o)
+	}
+	stm, ok := right.(*codex.SelectStatementNode)
+	if !ok {
+		return c.fail(right)
+	}
+	return method + "(" + c.selection(stm, "") + ")"
+}
+
+// count returns the int of SelectManager.Limit and Offset.
+func (c *converter) count(o interface{}) string {
+	switch o := o.(type) {
+	case convertArg:
+		return fmt.Sprintf("args[%d].(int)", int(o))
+	case *codex.LiteralNode:
+		if n, err := strconv.Atoi(o.Sql); err == nil && 0 == len(o.Args) {
+			return strconv.Itoa(n)
+		}
+	}
+	return c.fail(o)
+}
+
+func (c *converter) insertion(tree *codex.InsertStatementNode) string {
+	code := c.table(tree.Table) + ".Insert(" + c.list(tree.Values.Expressions, c.value) + ")"
+	if 0 < len(tree.Values.Columns) {
+		code += ".\n\tInto(" + c.list(tree.Values.Columns, c.column) + ")"
+	}
+	for _, row := range tree.Values.Rows {
+		code += ".\n\tRow(" + c.list(row, c.value) + ")"
+	}
+	if tree.Returning != nil {
+		code += ".\n\tReturning(" + c.column(tree.Returning) + ")"
+	}
+	return code
+}
+
+func (c *converter) modification(tree *codex.UpdateStatementNode) string {
+	table := c.table(tree.Table)
+	var froms []string
+	for _, from := range tree.Froms {
+		froms = append(froms, c.source(from))
+	}
+	scope := convertScope{}
+	if 0 == len(froms) {
+		scope.table = table
+	}
+	c.scopes = append(c.scopes, scope)
+	defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+
+	var columns, values []interface{}
+	for _, value := range tree.Values {
+		assignment, ok := value.(*codex.AssignmentNode)
+		if !ok {
+			return c.fail(value)
+		}
+		columns = append(columns, assignment.Left)
+		values = append(values, assignment.Right)
+	}
+
+	calls := []string{"Set(" + c.list(columns, c.column) + ").To(" + c.list(values, c.value) + ")"}
+	if 0 < len(froms) {
+		calls = append(calls, "From("+strings.Join(froms, ", ")+")")
+	}
+	calls = append(calls, c.wheres(tree.Wheres)...)
+	if tree.Limit != nil {
+		calls = append(calls, "Limit("+c.value(tree.Limit.Expr)+")")
+	}
+	return table + "." + strings.Join(calls, ".\n\t")
+}
+
+func (c *converter) deletion(tree *codex.DeleteStatementNode) string {
+	table := c.table(tree.Table)
+	c.scopes = append(c.scopes, convertScope{table: table})
+	defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+
+	calls := []string{"Deletion()"}
+	if wheres := c.wheres(tree.Wheres); 0 < len(wheres) {
+		calls = append([]string{"Delete" + strings.TrimPrefix(wheres[0], "Where")}, wheres[1:]...)
+	}
+	if tree.Limit != nil {
+		calls = append(calls, "Limit("+c.value(tree.Limit.Expr)+")")
+	}
+	return table + "." + strings.Join(calls, ".\n\t")
+}
+
+// column returns a column name of INSERT, SET or RETURNING as string.
+func (c *converter) column(o interface{}) string {
+	switch o := o.(type) {
+	case string:
+		return strconv.Quote(o)
+	case *codex.ColumnNode:
+		if name, ok := o.Expr.(string); ok {
+			return strconv.Quote(name)
+		}
+	}
+	return c.expr(o)
+}
+
+// isAttribute is true for the nodes rendered as AttributeNode.
+func (c *converter) isAttribute(o interface{}) bool {
+	switch o := o.(type) {
+	case *codex.AttributeNode:
+		return true
+	case *codex.ColumnNode:
+		scope := c.scope()
+		return scope.table != "" && !scope.aliases[fmt.Sprint(o.Expr)]
+	}
+	return false
+}
+
+// hasMethods is true for the nodes rendered with comparison methods e.g. Eq.
+func (c *converter) hasMethods(o interface{}) bool {
+	_, ok := o.(*codex.FunctionNode)
+	return ok || c.isAttribute(o)
+}
+
+// receiver returns the code of o to call a method on.
+func (c *converter) receiver(o interface{}) string {
+	code := c.expr(o)
+	if strings.HasPrefix(code, "&") {
+		return "(" + code + ")"
+	}
+	return code
+}
+
+func (c *converter) list(items []interface{}, item func(interface{}) string) string {
+	codes := make([]string, len(items))
+	for i, o := range items {
+		codes[i] = item(o)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// value returns the Go value of an inline SQL literal to bind it as argument, the code of other nodes like expr.
+func (c *converter) value(o interface{}) string {
+	if literal, ok := o.(*codex.LiteralNode); ok && 0 == len(literal.Args) {
+		if value, ok := c.goValue(literal.Sql, true); ok {
+			return value
+		}
+	}
+	return c.expr(o)
+}
+
+// goValue returns the Go value of a SQL number or string, of TRUE and FALSE if keywords is true.
+// NULL is kept as literal as Eq(nil) renders IS NULL.
+func (c *converter) goValue(sql string, keywords bool) (string, bool) {
+	if upper := strings.ToUpper(sql); keywords && (upper == "TRUE" || upper == "FALSE") {
+		return strings.ToLower(sql), true
+	}
+	if sql == "" {
+		return "", false
+	}
+	switch first := sql[0]; {
+	case first == '\'' || first == '"' && c.opts.Dialect == "MYSQL":
+		return strconv.Quote(unquoteSql(sql, c.opts.Dialect == "MYSQL")), true
+	case '0' <= first && first <= '9' || first == '.' || first == '-':
+		if n, err := strconv.ParseInt(sql, 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), true
+		}
+		if strings.Trim(sql, "-.0123456789eE") == "" && strings.ContainsAny(sql, ".eE") {
+			if f, err := strconv.ParseFloat(sql, 64); err == nil {
+				s := strconv.FormatFloat(f, 'g', -1, 64)
+				if !strings.ContainsAny(s, ".e") {
+					s += ".0"
+				}
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// unquoteSql returns the content of a quoted SQL string, with MySQL's backslash escapes if backslash is true.
+func unquoteSql(sql string, backslash bool) string {
+	quote, body := sql[0], sql[1:len(sql)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		switch {
+		case ch == quote && i+1 < len(body) && body[i+1] == quote:
+			i++
+		case ch == '\\' && backslash && i+1 < len(body):
+			i++
+			switch ch = body[i]; ch {
+			case 'n':
+				ch = '\n'
+			case 't':
+				ch = '\t'
+			case 'r':
+				ch = '\r'
+			case 'b':
+				ch = '\b'
+			case '0':
+				ch = 0
+			case 'Z':
+				ch = 26
+			case '%', '_': // kept for LIKE patterns
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// expr returns the code of an expression.
+func (c *converter) expr(o interface{}) string {
+	switch o := o.(type) {
+	case nil:
+		return "nil"
+	case convertArg:
+		return fmt.Sprintf("args[%d]", int(o))
+	case *codex.AttributeNode:
+		switch name := o.Name.(type) {
+		case *codex.ColumnNode:
+			return fmt.Sprintf("%s.Col(%q)", c.table(o.Table), name.Expr)
+		case *codex.StarNode:
+			return c.table(o.Table) + ".Star()"
+		}
+	case *codex.ColumnNode:
+		if c.isAttribute(o) {
+			return fmt.Sprintf("%s.Col(%q)", c.scope().table, o.Expr)
+		}
+		return fmt.Sprintf("codex.Column(%q)", o.Expr)
+	case *codex.StarNode:
+		return "codex.Star()"
+	case *codex.LiteralNode:
+		return c.literal(o.Sql, c.exprs(o.Args))
+	case *codex.EqualNode:
+		return c.comparison("Eq", "Equal", o.Left, o.Right)
+	case *codex.NotEqualNode:
+		return c.comparison("Neq", "NotEqual", o.Left, o.Right)
+	case *codex.GreaterThanNode:
+		return c.comparison("Gt", "GreaterThan", o.Left, o.Right)
+	case *codex.GreaterThanOrEqualNode:
+		return c.comparison("Gte", "GreaterThanOrEqual", o.Left, o.Right)
+	case *codex.LessThanNode:
+		return c.comparison("Lt", "LessThan", o.Left, o.Right)
+	case *codex.LessThanOrEqualNode:
+		return c.comparison("Lte", "LessThanOrEqual", o.Left, o.Right)
+	case *codex.LikeNode:
+		return c.comparison("Like", "Like", o.Left, o.Right)
+	case *codex.UnlikeNode:
+		return c.comparison("Unlike", "Unlike", o.Left, o.Right)
+	case *codex.InNode:
+		return c.in(o)
+	case *codex.AndNode:
+		return fmt.Sprintf("codex.And(%s, %s)", c.expr(o.Left), c.expr(o.Right))
+	case *codex.OrNode:
+		return fmt.Sprintf("codex.Or(%s, %s)", c.expr(o.Left), c.expr(o.Right))
+	case *codex.NotNode:
+		return fmt.Sprintf("codex.Not(%s)", c.expr(o.Expr))
+	case *codex.GroupingNode:
+		// a SelectManager renders as sub select in parentheses
+		if stm, ok := o.Expr.(*codex.SelectStatementNode); ok {
+			return c.selection(stm, "")
+		}
+		return fmt.Sprintf("codex.Grouping(%s)", c.expr(o.Expr))
+	case *codex.FunctionNode:
+		return c.function(o)
+	case *codex.AsNode:
+		alias, ok := o.Right.(*codex.ColumnNode)
+		if !ok {
+			break
+		}
+		if c.hasMethods(o.Left) {
+			return fmt.Sprintf("%s.As(%q)", c.receiver(o.Left), alias.Expr)
+		}
+		return fmt.Sprintf("codex.As(%s, codex.Column(%q))", c.expr(o.Left), alias.Expr)
+	case *codex.AscendingNode:
+		if c.isAttribute(o.Expr) {
+			return c.expr(o.Expr) + ".Asc()"
+		}
+		return fmt.Sprintf("codex.Ascending(%s)", c.expr(o.Expr))
+	case *codex.DescendingNode:
+		if c.isAttribute(o.Expr) {
+			return c.expr(o.Expr) + ".Desc()"
+		}
+		return fmt.Sprintf("codex.Descending(%s)", c.expr(o.Expr))
+	case *codex.BinaryLiteralNode:
+		return c.binaryLiteral(o)
+	case *codex.SelectStatementNode:
+		return c.selection(o, "") + ".Tree"
+	}
+	return c.fail(o)
+}
+
+func (c *converter) exprs(items []interface{}) []string {
+	codes := make([]string, len(items))
+	for i, o := range items {
+		codes[i] = c.expr(o)
+	}
+	return codes
+}
+
+// comparison returns e.g. left.Eq(right) or codex.Equal(left, right) if left has no method Eq.
+func (c *converter) comparison(method, factory string, left, right interface{}) string {
+	if c.hasMethods(left) {
+		return fmt.Sprintf("%s.%s(%s)", c.receiver(left), method, c.value(right))
+	}
+	return fmt.Sprintf("codex.%s(%s, %s)", factory, c.expr(left), c.value(right))
+}
+
+func (c *converter) in(o *codex.InNode) string {
+	values, ok := o.Right.([]interface{})
+	if !ok {
+		return c.fail(o.Right)
+	}
+	codes := make([]string, len(values))
+	for i, value := range values {
+		codes[i] = c.value(value)
+		if _, ok := value.(*codex.SelectStatementNode); ok {
+			codes[i] = c.expr(value)
+		}
+	}
+	if c.isAttribute(o.Left) {
+		return fmt.Sprintf("%s.In(%s)", c.expr(o.Left), strings.Join(codes, ", "))
+	}
+	return fmt.Sprintf("codex.In(%s)", strings.Join(append([]string{c.expr(o.Left)}, codes...), ", "))
+}
+
+// functions are the FunctionNode factories of codex by function name.
+var functions = map[string]string{
+	"AVG":       "Avg",
+	"COALESCE":  "Coalesce",
+	"COUNT":     "Count",
+	"MAX":       "Max",
+	"MIN":       "Min",
+	"SUM":       "Sum",
+	"LOWER":     "Lower",
+	"UPPER":     "Upper",
+	"SUBSTRING": "Substring",
+}
+
+func (c *converter) function(o *codex.FunctionNode) string {
+	args := c.exprs(o.Args)
+	// Function without args renders name(*)
+	if o.Distinct || o.Alias != nil || o.Args != nil && 0 == len(o.Args) {
+		code := fmt.Sprintf("&codex.FunctionNode{Name: %q, Args: []interface{}{%s}", o.Name, strings.Join(args, ", "))
+		if o.Distinct {
+			code += ", Distinct: true"
+		}
+		if o.Alias != nil {
+			code += fmt.Sprintf(", Alias: %s", c.column(o.Alias))
+		}
+		return code + "}"
+	}
+	if factory, ok := functions[strings.ToUpper(o.Name)]; ok {
+		return "codex." + factory + "(" + strings.Join(args, ", ") + ")"
+	}
+	return fmt.Sprintf("codex.Function(%s)", strings.Join(append([]string{strconv.Quote(o.Name)}, args...), ", "))
+}
+
+// binaryLiteral returns column.Literal("op ?", value) for operators without node of their own e.g. BETWEEN.
+func (c *converter) binaryLiteral(o *codex.BinaryLiteralNode) string {
+	if c.isAttribute(o.Left) {
+		if sql, args, ok := c.flat(o.Right); ok {
+			return fmt.Sprintf("%s.Literal(%s)", c.expr(o.Left), literalArgs(sql, args))
+		}
+	}
+	if sql, args, ok := c.flat(o); ok {
+		return c.literal(sql, args)
+	}
+	return fmt.Sprintf("&codex.BinaryLiteralNode{Left: %s, Right: %s}", c.expr(o.Left), c.expr(o.Right))
+}
+
+// flat returns the SQL and args of literals, inline values and BinaryLiteralNodes of them.
+func (c *converter) flat(o interface{}) (string, []string, bool) {
+	switch o := o.(type) {
+	case convertArg:
+		return "?", []string{c.expr(o)}, true
+	case *codex.LiteralNode:
+		if value, ok := c.goValue(o.Sql, false); ok && 0 == len(o.Args) {
+			return "?", []string{value}, true
+		}
+		return o.Sql, c.exprs(o.Args), true
+	case *codex.BinaryLiteralNode:
+		left, leftArgs, ok := c.flat(o.Left)
+		if !ok {
+			return "", nil, false
+		}
+		right, rightArgs, ok := c.flat(o.Right)
+		return left + " " + right, append(leftArgs, rightArgs...), ok
+	}
+	return "", nil, false
+}
+
+// literal returns codex.Literal(sql, args...).
+func (c *converter) literal(sql string, args []string) string {
+	return "codex.Literal(" + literalArgs(sql, args) + ")"
+}
+
+// literalArgs returns the args of codex.Literal, sql in back quotes if it has double quotes.
+func literalArgs(sql string, args []string) string {
+	code := strconv.Quote(sql)
+	if strings.Contains(sql, `"`) && strconv.CanBackquote(sql) {
+		code = "`" + sql + "`"
+	}
+	return strings.Join(append([]string{code}, args...), ", ")
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,133 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"testing"
+
+	"github.com/janmentzel/codex"
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlRenderer interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// convertTests are converted to the functions of convert_golden_test.go by TestConvertGolden,
+// TestConvertRender renders them.
+var convertTests = []struct {
+	name     string
+	dialect  string
+	sql      string
+	args     []interface{}
+	want     string        // sql of the generated code
+	wantArgs []interface{} // args of the generated code
+}{
+	{"select", "POSTGRES",
+		`SELECT id, name FROM users WHERE active = true AND id = $1 ORDER BY name LIMIT 10`, []interface{}{7},
+		`SELECT "users"."id","users"."name" FROM "users" WHERE ("users"."active"=$1) AND ("users"."id"=$2) ORDER BY "users"."name" LIMIT $3`,
+		[]interface{}{true, 7, 10}},
+	{"join", "POSTGRES",
+		`SELECT u.id, p.title FROM users u JOIN posts p ON p.user_id = u.id LEFT JOIN comments c ON c.post_id = p.id
+		 WHERE u.name LIKE 'J%' AND p.published_at IS NOT NULL ORDER BY p.published_at DESC`, nil,
+		`SELECT "users"."id","posts"."title" FROM "users" INNER JOIN "posts" ON "posts"."user_id"="users"."id" LEFT OUTER JOIN "comments" ON "comments"."post_id"="posts"."id" WHERE ("users"."name" LIKE $1) AND ("posts"."published_at" IS NOT NULL) ORDER BY "posts"."published_at" DESC`,
+		[]interface{}{"J%"}},
+	{"aggregate", "POSTGRES",
+		`SELECT user_id, count(*) AS n, SUM(total) FROM orders WHERE state IN ('paid', 'shipped') GROUP BY user_id HAVING COUNT(*) > 5 ORDER BY n DESC`, nil,
+		`SELECT "orders"."user_id",COUNT(*) AS "n",SUM("orders"."total") FROM "orders" WHERE ("orders"."state" IN($1,$2)) GROUP BY "orders"."user_id" HAVING COUNT(*)>$3 ORDER BY "n" DESC`,
+		[]interface{}{"paid", "shipped", 5}},
+	{"subselect", "POSTGRES",
+		`SELECT * FROM users WHERE id IN (SELECT user_id FROM orders WHERE total > 100) AND EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)`, nil,
+		`SELECT * FROM "users" WHERE ("users"."id" IN(SELECT "orders"."user_id" FROM "orders" WHERE ("orders"."total">$1))) AND (EXISTS(SELECT 1 FROM "posts" WHERE ("posts"."user_id"="users"."id")))`,
+		[]interface{}{100}},
+	{"derived", "POSTGRES",
+		`SELECT t.user_id, t.total FROM (SELECT user_id, SUM(total) AS total FROM orders GROUP BY user_id) AS t WHERE t.total > $1`, []interface{}{50},
+		`SELECT "t"."user_id","t"."total" FROM (SELECT "orders"."user_id",SUM("orders"."total") AS "total" FROM "orders" GROUP BY "orders"."user_id") AS "t" WHERE ("t"."total">$1)`,
+		[]interface{}{50}},
+	{"union", "POSTGRES",
+		`SELECT id FROM users WHERE active = $1 UNION ALL SELECT id FROM admins ORDER BY id LIMIT $2 OFFSET 20`, []interface{}{true, 10},
+		`SELECT "users"."id" FROM "users" WHERE ("users"."active"=$1) UNION ALL SELECT "admins"."id" FROM "admins" ORDER BY "id" LIMIT $2 OFFSET $3`,
+		[]interface{}{true, 10, 20}},
+	{"expressions", "POSTGRES",
+		`SELECT id FROM products WHERE price BETWEEN 10 AND 20.5 AND tags @> ARRAY['a'] AND attrs ? 'color' AND id <> $1 AND NOT (deleted OR hidden)
+		 AND created_at > now() - INTERVAL '1 day' AND CAST(stock AS int) >= 3 AND CASE WHEN a THEN b END`, []interface{}{9},
+		`SELECT "products"."id" FROM "products" WHERE ("products"."price" BETWEEN $1 AND $2) AND ("products"."tags" @> ARRAY['a']) AND ("products"."attrs" ? $3) AND ("products"."id"!=$4) AND (NOT("products"."deleted" OR "products"."hidden")) AND ("products"."created_at">now() - INTERVAL '1 day') AND (CAST(stock AS int)>=$5) AND (CASE WHEN a THEN b END)`,
+		[]interface{}{10, 20.5, "color", 9, 3}},
+	{"distinct", "POSTGRES",
+		`SELECT DISTINCT email FROM public.users`, nil,
+		`SELECT DISTINCT "users"."email" FROM "users"`, nil},
+	{"insert", "POSTGRES",
+		`INSERT INTO users (name, email, active) VALUES ('Jon', $1, TRUE), ('Ann', $2, DEFAULT) RETURNING id`, []interface{}{"jon@example.com", "ann@example.com"},
+		`INSERT INTO "users" ("name","email","active") VALUES ($1,$2,$3),($4,$5,DEFAULT) RETURNING "id"`,
+		[]interface{}{"Jon", "jon@example.com", true, "Ann", "ann@example.com"}},
+	{"update", "POSTGRES",
+		`UPDATE users SET name = 'x', visits = visits + 1, seen_at = NULL WHERE id = $1`, []interface{}{7},
+		`UPDATE "users" SET "name"=$1,"visits"="users"."visits" + $2,"seen_at"=NULL WHERE ("users"."id"=$3)`,
+		[]interface{}{"x", 1, 7}},
+	{"update_values", "POSTGRES",
+		`UPDATE items SET qty = v.qty FROM (VALUES (CAST($1 AS bigint), $2), (CAST($3 AS bigint), $4)) AS v (id, qty) WHERE items.id = v.id`, []interface{}{1, 10, 2, 20},
+		`UPDATE "items" SET "qty"="v"."qty" FROM (VALUES (CAST($1 AS bigint),$2),(CAST($3 AS bigint),$4)) AS "v"("id","qty") WHERE ("items"."id"="v"."id")`,
+		[]interface{}{1, 10, 2, 20}},
+	{"select_values", "POSTGRES",
+		`SELECT v.id FROM (VALUES (1), (2)) AS v (id)`, nil,
+		`SELECT "v"."id" FROM (VALUES ($1),($2)) AS "v"("id")`,
+		[]interface{}{1, 2}},
+	{"delete", "MYSQL",
+		"DELETE FROM `shop`.`sessions` WHERE expires_at < NOW() AND user_id = 7 LIMIT 100", nil,
+		"DELETE FROM `shop`.`sessions` WHERE (`shop`.`sessions`.`expires_at`<NOW()) AND (`shop`.`sessions`.`user_id`=?) LIMIT ?",
+		[]interface{}{7, 100}},
+	{"mysql", "MYSQL",
+		"SELECT `name` AS `type` FROM `type` WHERE nick = \"it\\'s\" OR nick = ? LIMIT 5, 10", []interface{}{"x"},
+		"SELECT `type`.`name` AS `type` FROM `type` WHERE (`type`.`nick`=? OR `type`.`nick`=?) LIMIT ? OFFSET ?",
+		[]interface{}{"it's", "x", 10, 5}},
+}
+
+func TestConvertGolden(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by TestConvertGolden with -update. DO NOT EDIT.\n\npackage gen\n\n")
+	b.WriteString("import \"github.com/janmentzel/codex\"\n\n")
+	b.WriteString("var convertGolden = map[string]func(args ...interface{}) sqlRenderer{\n")
+	for _, test := range convertTests {
+		src, err := Convert(test.sql, ConvertOptions{Dialect: test.dialect, DefaultSchema: "public"})
+		assert.Nil(t, err, test.name)
+		fmt.Fprintf(&b, "%q: func(args ...interface{}) sqlRenderer {\n%sreturn query\n},\n", test.name, src)
+	}
+	b.WriteString("}\n")
+	src, err := format.Source(b.Bytes())
+	assert.Nil(t, err)
+
+	if *update {
+		assert.Nil(t, os.WriteFile("convert_golden_test.go", src, 0644))
+	}
+	want, err := os.ReadFile("convert_golden_test.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(want), string(src))
+}
+
+// TestConvertRender renders the generated code of convert_golden_test.go.
+func TestConvertRender(t *testing.T) {
+	for _, test := range convertTests {
+		build, ok := convertGolden[test.name]
+		if !assert.True(t, ok, test.name) {
+			continue
+		}
+		sql, args, err := build(test.args...).ToSql()
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.want, sql, test.name)
+		assert.Equal(t, test.wantArgs, args, test.name)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	_, err := Convert("SELECT a.id FROM users a JOIN users b ON b.parent_id = a.id", ConvertOptions{Dialect: "POSTGRES"})
+	assert.EqualError(t, err, "table users is used twice, alias a is not supported")
+
+	_, err = Convert("SELECT id FROM users WHERE", ConvertOptions{Dialect: "POSTGRES"})
+	assert.True(t, errors.Is(err, codex.ErrSyntax))
+
+	_, err = Convert("SELECT 1", ConvertOptions{Dialect: "SQLITE"})
+	assert.EqualError(t, err, `unknown dialect "SQLITE"`)
+}
@@ -0,0 +1,560 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janmentzel/codex"
+)
+
+// ParseDDL returns the tables of the CREATE TABLE statements of src e.g. a pg_dump or mysqldump schema file.
+// Primary and foreign keys are read from column and table constraints
+// as well as from ALTER TABLE ... ADD PRIMARY KEY / FOREIGN KEY statements, other statements are skipped.
+func ParseDDL(src string) (*codex.Definitions, error) {
+	tokens, err := tokenizeDDL(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &ddlParser{src: src, tokens: tokens, defs: codex.NewDefinitions()}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+	return p.defs, nil
+}
+
+// Kinds of ddlTokens.
+const (
+	tokWord   = 'w' // keyword or unquoted identifier
+	tokQuoted = 'q' // quoted identifier
+	tokString = 's' // string or dollar quoted string
+	tokPunct  = 'p' // single byte e.g. ( ) , ; .
+)
+
+type ddlToken struct {
+	kind       byte
+	text       string // unquoted text of identifiers
+	start, end int    // position within the source
+}
+
+// tokenizeDDL splits src into tokens, comments are skipped.
+func tokenizeDDL(src string) (tokens []ddlToken, err error) {
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "--"):
+			if j := strings.IndexByte(src[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(src)
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", lineOf(src, i))
+			}
+			i += j + 4
+		case c == '\'' || c == '"' || c == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == c {
+					if j+1 < len(src) && src[j+1] == c { // doubled quote
+						b.WriteByte(c)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated quote %c", lineOf(src, i), c)
+			}
+			kind := byte(tokQuoted)
+			if c == '\'' {
+				kind = tokString
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: b.String(), start: i, end: j + 1})
+			i = j + 1
+		case c == '$' && dollarTag(src, i) != "":
+			tag := dollarTag(src, i)
+			j := strings.Index(src[i+len(tag):], tag)
+			if j < 0 {
+				return nil, fmt.Errorf("line %d: unterminated dollar quote %s", lineOf(src, i), tag)
+			}
+			end := i + len(tag) + j + len(tag)
+			tokens = append(tokens, ddlToken{kind: tokString, text: src[i+len(tag) : end-len(tag)], start: i, end: end})
+			i = end
+		case isWordByte(c):
+			j := i + 1
+			for j < len(src) && (isWordByte(src[j]) || src[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, ddlToken{kind: tokWord, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, ddlToken{kind: tokPunct, text: src[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return
+}
+
+// dollarTag returns the tag of the dollar quote at src[i] e.g. "$$" or "$body$", empty if there is none.
+func dollarTag(src string, i int) string {
+	j := i + 1
+	for j < len(src) && (isWordByte(src[j]) && !('0' <= src[j] && src[j] <= '9' && j == i+1)) {
+		j++
+	}
+	if j < len(src) && src[j] == '$' {
+		return src[i : j+1]
+	}
+	return ""
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c >= 0x80
+}
+
+func lineOf(src string, pos int) int {
+	return strings.Count(src[:pos], "\n") + 1
+}
+
+// columnKeywords end the type and default expression of a column definition.
+var columnKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "REFERENCES": true, "UNIQUE": true,
+	"CHECK": true, "CONSTRAINT": true, "COLLATE": true, "AUTO_INCREMENT": true, "AUTOINCREMENT": true,
+	"GENERATED": true, "COMMENT": true, "ON": true, "CHARSET": true, "KEY": true,
+}
+
+// tableConstraints start a table constraint instead of a column definition.
+var tableConstraints = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "FOREIGN": true, "UNIQUE": true, "CHECK": true,
+	"KEY": true, "INDEX": true, "FULLTEXT": true, "SPATIAL": true, "EXCLUDE": true,
+}
+
+type ddlParser struct {
+	src    string
+	tokens []ddlToken
+	pos    int
+	defs   *codex.Definitions
+	refs   []*codex.ForeignKey // column references without column, resolved to the primary key
+}
+
+func (p *ddlParser) parse() error {
+	for p.pos < len(p.tokens) {
+		var err error
+		switch {
+		case p.word("CREATE"):
+			p.word("TEMPORARY")
+			p.word("TEMP")
+			p.word("UNLOGGED")
+			if p.word("TABLE") {
+				p.words("IF", "NOT", "EXISTS")
+				err = p.createTable()
+			}
+		case p.word("ALTER"):
+			if p.word("TABLE") {
+				p.words("IF", "EXISTS")
+				p.word("ONLY")
+				err = p.alterTable()
+			}
+		}
+		if err != nil {
+			return err
+		}
+		p.skipStatement()
+	}
+
+	for _, ref := range p.refs {
+		if def, ok := p.lookup(ref.Table); ok {
+			if pk := def.PrimaryKey(); len(pk) == 1 {
+				ref.Column = pk[0]
+			}
+		}
+	}
+	return nil
+}
+
+// createTable parses the table definition after CREATE TABLE.
+func (p *ddlParser) createTable() error {
+	table, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	if !p.punct("(") {
+		return nil // e.g. CREATE TABLE ... AS SELECT
+	}
+	def := p.defs.Define(table)
+	for !p.punct(")") {
+		if p.pos >= len(p.tokens) {
+			return p.errorf("expected ')'")
+		}
+		if tok := p.peek(); tok.kind == tokWord && tableConstraints[strings.ToUpper(tok.text)] {
+			err = p.tableConstraint(def)
+		} else {
+			err = p.column(def)
+		}
+		if err != nil {
+			return err
+		}
+		p.punct(",")
+	}
+	return nil
+}
+
+// alterTable parses ALTER TABLE ... ADD [CONSTRAINT name] PRIMARY KEY / FOREIGN KEY.
+func (p *ddlParser) alterTable() error {
+	table, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	def, ok := p.defs.Lookup(table)
+	if !ok || !p.word("ADD") {
+		return nil
+	}
+	if tok := p.peek(); tok.kind == tokWord && tableConstraints[strings.ToUpper(tok.text)] {
+		return p.tableConstraint(def)
+	}
+	return nil
+}
+
+// column parses a column definition.
+func (p *ddlParser) column(def *codex.TableDef) error {
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	col := &codex.ColumnDef{Name: name, Nullable: true}
+	def.Columns = append(def.Columns, col)
+
+	col.Type = strings.ToLower(normalizeSpace(p.raw(p.until(true))))
+	for !p.atEnd() {
+		switch {
+		case p.words("NOT", "NULL"):
+			col.Nullable = false
+		case p.word("NULL"):
+			col.Nullable = true
+		case p.word("DEFAULT"):
+			start := p.pos
+			p.pos++ // at least one token e.g. DEFAULT NULL
+			p.skipGroup()
+			p.until(false)
+			if def := p.raw(start); !strings.EqualFold(def, "NULL") {
+				col.Default = codex.Literal(def)
+			}
+		case p.words("PRIMARY", "KEY"):
+			col.PrimaryKey = true
+			col.Nullable = false
+		case p.word("REFERENCES"):
+			ref, err := p.references()
+			if err != nil {
+				return err
+			}
+			col.References = ref[0]
+			if ref[0].Column == "" {
+				p.refs = append(p.refs, ref[0])
+			}
+		case p.word("AUTO_INCREMENT"), p.word("AUTOINCREMENT"):
+			col.Identity = true
+		case p.word("GENERATED"):
+			// e.g. GENERATED BY DEFAULT AS IDENTITY
+			for !p.atEnd() && !p.punct("(") {
+				if p.word("IDENTITY") {
+					col.Identity = true
+					col.Nullable = false
+					break
+				}
+				p.pos++
+			}
+			p.skipGroup()
+		default:
+			p.pos++
+			p.skipGroup()
+		}
+	}
+	return nil
+}
+
+// tableConstraint parses a table constraint, only primary and foreign keys are kept.
+func (p *ddlParser) tableConstraint(def *codex.TableDef) error {
+	if p.word("CONSTRAINT") {
+		if _, err := p.identifier(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.words("PRIMARY", "KEY"):
+		names, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if col := def.Column(name); col != nil {
+				col.PrimaryKey = true
+				col.Nullable = false
+			}
+		}
+	case p.words("FOREIGN", "KEY"):
+		names, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		if !p.word("REFERENCES") {
+			return p.errorf("expected REFERENCES")
+		}
+		refs, err := p.references()
+		if err != nil {
+			return err
+		}
+		for i, name := range names {
+			col := def.Column(name)
+			if col == nil {
+				continue
+			}
+			if i < len(refs) {
+				col.References = refs[i]
+			} else {
+				col.References = &codex.ForeignKey{Table: refs[0].Table, OnDelete: refs[0].OnDelete}
+				p.refs = append(p.refs, col.References)
+			}
+		}
+	}
+	for !p.atEnd() {
+		p.pos++
+		p.skipGroup()
+	}
+	return nil
+}
+
+// references parses the table, the optional columns and ON DELETE action after REFERENCES.
+func (p *ddlParser) references() ([]*codex.ForeignKey, error) {
+	table, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	key := qualifiedName(table)
+	columns := []string{""}
+	if p.peek().text == "(" {
+		if columns, err = p.columnList(); err != nil {
+			return nil, err
+		}
+	}
+
+	onDelete := ""
+	for {
+		if p.words("ON", "DELETE") {
+			onDelete = p.action()
+		} else if p.words("ON", "UPDATE") {
+			p.action()
+		} else if p.word("MATCH") || p.word("INITIALLY") {
+			p.pos++
+		} else if !p.word("DEFERRABLE") && !p.words("NOT", "DEFERRABLE") {
+			break
+		}
+	}
+
+	refs := make([]*codex.ForeignKey, len(columns))
+	for i, column := range columns {
+		refs[i] = &codex.ForeignKey{Table: key, Column: column, OnDelete: onDelete}
+	}
+	return refs, nil
+}
+
+// action parses the referential action of ON DELETE and ON UPDATE.
+func (p *ddlParser) action() string {
+	for _, action := range [][]string{{"CASCADE"}, {"RESTRICT"}, {"SET", "NULL"}, {"SET", "DEFAULT"}, {"NO", "ACTION"}} {
+		if p.words(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+// tableName parses a table name, optionally qualified by its schema.
+func (p *ddlParser) tableName() (*codex.TableNode, error) {
+	parts := []string{}
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, name)
+		if !p.punct(".") {
+			break
+		}
+	}
+	table := codex.Table(parts[len(parts)-1])
+	if len(parts) > 1 {
+		table.InSchema(parts[len(parts)-2])
+	}
+	return table, nil
+}
+
+// columnList parses (a, b, ...) dropping MySQL prefix lengths and sort orders e.g. (name(10) DESC).
+func (p *ddlParser) columnList() (names []string, err error) {
+	if !p.punct("(") {
+		return nil, p.errorf("expected '('")
+	}
+	for !p.punct(")") {
+		if p.pos >= len(p.tokens) {
+			return nil, p.errorf("expected ')'")
+		}
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		for !p.punct(",") && p.peek().text != ")" && p.pos < len(p.tokens) {
+			p.pos++
+			p.skipGroup()
+		}
+	}
+	return
+}
+
+func (p *ddlParser) identifier() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokWord && tok.kind != tokQuoted {
+		return "", p.errorf("expected identifier")
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+// until advances to the next column keyword, ',' or ')' outside of parentheses and returns the start position.
+// The first token is never a keyword if first is true, e.g. a column type "key".
+func (p *ddlParser) until(first bool) int {
+	start := p.pos
+	for !p.atEnd() && (first && p.pos == start || !p.isColumnKeyword()) {
+		p.pos++
+		p.skipGroup()
+	}
+	return start
+}
+
+// isColumnKeyword is true at a column keyword, CHARACTER SET is one but CHARACTER VARYING is none.
+func (p *ddlParser) isColumnKeyword() bool {
+	tok := p.peek()
+	if tok.kind != tokWord {
+		return false
+	}
+	word := strings.ToUpper(tok.text)
+	if word == "CHARACTER" && p.pos+1 < len(p.tokens) {
+		return strings.EqualFold(p.tokens[p.pos+1].text, "SET")
+	}
+	return columnKeywords[word]
+}
+
+// skipGroup skips the rest of the parenthesized group if the previous token opened one,
+// otherwise a group following the previous token e.g. the (255) of varchar(255).
+func (p *ddlParser) skipGroup() {
+	if prev := p.tokens[p.pos-1]; prev.kind != tokPunct || prev.text != "(" {
+		if !p.punct("(") {
+			return
+		}
+	}
+	for depth := 1; depth > 0 && p.pos < len(p.tokens); p.pos++ {
+		if tok := p.tokens[p.pos]; tok.kind == tokPunct {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+	}
+}
+
+// atEnd is true at ',', ')' or ';' ending a definition.
+func (p *ddlParser) atEnd() bool {
+	tok := p.peek()
+	return p.pos >= len(p.tokens) || tok.kind == tokPunct && (tok.text == "," || tok.text == ")" || tok.text == ";")
+}
+
+// skipStatement advances after the next ';' outside of parentheses.
+func (p *ddlParser) skipStatement() {
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		if tok := p.tokens[p.pos]; tok.kind == tokPunct {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ";":
+				if depth <= 0 {
+					p.pos++
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *ddlParser) peek() ddlToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ddlToken{}
+}
+
+// word consumes the next token if it is the keyword.
+func (p *ddlParser) word(keyword string) bool {
+	return p.words(keyword)
+}
+
+// words consumes the next tokens if they are the keywords.
+func (p *ddlParser) words(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		if tok := p.tokens[p.pos+i]; tok.kind != tokWord || !strings.EqualFold(tok.text, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *ddlParser) punct(s string) bool {
+	if tok := p.peek(); tok.kind == tokPunct && tok.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// raw returns the source from the token at start up to the current token.
+func (p *ddlParser) raw(start int) string {
+	if start >= p.pos {
+		return ""
+	}
+	return p.src[p.tokens[start].start:p.tokens[p.pos-1].end]
+}
+
+// lookup returns the table definition by its (schema qualified) name.
+func (p *ddlParser) lookup(key string) (*codex.TableDef, bool) {
+	table := codex.Table(key)
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		table = codex.Table(key[i+1:]).InSchema(key[:i])
+	}
+	return p.defs.Lookup(table)
+}
+
+func (p *ddlParser) errorf(format string, args ...interface{}) error {
+	pos, near := len(p.src), "end of file"
+	if p.pos < len(p.tokens) {
+		pos, near = p.tokens[p.pos].start, fmt.Sprintf("%q", p.tokens[p.pos].text)
+	}
+	return fmt.Errorf("line %d: %s near %s", lineOf(p.src, pos), fmt.Sprintf(format, args...), near)
+}
+
+// normalizeSpace collapses whitespace and removes it around parentheses and commas e.g. "numeric(10,2)" of a column type.
+func normalizeSpace(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer(" (", "(", "( ", "(", " )", ")", " ,", ",", ", ", ",").Replace(s)
+}
@@ -0,0 +1,313 @@
+// Package gen generates Go table accessors and row structs from schema files, see cmd/codex-gen.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/janmentzel/codex"
+)
+
+// Options configure Generate.
+type Options struct {
+	Package       string // package name of the generated file
+	Dialect       string // "POSTGRES" or "MYSQL"
+	DefaultSchema string // tables of this schema are not qualified e.g. "public"
+}
+
+// Generate returns a formatted Go file with a table struct, a constructor and a row struct per table:
+//
+//	type UsersTable struct {
+//		Table *codex.TableNode
+//		Id    *codex.AttributeNode // bigint NOT NULL PRIMARY KEY
+//		Email *codex.AttributeNode // varchar(255) NOT NULL
+//	}
+//
+//	func NewUsersTable() *UsersTable
+//
+//	type UsersRow struct {
+//		Id    int64  `db:"id,pk"`
+//		Email string `db:"email"`
+//	}
+//
+// Primary key columns are tagged pk, identity and serial columns readonly, see generatedByDatabase.
+func Generate(schema *codex.Definitions, opts Options) ([]byte, error) {
+	switch opts.Dialect {
+	case "POSTGRES", "MYSQL":
+	default:
+		return nil, fmt.Errorf("unknown dialect %q", opts.Dialect)
+	}
+	if opts.Package == "" {
+		return nil, fmt.Errorf("missing package name")
+	}
+
+	file := genFile{Options: opts}
+	imports := map[string]bool{}
+	names := map[string]int{}
+	for _, def := range schema.Tables() {
+		names[goName(def.Table.Name)]++
+	}
+
+	for _, def := range schema.Tables() {
+		t := genTable{Name: def.Table.Name, GoName: goName(def.Table.Name)}
+		if def.Table.Schema != opts.DefaultSchema {
+			t.Schema = def.Table.Schema
+			if names[t.GoName] > 1 {
+				t.GoName = goName(t.Schema) + t.GoName
+			}
+		}
+
+		fields := map[string]bool{"Table": true}
+		for _, col := range def.Columns {
+			c := genColumn{Name: col.Name, GoName: goName(col.Name), Comment: columnComment(col)}
+			for fields[c.GoName] {
+				c.GoName += "Col"
+			}
+			fields[c.GoName] = true
+
+			var pkg string
+			c.GoType, pkg = goType(col.Type, col.Nullable)
+			if pkg != "" {
+				imports[pkg] = true
+			}
+
+			c.Tag = col.Name
+			if col.PrimaryKey {
+				c.Tag += ",pk"
+			} else if generatedByDatabase(col) {
+				c.Tag += ",readonly"
+			}
+			t.Columns = append(t.Columns, c)
+		}
+		file.Tables = append(file.Tables, t)
+	}
+	for pkg := range imports {
+		file.Imports = append(file.Imports, pkg)
+	}
+	sort.Strings(file.Imports)
+
+	var b bytes.Buffer
+	if err := fileTemplate.Execute(&b, file); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+type genFile struct {
+	Options
+	Imports []string
+	Tables  []genTable
+}
+
+type genTable struct {
+	Name, Schema, GoName string
+	Columns              []genColumn
+}
+
+type genColumn struct {
+	Name, GoName, GoType, Tag, Comment string
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by codex-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/janmentzel/codex"
+)
+
+// Dialect of the tables, replace it before creating tables e.g. to add hooks.
+var Dialect = codex.Dialect(codex.{{.Dialect}})
+{{range .Tables}}
+// {{.GoName}}Table is the table {{if .Schema}}{{.Schema}}.{{end}}{{.Name}}.
+type {{.GoName}}Table struct {
+	Table *codex.TableNode
+{{- range .Columns}}
+	{{.GoName}} *codex.AttributeNode // {{.Comment}}
+{{- end}}
+}
+
+// New{{.GoName}}Table returns the table {{if .Schema}}{{.Schema}}.{{end}}{{.Name}} of Dialect.
+func New{{.GoName}}Table() *{{.GoName}}Table {
+	table := Dialect.Table({{printf "%q" .Name}}){{if .Schema}}.InSchema({{printf "%q" .Schema}}){{end}}
+	return &{{.GoName}}Table{
+		Table: table,
+{{- range .Columns}}
+		{{.GoName}}: table.Col({{printf "%q" .Name}}),
+{{- end}}
+	}
+}
+
+// {{.GoName}}Row is a row of the table {{if .Schema}}{{.Schema}}.{{end}}{{.Name}}.
+type {{.GoName}}Row struct {
+{{- range .Columns}}
+	{{.GoName}} {{.GoType}} ` + "`" + `db:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{end}}`))
+
+// goName returns the exported Go name of a snake_case SQL name e.g. "user_id" -> "UserId".
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z':
+			if upper {
+				r -= 'a' - 'A'
+			}
+			upper = false
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			upper = false
+		default:
+			upper = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || '0' <= s[0] && s[0] <= '9' {
+		s = "C" + s
+	}
+	return s
+}
+
+// columnComment returns the SQL definition of the column e.g. "bigint NOT NULL REFERENCES users(id)".
+func columnComment(col *codex.ColumnDef) string {
+	parts := []string{col.Type}
+	if !col.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.PrimaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if lit, ok := col.Default.(*codex.LiteralNode); ok {
+		parts = append(parts, "DEFAULT "+lit.Sql)
+	}
+	if ref := col.References; ref != nil {
+		parts = append(parts, fmt.Sprintf("REFERENCES %s(%s)", ref.Table, ref.Column))
+	}
+	return strings.Join(parts, " ")
+}
+
+// generatedByDatabase reports whether the database generates the values of col:
+// identity and serial columns or columns defaulting to a sequence.
+// Columns having other defaults are written, omitempty would make false, 0 or "" impossible to store.
+func generatedByDatabase(col *codex.ColumnDef) bool {
+	if col.Identity || strings.Contains(strings.ToLower(col.Type), "serial") {
+		return true
+	}
+	lit, ok := col.Default.(*codex.LiteralNode)
+	return ok && strings.HasPrefix(strings.ToLower(lit.Sql), "nextval(")
+}
+
+var typeArgs = regexp.MustCompile(`\([^)]*\)`)
+
+// goType returns the Go type of an SQL type and the package to import for it.
+// Nullable columns map to sql.Null* types, unknown types to interface{}.
+func goType(sqlType string, nullable bool) (string, string) {
+	t := strings.ToLower(sqlType)
+	if strings.HasPrefix(t, "tinyint(1)") {
+		t = "boolean"
+	}
+	if strings.HasSuffix(t, "[]") || strings.HasPrefix(t, "array") {
+		return "interface{}", ""
+	}
+	t = typeArgs.ReplaceAllString(t, "")
+	unsigned := strings.Contains(t, "unsigned")
+	t = strings.Join(strings.Fields(strings.NewReplacer("unsigned", "", "zerofill", "").Replace(t)), " ")
+
+	// the next larger type for unsigned MySQL integers
+	if unsigned {
+		switch t {
+		case "tinyint", "smallint", "mediumint":
+			t = "int"
+		case "int", "integer":
+			t = "bigint"
+		case "bigint":
+			if nullable {
+				return "*uint64", ""
+			}
+			return "uint64", ""
+		}
+	}
+
+	switch t {
+	case "bool", "boolean":
+		return nullType("bool", "sql.NullBool", nullable)
+	case "smallint", "int2", "tinyint", "smallserial", "serial2":
+		return nullType("int16", "sql.NullInt16", nullable)
+	case "int", "integer", "int4", "mediumint", "serial", "serial4":
+		return nullType("int32", "sql.NullInt32", nullable)
+	case "bigint", "int8", "bigserial", "serial8":
+		return nullType("int64", "sql.NullInt64", nullable)
+	case "real", "float", "float4", "float8", "double", "double precision":
+		return nullType("float64", "sql.NullFloat64", nullable)
+	case "numeric", "decimal", "char", "character", "varchar", "character varying", "text", "tinytext",
+		"mediumtext", "longtext", "uuid", "citext", "enum", "set", "time", "interval":
+		return nullType("string", "sql.NullString", nullable)
+	case "json", "jsonb", "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "[]byte", ""
+	case "date", "datetime", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone":
+		if nullable {
+			return "sql.NullTime", "database/sql"
+		}
+		return "time.Time", "time"
+	}
+	return "interface{}", ""
+}
+
+func nullType(goType, nullType string, nullable bool) (string, string) {
+	if nullable {
+		return nullType, "database/sql"
+	}
+	return goType, ""
+}
+
+// LoadFile reads a schema file, format is "ddl", "json" or "yaml", empty for the one of the file extension.
+func LoadFile(path, format string) (*codex.Definitions, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".sql", ".ddl":
+			format = "ddl"
+		case ".json":
+			format = "json"
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			return nil, fmt.Errorf("unknown format of %s, use -format", path)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "ddl" {
+		schema, err := ParseDDL(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return schema, nil
+	}
+	schema, err := ParseInformationSchema(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema, nil
+}
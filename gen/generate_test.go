@@ -0,0 +1,119 @@
+package gen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janmentzel/codex"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		in      string
+		options Options
+	}{
+		{"postgres.sql", Options{Package: "models", Dialect: "POSTGRES", DefaultSchema: "public"}},
+		{"mysql.sql", Options{Package: "models", Dialect: "MYSQL"}},
+		{"information_schema.json", Options{Package: "db", Dialect: "POSTGRES", DefaultSchema: "public"}},
+		{"information_schema.yaml", Options{Package: "shop", Dialect: "MYSQL", DefaultSchema: "shop"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			path := filepath.Join("testdata", test.in)
+			schema, err := LoadFile(path, "")
+			assert.Nil(t, err)
+			src, err := Generate(schema, test.options)
+			assert.Nil(t, err)
+
+			golden := strings.TrimSuffix(path, filepath.Ext(path)) + "_" + strings.TrimPrefix(filepath.Ext(path), ".") + ".golden"
+			if *update {
+				assert.Nil(t, os.WriteFile(golden, src, 0644))
+			}
+			want, err := os.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(want), string(src))
+		})
+	}
+}
+
+func TestParseDDL(t *testing.T) {
+	schema, err := LoadFile(filepath.Join("testdata", "postgres.sql"), "")
+	assert.Nil(t, err)
+	assert.Len(t, schema.Tables(), 3)
+
+	users, ok := schema.Lookup(codex.Table("users").InSchema("public"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey())
+	assert.Equal(t, "character varying(255)", users.Column("email").Type)
+	assert.False(t, users.Column("email").Nullable)
+	assert.True(t, users.Column("nick").Nullable)
+	assert.Equal(t, codex.Literal("now()"), users.Column("created_at").Default)
+	assert.Equal(t, "numeric(10,2)", users.Column("score").Type)
+	assert.NotNil(t, users.Column("Table"))
+	assert.True(t, users.Column("seq").Identity)
+	assert.False(t, users.Column("active").Identity)
+
+	posts, _ := schema.Lookup(codex.Table("posts").InSchema("public"))
+	assert.Equal(t, &codex.ForeignKey{Table: "public.users", Column: "id", OnDelete: "CASCADE"}, posts.Column("user_id").References)
+	assert.Equal(t, codex.Literal("''::text"), posts.Column("title").Default)
+	assert.Equal(t, "text[]", posts.Column("tags").Type)
+	assert.Len(t, posts.Columns, 5)
+
+	events, _ := schema.Lookup(codex.Table("events").InSchema("analytics"))
+	assert.Equal(t, []string{"id"}, events.PrimaryKey())
+	assert.Equal(t, "integer", events.Column("id").Type)
+	assert.True(t, events.Column("id").Identity)
+	assert.Equal(t, &codex.ForeignKey{Table: "public.posts", Column: "id", OnDelete: "SET NULL"}, events.Column("post_id").References)
+
+	assert.Nil(t, schema.Validate())
+}
+
+func TestParseDDLMySql(t *testing.T) {
+	schema, err := LoadFile(filepath.Join("testdata", "mysql.sql"), "")
+	assert.Nil(t, err)
+
+	users, _ := schema.Lookup(codex.Table("users"))
+	assert.Equal(t, "int unsigned", users.Column("id").Type)
+	assert.True(t, users.Column("id").Identity)
+	assert.Equal(t, "varchar(255)", users.Column("email").Type)
+	assert.Equal(t, codex.Literal("CURRENT_TIMESTAMP(3)"), users.Column("updated_at").Default)
+	assert.Equal(t, codex.Literal("'user'"), users.Column("role").Default)
+
+	logins, _ := schema.Lookup(codex.Table("logins"))
+	assert.Equal(t, []string{"user_id", "at"}, logins.PrimaryKey())
+	assert.Equal(t, &codex.ForeignKey{Table: "users", Column: "id", OnDelete: "CASCADE"}, logins.Column("user_id").References)
+	assert.Nil(t, schema.Validate())
+}
+
+func TestParseDDLErrors(t *testing.T) {
+	_, err := ParseDDL("CREATE TABLE users (\n  id int,\n  name text")
+	assert.EqualError(t, err, `line 3: expected ')' near end of file`)
+
+	_, err = ParseDDL("CREATE TABLE users (id int DEFAULT 'x)")
+	assert.EqualError(t, err, `line 1: unterminated quote '`)
+
+	_, err = LoadFile(filepath.Join("testdata", "schema.txt"), "")
+	assert.NotNil(t, err)
+}
+
+func TestGenerateOptions(t *testing.T) {
+	schema := codex.NewDefinitions()
+	_, err := Generate(schema, Options{Package: "models", Dialect: "SQLITE"})
+	assert.NotNil(t, err)
+	_, err = Generate(schema, Options{Dialect: "MYSQL"})
+	assert.NotNil(t, err)
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "UserId", goName("user_id"))
+	assert.Equal(t, "CreatedAt", goName("createdAt"))
+	assert.Equal(t, "C2fa", goName("2fa"))
+	assert.Equal(t, "FooBar", goName("foo-bar"))
+}
@@ -0,0 +1,147 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/janmentzel/codex"
+	"gopkg.in/yaml.v3"
+)
+
+// InformationSchema is an export of information_schema as JSON or YAML:
+//
+//	{
+//	  "columns": [{"table_name": "users", "column_name": "id", "ordinal_position": 1, "data_type": "bigint", "is_nullable": "NO"}, ...],
+//	  "primary_keys": [{"table_name": "users", "column_name": "id"}],
+//	  "foreign_keys": [{"table_name": "posts", "column_name": "user_id", "foreign_table_name": "users", "foreign_column_name": "id"}]
+//	}
+//
+// A plain array is read as columns. MySQL column_key "PRI" marks primary keys as well.
+type InformationSchema struct {
+	Columns     []InformationSchemaColumn `json:"columns" yaml:"columns"`
+	PrimaryKeys []KeyColumnUsage          `json:"primary_keys" yaml:"primary_keys"`
+	ForeignKeys []KeyColumnUsage          `json:"foreign_keys" yaml:"foreign_keys"`
+}
+
+// InformationSchemaColumn is a row of information_schema.columns.
+type InformationSchemaColumn struct {
+	TableSchema            string  `json:"table_schema" yaml:"table_schema"`
+	TableName              string  `json:"table_name" yaml:"table_name"`
+	ColumnName             string  `json:"column_name" yaml:"column_name"`
+	OrdinalPosition        int     `json:"ordinal_position" yaml:"ordinal_position"`
+	DataType               string  `json:"data_type" yaml:"data_type"`
+	ColumnType             string  `json:"column_type" yaml:"column_type"` // MySQL e.g. "tinyint(1)", preferred over data_type
+	CharacterMaximumLength *int    `json:"character_maximum_length" yaml:"character_maximum_length"`
+	IsNullable             string  `json:"is_nullable" yaml:"is_nullable"` // "YES" or "NO"
+	ColumnDefault          *string `json:"column_default" yaml:"column_default"`
+	ColumnKey              string  `json:"column_key" yaml:"column_key"`   // MySQL "PRI" for primary keys
+	Extra                  string  `json:"extra" yaml:"extra"`             // MySQL e.g. "auto_increment"
+	IsIdentity             string  `json:"is_identity" yaml:"is_identity"` // Postgres "YES" for identity columns
+}
+
+// KeyColumnUsage is a column of a primary or foreign key, see information_schema.key_column_usage.
+type KeyColumnUsage struct {
+	TableSchema        string `json:"table_schema" yaml:"table_schema"`
+	TableName          string `json:"table_name" yaml:"table_name"`
+	ColumnName         string `json:"column_name" yaml:"column_name"`
+	ForeignTableSchema string `json:"foreign_table_schema" yaml:"foreign_table_schema"`
+	ForeignTableName   string `json:"foreign_table_name" yaml:"foreign_table_name"`
+	ForeignColumnName  string `json:"foreign_column_name" yaml:"foreign_column_name"`
+}
+
+// ParseInformationSchema returns the tables of an information_schema export, format is "json" or "yaml".
+// Tables are ordered by schema and name, columns by their ordinal position.
+func ParseInformationSchema(data []byte, format string) (*codex.Definitions, error) {
+	var is InformationSchema
+	var err error
+	switch format {
+	case "json":
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(data, &is.Columns)
+		} else {
+			err = json.Unmarshal(data, &is)
+		}
+	case "yaml":
+		var node yaml.Node
+		if err = yaml.Unmarshal(data, &node); err == nil && len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+			err = node.Decode(&is.Columns)
+		} else if err == nil {
+			err = node.Decode(&is)
+		}
+	default:
+		return nil, fmt.Errorf("unknown information schema format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return is.Definitions(), nil
+}
+
+// Schema returns the tables of the export.
+func (is *InformationSchema) Definitions() *codex.Definitions {
+	columns := append([]InformationSchemaColumn(nil), is.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		a, b := columns[i], columns[j]
+		if a.TableSchema != b.TableSchema {
+			return a.TableSchema < b.TableSchema
+		}
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.OrdinalPosition < b.OrdinalPosition
+	})
+
+	schema := codex.NewDefinitions()
+	for _, c := range columns {
+		table := codex.Table(c.TableName).InSchema(c.TableSchema)
+		def, ok := schema.Lookup(table)
+		if !ok {
+			def = schema.Define(table)
+		}
+
+		col := &codex.ColumnDef{Name: c.ColumnName, Type: c.ColumnType, Nullable: c.IsNullable == "YES", PrimaryKey: c.ColumnKey == "PRI",
+			Identity: c.IsIdentity == "YES" || strings.Contains(strings.ToLower(c.Extra), "auto_increment")}
+		if col.Type == "" {
+			col.Type = c.DataType
+			if c.CharacterMaximumLength != nil {
+				col.Type = fmt.Sprintf("%s(%d)", c.DataType, *c.CharacterMaximumLength)
+			}
+		}
+		if c.ColumnDefault != nil {
+			col.Default = codex.Literal(*c.ColumnDefault)
+		}
+		def.Columns = append(def.Columns, col)
+	}
+
+	for _, k := range is.PrimaryKeys {
+		if col := keyColumn(schema, k); col != nil {
+			col.PrimaryKey = true
+		}
+	}
+	for _, k := range is.ForeignKeys {
+		if col := keyColumn(schema, k); col != nil {
+			ref := codex.Table(k.ForeignTableName).InSchema(k.ForeignTableSchema)
+			col.References = &codex.ForeignKey{Table: qualifiedName(ref), Column: k.ForeignColumnName}
+		}
+	}
+	return schema
+}
+
+// keyColumn returns the definition of the key column, nil if undefined.
+func keyColumn(schema *codex.Definitions, k KeyColumnUsage) *codex.ColumnDef {
+	if def, ok := schema.Lookup(codex.Table(k.TableName).InSchema(k.TableSchema)); ok {
+		return def.Column(k.ColumnName)
+	}
+	return nil
+}
+
+// qualifiedName returns the name of the table qualified by its schema e.g. "analytics.events".
+func qualifiedName(t *codex.TableNode) string {
+	if t.Schema != "" {
+		return t.Schema + "." + t.Name
+	}
+	return t.Name
+}
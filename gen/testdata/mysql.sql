@@ -0,0 +1,21 @@
+/*!40101 SET NAMES utf8 */;
+DROP TABLE IF EXISTS `users`;
+CREATE TABLE `users` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+  `admin` tinyint(1) NOT NULL DEFAULT '0',
+  `born_on` date DEFAULT NULL,
+  `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  `role` enum('user','admin') NOT NULL DEFAULT 'user' COMMENT 'it''s the role',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email` (`email`(191))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `logins` (
+  `user_id` int unsigned NOT NULL,
+  `at` datetime NOT NULL,
+  `ip` varbinary(16) DEFAULT NULL,
+  PRIMARY KEY (`user_id`, `at`),
+  KEY `at` (`at`),
+  CONSTRAINT `logins_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB;
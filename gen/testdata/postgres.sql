@@ -0,0 +1,45 @@
+-- pg_dump style schema
+SET statement_timeout = 0;
+
+CREATE FUNCTION public.touch() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.updated_at = now(); -- CREATE TABLE inside a body is skipped
+    RETURN NEW;
+END;
+$$;
+
+CREATE TABLE public.users (
+    id bigint NOT NULL,
+    email character varying(255) NOT NULL,
+    nick text,
+    active boolean DEFAULT true NOT NULL,
+    score numeric(10, 2),
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp without time zone,
+    "Table" integer,
+    seq bigint GENERATED BY DEFAULT AS IDENTITY
+);
+
+CREATE TABLE IF NOT EXISTS public.posts (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES public.users ON DELETE CASCADE,
+    title text DEFAULT ''::text NOT NULL,
+    body jsonb,
+    tags text[],
+    CONSTRAINT posts_title_check CHECK ((char_length(title) > 0))
+);
+
+CREATE TABLE analytics.events (
+    id integer GENERATED ALWAYS AS IDENTITY,
+    post_id bigint,
+    payload bytea,
+    PRIMARY KEY (id),
+    FOREIGN KEY (post_id) REFERENCES public.posts (id) ON DELETE SET NULL
+);
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX users_email ON public.users USING btree (email);
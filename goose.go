@@ -0,0 +1,59 @@
+package codex
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GooseMigration returns a SQL migration of the up and down statements in the format of goose
+// (https://github.com/pressly/goose). Each statement is preceded by a comment with its description,
+// destructive ones are marked.
+func GooseMigration(up, down []Statement) []byte {
+	var b bytes.Buffer
+	b.WriteString("-- +goose Up\n")
+	writeGooseStatements(&b, up)
+	b.WriteString("\n-- +goose Down\n")
+	writeGooseStatements(&b, down)
+	return b.Bytes()
+}
+
+func writeGooseStatements(b *bytes.Buffer, statements []Statement) {
+	for i, s := range statements {
+		if i != 0 {
+			b.WriteByte('\n')
+		}
+		if s.Destructive {
+			b.WriteString("-- DESTRUCTIVE: ")
+		} else {
+			b.WriteString("-- ")
+		}
+		b.WriteString(s.Description)
+		b.WriteByte('\n')
+		b.WriteString(s.Sql)
+		b.WriteString(";\n")
+	}
+}
+
+// WriteGooseMigration writes the migration from old to new and back as file dir/<version>_<name>.sql,
+// version is formatted as timestamp e.g. 20240131120000 like goose does. It returns the path of the file.
+// If the schemas do not differ ErrNoChanges is returned and no file is written.
+//
+//	path, err := WriteGooseMigration("migrations", "add_nick", time.Now().UTC(), current, next)
+func WriteGooseMigration(dir, name string, version time.Time, old, new *Definitions) (string, error) {
+	up, err := Diff(old, new)
+	if err != nil {
+		return "", err
+	}
+	down, err := Diff(new, old)
+	if err != nil {
+		return "", err
+	}
+	if 0 == len(up) {
+		return "", ErrNoChanges
+	}
+
+	path := filepath.Join(dir, version.Format("20060102150405")+"_"+name+".sql")
+	return path, os.WriteFile(path, GooseMigration(up, down), 0644)
+}
@@ -0,0 +1,52 @@
+package codex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGooseMigration(t *testing.T) {
+	up := []Statement{
+		{Sql: `ALTER TABLE "users" ADD COLUMN "nick" text`, Description: "add column users.nick"},
+		{Sql: `ALTER TABLE "users" DROP COLUMN "legacy"`, Description: "drop column users.legacy", Destructive: true},
+	}
+	down := []Statement{
+		{Sql: `ALTER TABLE "users" ADD COLUMN "legacy" text`, Description: "add column users.legacy"},
+	}
+
+	assert.Equal(t, `-- +goose Up
+-- add column users.nick
+ALTER TABLE "users" ADD COLUMN "nick" text;
+
+-- DESTRUCTIVE: drop column users.legacy
+ALTER TABLE "users" DROP COLUMN "legacy";
+
+-- +goose Down
+-- add column users.legacy
+ALTER TABLE "users" ADD COLUMN "legacy" text;
+`, string(GooseMigration(up, down)))
+}
+
+func TestWriteGooseMigration(t *testing.T) {
+	dir := t.TempDir()
+	old, new := diffSchemas()
+	version := time.Date(2024, 1, 31, 12, 30, 0, 0, time.UTC)
+
+	path, err := WriteGooseMigration(dir, "add_posts", version, old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "20240131123000_add_posts.sql"), path)
+
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "-- +goose Up\n-- create table posts\nCREATE TABLE \"posts\"")
+	assert.Contains(t, string(data), "-- +goose Down\n-- create table sessions\nCREATE TABLE \"sessions\"")
+	assert.Contains(t, string(data), "-- DESTRUCTIVE: drop table sessions\nDROP TABLE \"sessions\";\n")
+
+	_, err = WriteGooseMigration(dir, "noop", version, old, old)
+	assert.True(t, errors.Is(err, ErrNoChanges))
+}
@@ -0,0 +1,80 @@
+package codex
+
+import (
+	"time"
+)
+
+// Hook is notified when a manager renders SQL by ToSql or ToSqlWith, e.g. to feed a logger or tracer.
+// Hooks are added per dialect, table or manager, see DbDialect.Hooks, TableNode.Hooks and e.g. SelectManager.Hooks.
+//
+// BeforeRender and AfterRender receive the same *RenderEvent, so a tracer can start a span
+// in BeforeRender and end it in AfterRender. AfterRender may modify Sql e.g. to add a comment.
+// Hooks are called concurrently if managers render concurrently.
+type Hook interface {
+	BeforeRender(event *RenderEvent)
+	AfterRender(event *RenderEvent)
+}
+
+// RenderEvent describes the rendering of a statement.
+// Sql, Args, Duration and Err are set for AfterRender.
+type RenderEvent struct {
+	Statement string     // SELECT, INSERT, UPDATE, DELETE or a DDL statement e.g. CREATE TABLE
+	Table     *TableNode // The manager's table.
+	Sql       string
+	Args      []interface{}
+	Duration  time.Duration
+	Err       error
+	Data      interface{} // free for the hooks e.g. a span
+}
+
+// HookFuncs is a Hook calling its funcs unless nil.
+//
+//	logger := slog.Default()
+//	users := Dialect(POSTGRES).Hooks(HookFuncs{After: func(e *RenderEvent) {
+//		logger.Debug("codex", "sql", e.Sql, "args", len(e.Args), "duration", e.Duration, "err", e.Err)
+//	}}).Table("users")
+type HookFuncs struct {
+	Before func(event *RenderEvent)
+	After  func(event *RenderEvent)
+}
+
+var _ Hook = HookFuncs{}
+
+func (h HookFuncs) BeforeRender(event *RenderEvent) {
+	if h.Before != nil {
+		h.Before(event)
+	}
+}
+
+func (h HookFuncs) AfterRender(event *RenderEvent) {
+	if h.After != nil {
+		h.After(event)
+	}
+}
+
+// render calls accept between the BeforeRender and AfterRender of the hooks.
+// AfterRender is called in reverse order.
+func render(hooks []Hook, statement string, table *TableNode, accept func() (string, []interface{}, error)) (string, []interface{}, error) {
+	if 0 == len(hooks) {
+		return accept()
+	}
+
+	event := &RenderEvent{Statement: statement, Table: table}
+	for _, hook := range hooks {
+		hook.BeforeRender(event)
+	}
+
+	start := time.Now()
+	event.Sql, event.Args, event.Err = accept()
+	event.Duration = time.Since(start)
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].AfterRender(event)
+	}
+	return event.Sql, event.Args, event.Err
+}
+
+// appendHooks returns a new slice, so managers and their clones do not share appended hooks.
+func appendHooks(hooks []Hook, more ...Hook) []Hook {
+	return append(hooks[:len(hooks):len(hooks)], more...)
+}
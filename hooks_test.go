@@ -0,0 +1,145 @@
+package codex
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+type recordingHook struct {
+	name  string
+	mutex sync.Mutex
+	calls []string
+}
+
+func (h *recordingHook) BeforeRender(e *RenderEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.calls = append(h.calls, h.name+" before "+e.Statement+" "+e.Table.Name)
+}
+
+func (h *recordingHook) AfterRender(e *RenderEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.calls = append(h.calls, h.name+" after "+e.Sql)
+}
+
+func TestHooksDialect(t *testing.T) {
+	hook := &recordingHook{name: "h"}
+	users := Dialect(POSTGRES).Hooks(hook).Table("users")
+
+	_, _, err := users.Where(users.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+	_, _, err = users.Insert(1).Into("id").ToSql()
+	assert.Nil(t, err)
+	_, _, err = users.Set("id").To(2).ToSqlWith(AT_P_NUMBER)
+	assert.Nil(t, err)
+	_, _, err = users.Delete(users.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		`h before SELECT users`,
+		`h after SELECT "users".* FROM "users" WHERE ("users"."id"=$1)`,
+		`h before INSERT users`,
+		`h after INSERT INTO "users" ("id") VALUES ($1)`,
+		`h before UPDATE users`,
+		`h after UPDATE "users" SET "id"=@p1 `,
+		`h before DELETE users`,
+		`h after DELETE FROM "users" WHERE ("users"."id"=$1)`,
+	}, hook.calls)
+
+	// dialects without hooks are not affected
+	_, _, err = Dialect(POSTGRES).Table("users").Selection().ToSql()
+	assert.Nil(t, err)
+	assert.Len(t, hook.calls, 8)
+}
+
+func TestHooksOrderAndEvent(t *testing.T) {
+	a := &recordingHook{name: "a"}
+	b := &recordingHook{name: "b"}
+	var events []*RenderEvent
+	c := HookFuncs{
+		Before: func(e *RenderEvent) { e.Data = "span" },
+		After: func(e *RenderEvent) {
+			events = append(events, e)
+			e.Sql = "/* app */ " + e.Sql
+		},
+	}
+
+	users := Table("users").Hooks(a)
+	sql, args, err := users.Where(users.Col("id").Eq(1)).Hooks(b, c).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `/* app */ SELECT "users".* FROM "users" WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	assert.Equal(t, []string{`a before SELECT users`, `a after /* app */ SELECT "users".* FROM "users" WHERE ("users"."id"=?)`}, a.calls)
+	assert.Equal(t, []string{`b before SELECT users`, `b after /* app */ SELECT "users".* FROM "users" WHERE ("users"."id"=?)`}, b.calls)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "span", events[0].Data)
+	assert.Equal(t, []interface{}{1}, events[0].Args)
+	assert.True(t, events[0].Duration >= 0)
+	assert.Nil(t, events[0].Err)
+}
+
+func TestHooksError(t *testing.T) {
+	var event *RenderEvent
+	users := Table("users").Hooks(HookFuncs{After: func(e *RenderEvent) { event = e }})
+
+	_, _, err := users.InnerJoin(1).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+	assert.Equal(t, err, event.Err)
+	assert.Equal(t, "", event.Sql)
+}
+
+func TestHooksManagerConversions(t *testing.T) {
+	hook := &recordingHook{name: "h"}
+	users := Table("users")
+	m := users.Where(users.Col("id").Eq(1)).Hooks(hook)
+
+	m.Count("id").ToSql()
+	m.Modification().Set("id").To(2).ToSql()
+	m.Deletion().ToSql()
+	m.Insertion().Insert(1).Into("id").ToSql()
+	From(m, "sub").Selection().ToSql()
+	m.Clone().ToSql()
+	assert.Len(t, hook.calls, 12)
+
+	// ToDebugSql does not call hooks
+	m.ToDebugSql()
+	assert.Len(t, hook.calls, 12)
+}
+
+func TestHooksImmutableDoNotLeak(t *testing.T) {
+	a := &recordingHook{name: "a"}
+	b := &recordingHook{name: "b"}
+	users := Table("users")
+	base := users.Selection().Hooks(a).Immutable()
+	withB := base.Hooks(b)
+
+	base.ToSql()
+	assert.Len(t, a.calls, 2)
+	assert.Empty(t, b.calls)
+
+	withB.ToSql()
+	assert.Len(t, a.calls, 4)
+	assert.Len(t, b.calls, 2)
+}
+
+func TestHooksConcurrent(t *testing.T) {
+	hook := &recordingHook{name: "h"}
+	users := Table("users").Hooks(hook)
+	m := users.Where(users.Col("id").Eq(1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.ToSql()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, hook.calls, 16)
+}
@@ -1,27 +1,155 @@
 package codex
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // InsertManager manages a tree that compiles to a SQL insert statement.
 type InsertManager struct {
 	Tree    *InsertStatementNode // The AST for the SQL INSERT statement.
 	Adapter adapter              // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
 }
 
 // Appends the values to the trees Values node
 func (self *InsertManager) Insert(values ...interface{}) *InsertManager {
+	self = self.chain()
 	self.Tree.Values.Expressions = append(self.Tree.Values.Expressions, values...)
 	return self
 }
 
 // Appends the columns to the trees Columns slice and Values node.
 func (self *InsertManager) Into(columns ...interface{}) *InsertManager {
+	self = self.chain()
 	self.Tree.Values.Columns = append(self.Tree.Values.Columns, columns...)
 	self.Tree.Columns = append(self.Tree.Columns, columns...)
 	return self
 }
 
+// Row appends a further row of values for a multi row insert.
+// The first row is the one of Insert:
+//
+//	users.Insert(1, "Jon").Into("id", "name").Row(2, "Ann")
+//	// INSERT INTO "users" ("id","name") VALUES (?,?),(?,?)
+func (self *InsertManager) Row(values ...interface{}) *InsertManager {
+	self = self.chain()
+	if 0 == len(self.Tree.Values.Expressions) {
+		self.Tree.Values.Expressions = append(self.Tree.Values.Expressions, values...)
+	} else {
+		self.Tree.Values.Rows = append(self.Tree.Values.Rows, values)
+	}
+	return self
+}
+
+// FromStruct sets columns and values from the `db` tagged fields of a struct,
+// a pointer to one or a slice of them for a multi row insert:
+//
+//	type User struct {
+//		Id        int64     `db:"id,pk"`              // inserted unless zero
+//		Name      string    `db:"name"`
+//		Nick      string    `db:"nick,omitempty"`     // inserted unless zero
+//		CreatedAt time.Time `db:"created_at,readonly"` // never inserted
+//	}
+//
+//	users.Insertion().FromStruct([]User{{Name: "Jon"}, {Name: "Ann", Nick: "annie"}})
+//	// INSERT INTO "users" ("name","nick") VALUES (?,DEFAULT),(?,?)
+//
+// Untagged fields map to their lower case name, fields tagged `db:"-"` are skipped.
+// Omitted pk and omitempty columns of multi row inserts are DEFAULT in the rows they are zero.
+//
+// BEWARE: zero omitempty fields get the column's database default, not their zero value.
+// An omitempty `Active bool` of a column "DEFAULT true" can not be inserted as false.
+// Pass such columns as `force` to insert their zero values:
+//
+//	users.Insertion().FromStruct(user, "active")
+func (self *InsertManager) FromStruct(v interface{}, force ...string) *InsertManager {
+	self = self.chain()
+	forced := map[string]bool{}
+	for _, column := range force {
+		forced[column] = true
+	}
+
+	var rows []reflect.Value
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, rv.Index(i))
+		}
+	} else {
+		rows = append(rows, rv)
+	}
+	if 0 == len(rows) {
+		if self.err == nil {
+			self.err = fmt.Errorf("%w: InsertManager.FromStruct() got no rows", ErrArgumentCount)
+		}
+		return self
+	}
+	for i, row := range rows {
+		var ok bool
+		if rows[i], ok = structValue(row.Interface()); !ok {
+			if self.err == nil {
+				self.err = unexpectedType("InsertManager.FromStruct()", v)
+			}
+			return self
+		}
+	}
+
+	var columns []interface{}
+	var fields []StructField
+	for _, field := range StructFields(rows[0].Type()) {
+		if field.ReadOnly {
+			continue
+		}
+		skip := (field.PK || field.OmitEmpty) && !forced[field.Column]
+		for _, row := range rows {
+			if _, zero := field.value(row); !zero {
+				skip = false
+			}
+		}
+		if !skip {
+			columns = append(columns, field.Column)
+			fields = append(fields, field)
+		}
+	}
+	self = self.Into(columns...)
+
+	for _, row := range rows {
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			value, zero := field.value(row)
+			if zero && (field.PK || field.OmitEmpty) && !forced[field.Column] {
+				value = Literal("DEFAULT")
+			}
+			values[i] = value
+		}
+		self = self.Row(values...)
+	}
+	return self
+}
+
+// FromMap sets columns and values from the map, ordered by column name:
+//
+//	users.Insertion().FromMap(map[string]interface{}{"name": "Jon", "age": 42})
+//	// INSERT INTO "users" ("age","name") VALUES (?,?)
+func (self *InsertManager) FromMap(m map[string]interface{}) *InsertManager {
+	self = self.chain()
+	keys := sortedKeys(m)
+	columns := make([]interface{}, len(keys))
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		columns[i] = key
+		values[i] = m[key]
+	}
+	return self.Into(columns...).Row(values...)
+}
+
 // Return sets the InsertStatementNodes Return to the `column` paramenter
 // after ensureing it is a ColumnNode.
 func (self *InsertManager) Returning(column interface{}) *InsertManager {
+	self = self.chain()
 	if _, ok := column.(string); ok {
 		column = Column(column)
 	}
@@ -35,6 +163,8 @@ func (self *InsertManager) Returning(column interface{}) *InsertManager {
 func (self *InsertManager) Selection() *SelectManager {
 	m := Selection(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
@@ -43,6 +173,8 @@ func (self *InsertManager) Selection() *SelectManager {
 func (self *InsertManager) Modification() *UpdateManager {
 	m := Modification(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
@@ -51,12 +183,83 @@ func (self *InsertManager) Modification() *UpdateManager {
 func (self *InsertManager) Deletion() *DeleteManager {
 	m := Deletion(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
+// Hooks adds hooks called by ToSql and ToSqlWith, see Hook.
+func (self *InsertManager) Hooks(hooks ...Hook) *InsertManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a deep copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *InsertManager) Clone() *InsertManager {
+	m := *self
+	m.Tree = cloneInsertStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+// Thus a base query can be branched into several variants.
+func (self *InsertManager) Immutable() *InsertManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *InsertManager) chain() *InsertManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the query e.g. an unexpected argument type.
+// ToSql returns it as well.
+func (self *InsertManager) Err() error {
+	return self.err
+}
+
 // ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// The Tree is not modified, so ToSql is safe to be called repeatedly and concurrently.
+// On error neither SQL nor arguments are returned.
 func (self *InsertManager) ToSql() (string, []interface{}, error) {
-	return VisitorFor(self.Adapter).Accept(self.Tree)
+	return render(self.hooks, "INSERT", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorFor(self.Adapter))
+	})
+}
+
+// ToSqlWith is like ToSql but renders the placeholders in `style` e.g. COLON_NUMBER for Oracle.
+func (self *InsertManager) ToSqlWith(style PlaceholderStyle) (string, []interface{}, error) {
+	return render(self.hooks, "INSERT", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(style)))
+	})
+}
+
+// ToDebugSql returns the SQL with the args inlined for logs and EXPLAIN copy-paste, see Interpolate.
+// NEVER execute it - use ToSql instead. Hooks are not called.
+func (self *InsertManager) ToDebugSql() (string, error) {
+	sql, args, err := self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(QUESTION_MARK)))
+	if err != nil {
+		return "", err
+	}
+	return Interpolate(sql, args, self.Adapter)
+}
+
+func (self *InsertManager) accept(visitor VisitorInterface) (string, []interface{}, error) {
+	if self.err != nil {
+		return "", nil, self.err
+	}
+	if err := validateSchema(self.Tree); err != nil {
+		return "", nil, err
+	}
+	return visitor.Accept(self.Tree)
 }
 
 func (self *InsertManager) Table() *TableNode {
@@ -67,5 +270,6 @@ func Insertion(relation *TableNode) (m *InsertManager) {
 	m = new(InsertManager)
 	m.Tree = InsertStatement(relation)
 	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
 	return
 }
@@ -1,8 +1,10 @@
 package codex
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestInsertManager(t *testing.T) {
@@ -62,3 +64,129 @@ func TestInsertManagerDeletion(t *testing.T) {
 	assert.Equal(t, `DELETE FROM "users" `, sql)
 	assert.Empty(t, args)
 }
+
+func TestInsertManagerImmutable(t *testing.T) {
+	base := Table("users").Insertion().Into("name").Immutable()
+	a := base.Insert("a")
+	b := base.Insert("b").Returning("id")
+
+	sql, args, err := a.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name") VALUES (?)`, sql)
+	assert.Equal(t, []interface{}{"a"}, args)
+
+	sql, args, err = b.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name") VALUES (?) RETURNING "id"`, sql)
+	assert.Equal(t, []interface{}{"b"}, args)
+
+	assert.Empty(t, base.Tree.Values.Expressions)
+}
+
+func TestInsertManagerClone(t *testing.T) {
+	m := Table("users").Insert("a").Into("name")
+	c := m.Clone().Insert("b").Into("email")
+
+	sql, _, err := m.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name") VALUES (?)`, sql)
+
+	sql, _, err = c.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name","email") VALUES (?,?)`, sql)
+}
+
+type structAudit struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	Note      string    `db:"note,omitempty"`
+}
+
+type structUser struct {
+	Id       int64  `db:"id,pk"`
+	Name     string `db:"name"`
+	Nick     string `db:"nick,omitempty"`
+	Age      *int
+	Password string `db:"-"`
+	secret   string
+	structAudit
+}
+
+func TestInsertManagerRow(t *testing.T) {
+	users := Table("users")
+	sql, args, err := users.Insert(1, "Jon").Into("id", "name").Row(2, "Ann").Row(3, "Bob").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name") VALUES (?,?),(?,?),(?,?)`, sql)
+	assert.Equal(t, []interface{}{1, "Jon", 2, "Ann", 3, "Bob"}, args)
+
+	sql, _, err = users.Insertion().Into("id").Row(1).Row(2).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id") VALUES (?),(?)`, sql)
+}
+
+func TestInsertManagerFromStruct(t *testing.T) {
+	users := Table("users")
+	age := 42
+
+	sql, args, err := users.Insertion().FromStruct(&structUser{Name: "Jon", Age: &age, Password: "x", secret: "y"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name","age") VALUES (?,?)`, sql)
+	assert.Equal(t, []interface{}{"Jon", &age}, args)
+
+	sql, args, err = users.Insertion().FromStruct(structUser{Id: 7, Name: "Jon", Nick: "j", structAudit: structAudit{Note: "n"}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name","nick","age","note") VALUES (?,?,?,?,?)`, sql)
+	assert.Equal(t, []interface{}{int64(7), "Jon", "j", (*int)(nil), "n"}, args)
+}
+
+func TestInsertManagerFromStructSlice(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+
+	sql, args, err := users.Insertion().FromStruct([]*structUser{{Name: "Jon"}, {Name: "Ann", Nick: "annie"}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO `users` (`name`,`nick`,`age`) VALUES (?,DEFAULT,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{"Jon", (*int)(nil), "Ann", "annie", (*int)(nil)}, args)
+}
+
+func TestInsertManagerFromStructForce(t *testing.T) {
+	users := Table("users")
+
+	sql, args, err := users.Insertion().FromStruct([]structUser{{Name: "Jon"}, {Name: "Ann", Nick: "annie"}}, "nick", "id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name","nick","age") VALUES (?,?,?,?),(?,?,?,?)`, sql)
+	assert.Equal(t, []interface{}{int64(0), "Jon", "", (*int)(nil), int64(0), "Ann", "annie", (*int)(nil)}, args)
+}
+
+func TestInsertManagerFromStructErrors(t *testing.T) {
+	users := Table("users")
+
+	_, _, err := users.Insertion().FromStruct([]structUser{}).ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, _, err = users.Insertion().FromStruct(42).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	_, _, err = users.Insertion().FromStruct([]*structUser{nil}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+}
+
+func TestInsertManagerFromMap(t *testing.T) {
+	users := Table("users")
+	sql, args, err := users.Insertion().FromMap(map[string]interface{}{"name": "Jon", "age": 42}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("age","name") VALUES (?,?)`, sql)
+	assert.Equal(t, []interface{}{42, "Jon"}, args)
+}
+
+func TestInsertManagerRowImmutable(t *testing.T) {
+	users := Table("users")
+	base := users.Insertion().Immutable()
+	one := base.FromStruct(structUser{Name: "Jon"})
+	two := one.Row("Ann", nil)
+
+	sql, _, _ := base.ToSql()
+	assert.Equal(t, `INSERT INTO "users" `, sql)
+	sql, _, _ = one.ToSql()
+	assert.Equal(t, `INSERT INTO "users" ("name","age") VALUES (?,?)`, sql)
+	sql, _, _ = two.ToSql()
+	assert.Equal(t, `INSERT INTO "users" ("name","age") VALUES (?,?),(?,?)`, sql)
+}
@@ -0,0 +1,124 @@
+package codex
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interpolate returns `sql` with its ? placeholders replaced by the `args`
+// escaped for the adapter's dialect, "??" is unescaped to "?".
+// Question marks within quoted strings and comments are kept, see questionAt.
+//
+// The result is meant for logs and EXPLAIN copy-paste only.
+// NEVER execute it - use the SQL and args of ToSql() instead.
+//
+//	Interpolate("SELECT * FROM users WHERE name = ? AND active = ?", []interface{}{"O'Hara", true}, POSTGRES)
+//	// SELECT * FROM users WHERE name = 'O''Hara' AND active = TRUE
+func Interpolate(sql string, args []interface{}, adapter adapter) (string, error) {
+	var b strings.Builder
+	var err error
+	i := 0
+	scanPlaceholders(sql, true, func(text string) {
+		b.WriteString(text)
+	}, func() {
+		if i >= len(args) {
+			if err == nil {
+				err = fmt.Errorf("%w: %d args for more placeholders", ErrArgumentCount, len(args))
+			}
+			return
+		}
+		literal, e := quoteValue(args[i], adapter)
+		if e != nil && err == nil {
+			err = e
+		}
+		b.WriteString(literal)
+		i++
+	})
+	if err == nil && i != len(args) {
+		err = fmt.Errorf("%w: %d args for %d placeholders", ErrArgumentCount, len(args), i)
+	}
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// quoteValue returns arg as SQL literal of the adapter's dialect.
+func quoteValue(arg interface{}, adapter adapter) (string, error) {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL", nil
+	case sql.NamedArg:
+		return quoteValue(v.Value, adapter)
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL", nil
+		}
+		value, err := v.Value()
+		if err != nil {
+			return "", err
+		}
+		return quoteValue(value, adapter)
+	case string:
+		return quoteString(v, adapter), nil
+	case []byte:
+		if v == nil {
+			return "NULL", nil
+		}
+		if adapter.dialect() == POSTGRES {
+			return `'\x` + hex.EncodeToString(v) + `'`, nil
+		}
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case time.Time:
+		if adapter.dialect() == MYSQL {
+			return quoteString(v.Format("2006-01-02 15:04:05.999999"), adapter), nil
+		}
+		return quoteString(v.Format("2006-01-02 15:04:05.999999Z07:00"), adapter), nil
+	}
+
+	rv := reflect.ValueOf(arg)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "NULL", nil
+		}
+		return quoteValue(rv.Elem().Interface(), adapter)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return quoteString(strconv.FormatFloat(f, 'g', -1, 64), adapter), nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), nil
+	case reflect.Bool:
+		return quoteValue(rv.Bool(), adapter)
+	case reflect.String:
+		return quoteString(rv.String(), adapter), nil
+	}
+	return quoteString(fmt.Sprint(arg), adapter), nil
+}
+
+// quoteString returns s enclosed in single quotes, MySQL escapes backslashes as well.
+func quoteString(s string, adapter adapter) string {
+	if adapter.dialect() == MYSQL {
+		s = strings.NewReplacer(`\`, `\\`, "'", "''", "\x00", `\0`).Replace(s)
+	} else {
+		s = strings.Replace(s, "'", "''", -1)
+	}
+	return "'" + s + "'"
+}
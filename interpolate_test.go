@@ -0,0 +1,108 @@
+package codex
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+type testValuer struct {
+	value interface{}
+	err   error
+}
+
+func (v *testValuer) Value() (driver.Value, error) {
+	return v.value, v.err
+}
+
+type testStatus string
+
+func TestInterpolateValues(t *testing.T) {
+	ts := time.Date(2024, 2, 29, 13, 4, 5, 123000000, time.UTC)
+	var nilPtr *int
+	var nilValuer *testValuer
+	seven := 7
+
+	for _, c := range []struct {
+		arg                      interface{}
+		generic, mysql, postgres string
+	}{
+		{nil, `NULL`, `NULL`, `NULL`},
+		{"O'Hara", `'O''Hara'`, `'O''Hara'`, `'O''Hara'`},
+		{`back\slash`, `'back\slash'`, `'back\\slash'`, `'back\slash'`},
+		{"nul\x00", "'nul\x00'", `'nul\0'`, "'nul\x00'"},
+		{[]byte{0x01, 0xab}, `X'01ab'`, `X'01ab'`, `'\x01ab'`},
+		{[]byte(nil), `NULL`, `NULL`, `NULL`},
+		{true, `TRUE`, `TRUE`, `TRUE`},
+		{false, `FALSE`, `FALSE`, `FALSE`},
+		{42, `42`, `42`, `42`},
+		{int64(-42), `-42`, `-42`, `-42`},
+		{uint8(200), `200`, `200`, `200`},
+		{1.5, `1.5`, `1.5`, `1.5`},
+		{float32(0.1), `0.1`, `0.1`, `0.1`},
+		{math.Inf(1), `'+Inf'`, `'+Inf'`, `'+Inf'`},
+		{ts, `'2024-02-29 13:04:05.123Z'`, `'2024-02-29 13:04:05.123'`, `'2024-02-29 13:04:05.123Z'`},
+		{testStatus("new"), `'new'`, `'new'`, `'new'`},
+		{&seven, `7`, `7`, `7`},
+		{nilPtr, `NULL`, `NULL`, `NULL`},
+		{&testValuer{value: "it's"}, `'it''s'`, `'it''s'`, `'it''s'`},
+		{&testValuer{value: nil}, `NULL`, `NULL`, `NULL`},
+		{nilValuer, `NULL`, `NULL`, `NULL`},
+		{sql.NullString{String: "x", Valid: true}, `'x'`, `'x'`, `'x'`},
+		{sql.NullInt64{}, `NULL`, `NULL`, `NULL`},
+		{sql.Named("a", 1), `1`, `1`, `1`},
+	} {
+		for adapter, expected := range map[adapter]string{0: c.generic, MYSQL: c.mysql, POSTGRES: c.postgres} {
+			s, err := Interpolate("x = ?", []interface{}{c.arg}, adapter)
+			assert.Nil(t, err)
+			assert.Equal(t, "x = "+expected, s, "%#v %d", c.arg, adapter)
+		}
+	}
+}
+
+func TestInterpolateKeepsQuotedQuestionMarks(t *testing.T) {
+	s, err := Interpolate(`a = ? AND b = '?' AND data ?? 'k' AND data ?| ? -- ?`, []interface{}{1, "x"}, POSTGRES)
+	assert.Nil(t, err)
+	assert.Equal(t, `a = 1 AND b = '?' AND data ? 'k' AND data ?| 'x' -- ?`, s)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	_, err := Interpolate("a = ? AND b = ?", []interface{}{1}, 0)
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	_, err = Interpolate("a = ?", []interface{}{1, 2}, 0)
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+
+	valuerErr := errors.New("broken")
+	_, err = Interpolate("a = ?", []interface{}{&testValuer{err: valuerErr}}, 0)
+	assert.Equal(t, valuerErr, err)
+}
+
+func TestManagersToDebugSql(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+
+	s, err := users.Where(users.Col("name").Eq("O'Hara")).Where("tags @> ARRAY[?...]", "a", "b").Limit(3).ToDebugSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."name"='O''Hara') AND (tags @> ARRAY['a','b']) LIMIT 3`, s)
+
+	s, err = users.Insert("x", nil).Into("name", "email").ToDebugSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name","email") VALUES ('x',NULL)`, s)
+
+	mysql := Dialect(MYSQL).Table("users")
+	s, err = mysql.Set("name").To(`a\b`).Where(mysql.Col("id").Eq(1)).ToDebugSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name`='a\\\\b' WHERE (`users`.`id`=1)", s)
+
+	s, err = Table("users").Delete(Table("users").Col("active").Eq(false)).ToDebugSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."active"=FALSE)`, s)
+
+	_, err = users.InnerJoin(1).ToDebugSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+}
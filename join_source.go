@@ -2,12 +2,12 @@ package codex
 
 // JoinSourceNode is a specific BinaryNode.
 type JoinSourceNode struct {
-	Left  *TableNode    // Left child of the JoinSource node, a pointer to a Table.
+	Left  interface{}   // Left child of the JoinSource node, a pointer to a Table or DerivedTable.
 	Right []interface{} // Right child of the JoinSource node contains joins and their instructions
 }
 
 // JoinSourceNode factory method.
-func JoinSource(relation *TableNode) (source *JoinSourceNode) {
+func JoinSource(relation interface{}) (source *JoinSourceNode) {
 	source = new(JoinSourceNode)
 	source.Left = relation
 	source.Right = make([]interface{}, 0)
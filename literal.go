@@ -1,57 +1,254 @@
 package codex
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
+	"time"
 )
 
 // LiteralNode has raw SQL string and args array
 type LiteralNode struct {
 	Sql  string
 	Args []interface{}
+	Err  error // invalid sql, returned when visited
 }
 
 // LiteralNode factory method.
-// "?..." in sql and args = ["a","b","c"]  expands to "?,?,?" in sql
-// only one "?..." per Literal supported atm.
-// something like that does NOT work:  Literal("foo IN(?...) OR bar IN(?...)")
+//
+// Positional parameters "?" consume one argument each.
+// An expansion "?..." consumes one slice argument and expands to a "?" per element:
+//
+//	Literal("a = ? AND b IN(?...) AND c IN(?...)", 1, []int{2, 3}, []string{"x"})
+//	// a = ? AND b IN(?,?) AND c IN(?) with args 1, 2, 3, "x"
+//
+// A single "?..." without a single slice argument expands all arguments:
+//
+//	Literal("id IN(?...)", 1, 2, 3) // id IN(?,?,?)
+//
+// Named parameters ":name" or "@name" are bound from a single map or struct argument,
+// values like time.Time and driver.Valuers excepted.
+// Struct fields are matched by their `db` tag or case insensitive by their name.
+// Named expansions ":name..." expand a slice. Each occurrence becomes a "?",
+// so the arguments are ordered for every placeholder style:
+//
+//	Literal("status = :status OR prev = :status AND id IN(:ids...)", map[string]interface{}{"status": 1, "ids": []int{7, 8}})
+//	// status = ? OR prev = ? AND id IN(?,?) with args 1, 1, 7, 8
+//
+// Parameters within quotes and comments, postgres casts "::" and MySQL variables "@@" are left untouched,
+// as are the escaped question mark "??" and the JSONB operators "?|" and "?&".
+// Argument mismatches are returned as error when the literal is visited.
 func Literal(sql string, args ...interface{}) *LiteralNode {
+	params := scanLiteralParams(sql)
 
-	if strings.Contains(sql, "?...") {
-		parts := strings.Split(sql, "?...")
-		if len(parts) > 2 {
-			sql = parts[0] + "?..." + parts[1] + "-- ERROR supports ?... only once per Literal --"
+	positional, named, expand := 0, 0, false
+	for _, p := range params {
+		if p.name == "" {
+			positional++
 		} else {
-			nargs := len(args)
-			if nargs > 0 {
-				qs := make([]rune, nargs*2-1)
+			named++
+		}
+		expand = expand || p.expand
+	}
+
+	var source interface{}
+	if named > 0 && len(args) == 1 && isNamedSource(args[0]) {
+		if positional > 0 {
+			return &LiteralNode{Sql: sql, Args: args, Err: fmt.Errorf("%w: mixed named and positional parameters: %s", ErrInvalidLiteral, sql)}
+		}
+		source = args[0]
+	} else if named > 0 {
+		// no named source - :name and @name are plain sql
+		params = filterPositionalParams(params)
+	}
+
+	if source == nil && !expand {
+		return &LiteralNode{Sql: sql, Args: args}
+	}
+
+	// a single ?... expands all arguments unless a single slice is given
+	if len(params) == 1 && params[0].expand && source == nil && !(len(args) == 1 && isExpandable(args[0])) {
+		args = []interface{}{args}
+	}
+
+	var b strings.Builder
+	bound := make([]interface{}, 0, len(args))
+	last, next := 0, 0
+	for _, p := range params {
+		b.WriteString(sql[last:p.start])
+		last = p.end
+
+		var val interface{}
+		if p.name != "" {
+			var ok bool
+			if val, ok = namedValue(source, p.name); !ok {
+				return &LiteralNode{Sql: sql, Args: args, Err: fmt.Errorf("%w: missing named parameter '%s' in: %s", ErrArgumentCount, p.name, sql)}
+			}
+		} else {
+			if next >= len(args) {
+				return &LiteralNode{Sql: sql, Args: args, Err: fmt.Errorf("%w: %d arguments for: %s", ErrArgumentCount, len(args), sql)}
+			}
+			val = args[next]
+			next++
+		}
+
+		vals := []interface{}{val}
+		if p.expand {
+			vals = expandValue(val)
+		}
+		for i, v := range vals {
+			if i > 0 {
+				b.WriteByte(COMMA)
+			}
+			b.WriteByte(QUESTION)
+			bound = append(bound, v)
+		}
+		// keep the placeholders apart from the following sql e.g. "?|" is an operator,
+		// and the sql around an empty expansion apart e.g. "-?...-" is no comment
+		if p.end < len(sql) && (len(vals) > 0 && strings.IndexByte("?|&", sql[p.end]) >= 0 || len(vals) == 0 && p.start > 0) {
+			b.WriteByte(SPACE)
+		}
+	}
+	b.WriteString(sql[last:])
 
-				for i := 0; i < len(qs); i++ {
-					if i > 0 {
-						qs[i] = ','
-						i++
-					}
-					qs[i] = '?'
-				}
-				sql = parts[0] + string(qs) + parts[1]
-			} else {
-				sql = parts[0] + parts[1]
+	if source == nil && next != len(args) {
+		return &LiteralNode{Sql: sql, Args: args, Err: fmt.Errorf("%w: %d arguments for: %s", ErrArgumentCount, len(args), sql)}
+	}
+
+	return &LiteralNode{Sql: b.String(), Args: bound}
+}
+
+// literalParam is a parameter within the sql of a Literal.
+type literalParam struct {
+	start, end int    // position within the sql
+	name       string // name of :name and @name, empty for ?
+	expand     bool   // ?... and :name...
+}
+
+// scanLiteralParams returns the parameters within sql.
+// Quoted strings, identifiers, comments and escaped question marks are skipped, see skipQuoted and questionAt.
+func scanLiteralParams(sql string) (params []literalParam) {
+	for i := 0; i < len(sql); i++ {
+		if j := skipQuoted(sql, i); j > i {
+			i = j - 1
+			continue
+		}
+		switch c := sql[i]; c {
+		case QUESTION:
+			if kind, width := questionAt(sql, i); kind != questionPlaceholder {
+				i += width - 1
+				continue
 			}
+			p := literalParam{start: i, end: i + 1}
+			if strings.HasPrefix(sql[p.end:], "...") {
+				p.end += 3
+				p.expand = true
+			}
+			params = append(params, p)
+			i = p.end - 1
+		case ':', '@':
+			// skip postgres casts ::, @@ variables and e.g. arr[lo:hi]
+			if i > 0 && (sql[i-1] == c || isNameByte(sql[i-1], false)) {
+				continue
+			}
+			j := i + 1
+			for j < len(sql) && isNameByte(sql[j], j == i+1) {
+				j++
+			}
+			if j == i+1 {
+				continue
+			}
+			p := literalParam{start: i, end: j, name: sql[i+1 : j]}
+			if strings.HasPrefix(sql[p.end:], "...") {
+				p.end += 3
+				p.expand = true
+			}
+			params = append(params, p)
+			i = p.end - 1
+		}
+	}
+	return
+}
+
+func filterPositionalParams(params []literalParam) []literalParam {
+	positional := params[:0:0]
+	for _, p := range params {
+		if p.name == "" {
+			positional = append(positional, p)
 		}
 	}
+	return positional
+}
+
+// isExpandable is true for slices and arrays except []byte.
+func isExpandable(o interface{}) bool {
+	if _, ok := o.([]byte); ok {
+		return false
+	}
+	k := reflect.ValueOf(o).Kind()
+	return k == reflect.Slice || k == reflect.Array
+}
+
+// expandValue returns the elements of a slice or array, other values as single element slice.
+func expandValue(o interface{}) []interface{} {
+	if vals, ok := o.([]interface{}); ok {
+		return vals
+	}
+	if !isExpandable(o) {
+		return []interface{}{o}
+	}
+	rv := reflect.ValueOf(o)
+	vals := make([]interface{}, rv.Len())
+	for i := range vals {
+		vals[i] = rv.Index(i).Interface()
+	}
+	return vals
+}
 
-	return &LiteralNode{sql, args}
+// isNamedSource is true for maps with string keys and structs.
+// Values passed to the driver as they are e.g. time.Time and driver.Valuers are no sources.
+func isNamedSource(o interface{}) bool {
+	rv := reflect.Indirect(reflect.ValueOf(o))
+	if !rv.IsValid() {
+		return false
+	}
+	t := rv.Type()
+	if t == reflect.TypeOf(time.Time{}) || reflect.PtrTo(t).Implements(valuerType) {
+		return false
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	case reflect.Struct:
+		return true
+	}
+	return false
 }
 
-// TODO klimbim methoden
+// namedValue returns the map entry or struct field `name` of source.
+func namedValue(source interface{}, name string) (interface{}, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(source))
+	if rv.Kind() == reflect.Map {
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		return v.Interface(), true
+	}
 
-// count
-// sum
-// max
-// min
-// avg
-// extract(field)
-// equal
-// notequal
-// ...
-// matches
-// ...
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("db"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == name || tag == "" && strings.EqualFold(f.Name, name) {
+			return rv.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
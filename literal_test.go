@@ -1,8 +1,11 @@
 package codex
 
 import (
+	"database/sql/driver"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestLiteralEmpty(t *testing.T) {
@@ -45,8 +48,172 @@ func TestLiteralExpand(t *testing.T) {
 	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9}, l.Args)
 }
 
-func TestLiteralExpandTwiceRaisesError(t *testing.T) {
+func TestLiteralExpandTwice(t *testing.T) {
+	l := Literal("bar IN(?...) OR foo IN(?...)", []int{1, 2}, []string{"a", "b", "c"})
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "bar IN(?,?) OR foo IN(?,?,?)", l.Sql)
+	assert.Equal(t, []interface{}{1, 2, "a", "b", "c"}, l.Args)
+}
+
+func TestLiteralExpandSingleSlice(t *testing.T) {
+	l := Literal("id IN(?...)", []int64{4, 5})
+	assert.Equal(t, "id IN(?,?)", l.Sql)
+	assert.Equal(t, []interface{}{int64(4), int64(5)}, l.Args)
+
+	l = Literal("id IN(?...)", []interface{}{"a"})
+	assert.Equal(t, "id IN(?)", l.Sql)
+	assert.Equal(t, []interface{}{"a"}, l.Args)
+
+	// []byte is a value
+	l = Literal("data IN(?...)", []byte("ab"))
+	assert.Equal(t, "data IN(?)", l.Sql)
+	assert.Equal(t, []interface{}{[]byte("ab")}, l.Args)
+}
+
+func TestLiteralExpandMixedWithPositional(t *testing.T) {
+	l := Literal("a = ? AND b IN(?...) AND c = ? AND d IN(?...)", 1, []int{2, 3}, "x", [2]bool{true, false})
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "a = ? AND b IN(?,?) AND c = ? AND d IN(?,?)", l.Sql)
+	assert.Equal(t, []interface{}{1, 2, 3, "x", true, false}, l.Args)
+
+	// a non slice argument of an expansion is a single value
+	l = Literal("a IN(?...) AND b = ?", 1, 2)
+	assert.Equal(t, "a IN(?) AND b = ?", l.Sql)
+	assert.Equal(t, []interface{}{1, 2}, l.Args)
+}
+
+func TestLiteralExpandArgumentCountError(t *testing.T) {
 	l := Literal("bar IN(?...) OR foo IN(?...)", 1, 2, 3)
-	assert.Equal(t, "bar IN(?...) OR foo IN(-- ERROR supports ?... only once per Literal --", l.Sql)
+	assert.Equal(t, "bar IN(?...) OR foo IN(?...)", l.Sql)
 	assert.Equal(t, []interface{}{1, 2, 3}, l.Args)
+	assert.True(t, errors.Is(l.Err, ErrArgumentCount))
+
+	l = Literal("a = ? AND b IN(?...)", 1)
+	assert.True(t, errors.Is(l.Err, ErrArgumentCount))
+
+	sql, args, err := Table("users").Where("a IN(?...) AND b = ?", []int{1}).ToSql()
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
+
+func TestLiteralExpandSkipsQuotes(t *testing.T) {
+	l := Literal(`a IN(?...) AND b = '?...' AND "c?..." = ?`, []int{1, 2}, 3)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, `a IN(?,?) AND b = '?...' AND "c?..." = ?`, l.Sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, l.Args)
+}
+
+func TestLiteralNamedMap(t *testing.T) {
+	l := Literal("status = :status OR prev = @status AND id IN(:ids...)", map[string]interface{}{"status": "new", "ids": []int{7, 8}})
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "status = ? OR prev = ? AND id IN(?,?)", l.Sql)
+	assert.Equal(t, []interface{}{"new", "new", 7, 8}, l.Args)
+
+	l = Literal("id = :id", map[string]int{"id": 3})
+	assert.Equal(t, "id = ?", l.Sql)
+	assert.Equal(t, []interface{}{3}, l.Args)
+}
+
+func TestLiteralNamedStruct(t *testing.T) {
+	type filter struct {
+		Status  string
+		MinAge  int `db:"min_age"`
+		Ignored int `db:"-"`
+		secret  int
+	}
+	f := filter{Status: "new", MinAge: 18, Ignored: 1, secret: 2}
+
+	l := Literal("status = :status AND age >= :min_age", f)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "status = ? AND age >= ?", l.Sql)
+	assert.Equal(t, []interface{}{"new", 18}, l.Args)
+
+	l = Literal("status = @Status", &f)
+	assert.Equal(t, "status = ?", l.Sql)
+	assert.Equal(t, []interface{}{"new"}, l.Args)
+
+	for _, sql := range []string{"x = :ignored", "x = :secret", "x = :minage", "x = :missing"} {
+		l = Literal(sql, f)
+		assert.True(t, errors.Is(l.Err, ErrArgumentCount), sql)
+	}
+}
+
+func TestLiteralNamedSkipsCastsVariablesAndQuotes(t *testing.T) {
+	l := Literal("a::int = :a AND @@sql_mode = @mode AND t = '12:30' AND arr[lo:hi] @> :arr", map[string]interface{}{"a": 1, "mode": "x", "arr": "{}"})
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "a::int = ? AND @@sql_mode = ? AND t = '12:30' AND arr[lo:hi] @> ?", l.Sql)
+	assert.Equal(t, []interface{}{1, "x", "{}"}, l.Args)
+}
+
+func TestLiteralNamedWithoutSourceIsPlainSql(t *testing.T) {
+	l := Literal("a = :a AND b = ?", 1)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "a = :a AND b = ?", l.Sql)
+	assert.Equal(t, []interface{}{1}, l.Args)
+
+	l = Literal("a = :a AND b = ?", map[string]interface{}{"a": 1})
+	assert.True(t, errors.Is(l.Err, ErrInvalidLiteral))
+
+	// a struct argument for a positional parameter is a value
+	type point struct{ X, Y int }
+	l = Literal("p = ?", point{1, 2})
+	assert.Equal(t, "p = ?", l.Sql)
+	assert.Equal(t, []interface{}{point{1, 2}}, l.Args)
+}
+
+type nullDate struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (d nullDate) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.Time, nil
+}
+
+func TestLiteralNamedValuesAreNoSource(t *testing.T) {
+	since := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	l := Literal("created_at > :since::date", since)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "created_at > :since::date", l.Sql)
+	assert.Equal(t, []interface{}{since}, l.Args)
+
+	l = Literal("created_at > :since::date", &since)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, []interface{}{&since}, l.Args)
+
+	date := nullDate{since, true}
+	l = Literal("created_at > :since AND valid = ?", date)
+	assert.Nil(t, l.Err)
+	assert.Equal(t, "created_at > :since AND valid = ?", l.Sql)
+	assert.Equal(t, []interface{}{date}, l.Args)
+}
+
+func TestLiteralNamedDialects(t *testing.T) {
+	params := map[string]interface{}{"status": "new", "ids": []int{7, 8}}
+	for adapter, expected := range map[adapter]string{
+		0:        `SELECT "users".* FROM "users" WHERE (status = ? AND id IN(?,?) OR prev = ?)`,
+		MYSQL:    "SELECT `users`.* FROM `users` WHERE (status = ? AND id IN(?,?) OR prev = ?)",
+		POSTGRES: `SELECT "users".* FROM "users" WHERE (status = $1 AND id IN($2,$3) OR prev = $4)`,
+	} {
+		sql, args, err := Dialect(adapter).Table("users").Where("status = :status AND id IN(:ids...) OR prev = :status", params).ToSql()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, sql)
+		assert.Equal(t, []interface{}{"new", 7, 8, "new"}, args)
+	}
+}
+
+func TestLiteralExpandKeepsPlaceholdersApart(t *testing.T) {
+	l := Literal("a IN(?...)|b", []int{1})
+	assert.Equal(t, "a IN(?)|b", l.Sql)
+
+	l = Literal("?...&x AND :a?? AND ?...?? AND -?...-", []int{1}, []int{}, []int{})
+	assert.Equal(t, "? &x AND :a?? AND  ?? AND - -", l.Sql)
+	assert.Equal(t, []interface{}{1}, l.Args)
+
+	l = Literal("x = :a|y", map[string]interface{}{"a": 1})
+	assert.Equal(t, "x = ? |y", l.Sql)
 }
@@ -1,9 +1,5 @@
 package codex
 
-import (
-	"fmt"
-)
-
 const (
 	MYSQL_QUOTE = '`'
 )
@@ -15,37 +11,228 @@ type MySqlVisitor struct {
 var _ VisitorInterface = (*MySqlVisitor)(nil)
 
 func NewMySqlVisitor() *MySqlVisitor {
-	return &MySqlVisitor{NewToSqlVisitor()}
+	v := NewToSqlVisitor()
+	v.Quote = MYSQL_QUOTE
+	v.adapter = MYSQL
+	return &MySqlVisitor{v}
 }
 
 func (v *MySqlVisitor) Accept(o interface{}) (string, []interface{}, error) {
-	err := v.Visit(o, v)
+	if err := v.Visit(o, v); err != nil {
+		return "", nil, err
+	}
+
+	return v.String(), v.Args(), nil
+}
+
+// VisitValuesTable renders MySQL 8 syntax (VALUES ROW(?,?),ROW(?,?)) AS `alias`(`col1`,`col2`)
+func (v *MySqlVisitor) VisitValuesTable(o *ValuesTableNode, visitor VisitorInterface) (err error) {
+	return visitValuesTable(o, "ROW", visitor)
+}
 
-	return v.String(), v.Args(), err
+// VisitInsertStatement returns ErrUnsupportedByDialect for INSERT ... RETURNING.
+func (v *MySqlVisitor) VisitInsertStatement(o *InsertStatementNode, visitor VisitorInterface) (err error) {
+	if nil != o.Returning {
+		return unsupportedByDialect("MySQL", "INSERT ... RETURNING")
+	}
+	return v.ToSqlVisitor.VisitInsertStatement(o, visitor)
 }
 
-// Begin Helpers.
+// VisitUpdateStatement renders the Froms of the statement as multi table update
+// `UPDATE t, src SET ...` as MySQL does not support UPDATE ... FROM.
+func (v *MySqlVisitor) VisitUpdateStatement(o *UpdateStatementNode, visitor VisitorInterface) (err error) {
+	if 0 == len(o.Froms) {
+		return v.ToSqlVisitor.VisitUpdateStatement(o, visitor)
+	}
+
+	visitor.AppendSqlStr("UPDATE ")
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
+	}
+	for _, from := range o.Froms {
+		visitor.AppendSqlByte(COMMA)
+		err = visitor.Visit(from, visitor)
+		if err != nil {
+			return
+		}
+	}
+	visitor.AppendSqlByte(SPACE)
+
+	if length := len(o.Values) - 1; 0 <= length {
+		visitor.AppendSqlStr("SET ")
+		for index, assignment := range o.Values {
+			err = visitor.Visit(assignment, visitor)
+			if err != nil {
+				return
+			}
+			if index != length {
+				visitor.AppendSqlByte(COMMA)
+			} else {
+				visitor.AppendSqlByte(SPACE)
+			}
+		}
+	}
+
+	if length := len(o.Wheres) - 1; 0 <= length {
+		visitor.AppendSqlStr("WHERE ")
+		for index, filter := range o.Wheres {
+			err = visitor.Visit(filter, visitor)
+			if err != nil {
+				return
+			}
+			if index != length {
+				visitor.AppendSqlStr(AND)
+			}
+		}
+	}
 
-func (v *MySqlVisitor) QuoteTableName(o interface{}, visitor VisitorInterface) (err error) {
-	s, ok := o.(string)
-	if !ok {
-		return fmt.Errorf("MySqlVisitor.QuoteTableName() expected string but got %#v", o)
+	if nil != o.Limit {
+		visitor.AppendSqlByte(SPACE)
+		err = visitor.Visit(o.Limit, visitor)
 	}
-	visitor.AppendSqlByte(MYSQL_QUOTE)
-	visitor.AppendSqlStr(s)
-	visitor.AppendSqlByte(MYSQL_QUOTE)
+
 	return
 }
 
-func (v *MySqlVisitor) QuoteColumnName(o interface{}, visitor VisitorInterface) (err error) {
-	s, ok := o.(string)
-	if !ok {
-		return fmt.Errorf("MySqlVisitor.QuoteColumnName() expected string but got %#v", o)
+// VisitCreateTableStatement renders the references of columns as FOREIGN KEY constraints,
+// MySQL ignores inline REFERENCES.
+func (v *MySqlVisitor) VisitCreateTableStatement(o *CreateTableStatementNode, visitor VisitorInterface) (err error) {
+	n := *o
+	n.Columns = make([]*ColumnDef, len(o.Columns))
+	n.Constraints = nil
+	for i, column := range o.Columns {
+		var fk *ForeignKeyNode
+		n.Columns[i], fk = splitForeignKey(column)
+		if fk != nil {
+			n.Constraints = append(n.Constraints, fk)
+		}
+	}
+	n.Constraints = append(n.Constraints, o.Constraints...)
+	return v.ToSqlVisitor.VisitCreateTableStatement(&n, visitor)
+}
+
+// VisitAlterTableStatement renders the references of added columns as ADD FOREIGN KEY
+// and returns ErrUnsupportedByDialect for ALTER TABLE IF EXISTS.
+func (v *MySqlVisitor) VisitAlterTableStatement(o *AlterTableStatementNode, visitor VisitorInterface) (err error) {
+	if o.IfExists {
+		return unsupportedByDialect("MySQL", "ALTER TABLE IF EXISTS")
+	}
+
+	n := *o
+	n.Actions = make([]interface{}, 0, len(o.Actions))
+	for _, action := range o.Actions {
+		if add, ok := action.(*AddColumnNode); ok && add.Column.References != nil {
+			column, fk := splitForeignKey(add.Column)
+			n.Actions = append(n.Actions, &AddColumnNode{column}, &AddConstraintNode{fk})
+			continue
+		}
+		n.Actions = append(n.Actions, action)
+	}
+	return v.ToSqlVisitor.VisitAlterTableStatement(&n, visitor)
+}
+
+// VisitCreateIndexStatement renders CREATE [UNIQUE ]INDEX `name`[ USING method] ON `table` (columns)
+// and returns ErrUnsupportedByDialect for CONCURRENTLY, IF NOT EXISTS and partial indexes.
+func (v *MySqlVisitor) VisitCreateIndexStatement(o *CreateIndexStatementNode, visitor VisitorInterface) (err error) {
+	switch {
+	case o.Concurrently:
+		return unsupportedByDialect("MySQL", "CREATE INDEX CONCURRENTLY")
+	case o.IfNotExists:
+		return unsupportedByDialect("MySQL", "CREATE INDEX IF NOT EXISTS")
+	case 0 < len(o.Wheres):
+		return unsupportedByDialect("MySQL", "partial indexes")
+	case o.Name == "":
+		return unsupportedByDialect("MySQL", "indexes without name")
+	}
+
+	visitor.AppendSqlStr("CREATE ")
+	if o.Unique {
+		visitor.AppendSqlStr("UNIQUE ")
+	}
+	visitor.AppendSqlStr("INDEX ")
+	err = visitor.QuoteColumnName(o.Name, visitor)
+	if err != nil {
+		return
+	}
+	if err = visitIndexMethod(o.Using, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlStr(" ON ")
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+	return visitIndexColumns(o.Columns, visitor)
+}
+
+// VisitDropIndexStatement renders DROP INDEX `name` ON `table`
+// and returns ErrUnsupportedByDialect for IF EXISTS, CONCURRENTLY and CASCADE.
+func (v *MySqlVisitor) VisitDropIndexStatement(o *DropIndexStatementNode, visitor VisitorInterface) (err error) {
+	switch {
+	case o.IfExists:
+		return unsupportedByDialect("MySQL", "DROP INDEX IF EXISTS")
+	case o.Concurrently:
+		return unsupportedByDialect("MySQL", "DROP INDEX CONCURRENTLY")
+	case o.Cascade:
+		return unsupportedByDialect("MySQL", "DROP INDEX ... CASCADE")
+	case o.Table == nil:
+		return unsupportedByDialect("MySQL", "DROP INDEX without table")
+	}
+
+	visitor.AppendSqlStr("DROP INDEX ")
+	err = visitor.QuoteColumnName(o.Name, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlStr(" ON ")
+	return visitor.Visit(o.Table, visitor)
+}
+
+// VisitColumnDef renders AUTO_INCREMENT for identity columns.
+func (v *MySqlVisitor) VisitColumnDef(o *ColumnDef, visitor VisitorInterface) (err error) {
+	return visitColumnDef(o, "AUTO_INCREMENT", visitor)
+}
+
+// VisitAlterColumn renders MODIFY COLUMN `name` type[ NOT NULL][ DEFAULT value][ AUTO_INCREMENT].
+// MySQL requires the full definition, so o.From is ignored. Identity columns are rendered without default.
+func (v *MySqlVisitor) VisitAlterColumn(o *AlterColumnNode, visitor VisitorInterface) (err error) {
+	column := o.Column
+	if column.Identity && column.Default != nil {
+		c := *column
+		c.Default = nil
+		column = &c
+	}
+
+	visitor.AppendSqlStr("MODIFY COLUMN ")
+	err = visitor.QuoteColumnName(column.Name, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+	if err = visitColumnType(column, visitor); err != nil {
+		return
+	}
+	if column.Identity {
+		visitor.AppendSqlStr(" AUTO_INCREMENT")
 	}
-	visitor.AppendSqlByte(MYSQL_QUOTE)
-	visitor.AppendSqlStr(s)
-	visitor.AppendSqlByte(MYSQL_QUOTE)
 	return
 }
 
-// End Helpers.
+// splitForeignKey returns a copy of the column without references and
+// its references as FOREIGN KEY constraint, nil if the column has none.
+func splitForeignKey(column *ColumnDef) (*ColumnDef, *ForeignKeyNode) {
+	ref := column.References
+	if ref == nil {
+		return column, nil
+	}
+	c := *column
+	c.References = nil
+
+	fk := Foreign(column.Name).OnDelete(ref.OnDelete)
+	if ref.Column != "" {
+		return &c, fk.References(referencedTable(ref), ref.Column)
+	}
+	return &c, fk.References(referencedTable(ref))
+}
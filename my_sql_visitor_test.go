@@ -18,3 +18,25 @@ func TestQuoteTableName(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "`foo_bar2`", v.String())
 }
+
+func TestQuoteColumnNameWithBacktickIsEscaped(t *testing.T) {
+	v := NewMySqlVisitor()
+	err := v.QuoteColumnName("foo`bar", v)
+	assert.Nil(t, err)
+	assert.Equal(t, "`foo``bar`", v.String())
+}
+
+func TestQuoteTableNameStrict(t *testing.T) {
+	v := NewMySqlVisitor()
+	v.Strict = true
+	err := v.QuoteTableName("foo`bar", v)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid table name: 'foo`bar'", err.Error())
+}
+
+func TestQuoteColumnNameEmptyReturnsError(t *testing.T) {
+	v := NewMySqlVisitor()
+	err := v.QuoteColumnName("", v)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid column name: ''", err.Error())
+}
@@ -0,0 +1,137 @@
+package codex
+
+import (
+	"bytes"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderStyle selects how a PlaceholderCollector renders bind parameters.
+type PlaceholderStyle uint8
+
+const (
+	QUESTION_MARK PlaceholderStyle = iota // ? MySQL, SQLite
+	DOLLAR_NUMBER                         // $1, $2 PostgreSQL, CockroachDB
+	COLON_NUMBER                          // :1, :2 Oracle
+	AT_P_NUMBER                           // @p1, @p2 SQL Server
+	COLON_NAME                            // :p1, :name with sql.NamedArg args e.g. sqlx, Oracle
+	AT_NAME                               // @p1, @name with sql.NamedArg args e.g. SQL Server
+)
+
+// Named reports whether the style renders names and sql.NamedArg args.
+func (style PlaceholderStyle) Named() bool {
+	return style == COLON_NAME || style == AT_NAME
+}
+
+// PlaceholderCollector collects SQL with placeholders in a PlaceholderStyle.
+// Question marks in quoted strings and comments are no placeholders,
+// the escaped question mark "??" is kept for QUESTION_MARK and unescaped otherwise, see questionAt.
+//
+// Named styles use the name of sql.NamedArg args e.g. sql.Named("status", "new") renders :status,
+// other args are named p1, p2 ... pn by their position and passed as sql.NamedArg.
+type PlaceholderCollector struct {
+	Style  PlaceholderStyle
+	sqlBuf bytes.Buffer
+	args   []interface{}
+	marks  []int // positions of the placeholders within sqlBuf
+}
+
+var _ CollectorInterface = (*PlaceholderCollector)(nil)
+
+func (c *PlaceholderCollector) AppendSqlStr(s string) {
+	if !strings.ContainsRune(s, QUESTION) {
+		c.sqlBuf.WriteString(s)
+		return
+	}
+
+	scanPlaceholders(s, c.Style != QUESTION_MARK, func(text string) {
+		c.sqlBuf.WriteString(text)
+	}, func() {
+		c.marks = append(c.marks, c.sqlBuf.Len())
+	})
+}
+
+func (c *PlaceholderCollector) AppendSqlByte(b byte) {
+	if b == QUESTION {
+		c.marks = append(c.marks, c.sqlBuf.Len())
+	} else {
+		c.sqlBuf.WriteByte(b)
+	}
+}
+
+func (c *PlaceholderCollector) AppendArg(a interface{}) {
+	c.args = append(c.args, a)
+}
+
+// String returns the SQL with the placeholders rendered in the collector's Style.
+func (c *PlaceholderCollector) String() string {
+	if 0 == len(c.marks) {
+		return c.sqlBuf.String()
+	}
+
+	sql := c.sqlBuf.Bytes()
+	var b strings.Builder
+	b.Grow(len(sql) + len(c.marks)*4)
+
+	last := 0
+	for i, mark := range c.marks {
+		b.Write(sql[last:mark])
+		last = mark
+		b.WriteString(c.placeholder(i))
+	}
+	b.Write(sql[last:])
+	return b.String()
+}
+
+// Args returns the collected args, named styles wrap them into sql.NamedArg.
+func (c *PlaceholderCollector) Args() []interface{} {
+	if !c.Style.Named() {
+		return c.args
+	}
+
+	args := make([]interface{}, len(c.args))
+	for i, arg := range c.args {
+		if named, ok := arg.(sql.NamedArg); ok {
+			arg = named.Value
+		}
+		args[i] = sql.Named(c.argName(i), arg)
+	}
+	return args
+}
+
+func (c *PlaceholderCollector) placeholder(i int) string {
+	n := strconv.Itoa(i + 1)
+	switch c.Style {
+	case DOLLAR_NUMBER:
+		return "$" + n
+	case COLON_NUMBER:
+		return ":" + n
+	case AT_P_NUMBER:
+		return "@p" + n
+	case COLON_NAME:
+		return ":" + c.argName(i)
+	case AT_NAME:
+		return "@" + c.argName(i)
+	default:
+		return string(QUESTION)
+	}
+}
+
+// argName returns the name of the i-th arg, p1, p2 ... pn unless it is a sql.NamedArg.
+func (c *PlaceholderCollector) argName(i int) string {
+	if i < len(c.args) {
+		if named, ok := c.args[i].(sql.NamedArg); ok && named.Name != "" {
+			return named.Name
+		}
+	}
+	return "p" + strconv.Itoa(i+1)
+}
+
+// creates a PlaceholderCollector with 512 bytes buffer capacity
+func NewPlaceholderCollector(style PlaceholderStyle) *PlaceholderCollector {
+	return &PlaceholderCollector{
+		Style:  style,
+		sqlBuf: *bytes.NewBuffer(make([]byte, 0, EXPECTED_SQL_QUERY_LEN)),
+	}
+}
@@ -0,0 +1,138 @@
+package codex
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPlaceholderCollectorStyles(t *testing.T) {
+	for style, expected := range map[PlaceholderStyle]string{
+		QUESTION_MARK: `WHERE id=? AND name IN(?,?)`,
+		DOLLAR_NUMBER: `WHERE id=$1 AND name IN($2,$3)`,
+		COLON_NUMBER:  `WHERE id=:1 AND name IN(:2,:3)`,
+		AT_P_NUMBER:   `WHERE id=@p1 AND name IN(@p2,@p3)`,
+		COLON_NAME:    `WHERE id=:p1 AND name IN(:p2,:p3)`,
+		AT_NAME:       `WHERE id=@p1 AND name IN(@p2,@p3)`,
+	} {
+		c := NewPlaceholderCollector(style)
+		c.AppendSqlStr("WHERE id")
+		c.AppendSqlByte(EQUAL)
+		c.AppendSqlByte(QUESTION)
+		c.AppendArg(77)
+		c.AppendSqlStr(" AND name IN(?,?)")
+		c.AppendArg("a")
+		c.AppendArg("b")
+		assert.Equal(t, expected, c.String(), style)
+		if style.Named() {
+			assert.Equal(t, []interface{}{sql.Named("p1", 77), sql.Named("p2", "a"), sql.Named("p3", "b")}, c.Args())
+		} else {
+			assert.Equal(t, []interface{}{77, "a", "b"}, c.Args())
+		}
+	}
+}
+
+func TestPlaceholderCollectorEmpty(t *testing.T) {
+	c := NewPlaceholderCollector(DOLLAR_NUMBER)
+	assert.Equal(t, EXPECTED_SQL_QUERY_LEN, cap(c.sqlBuf.Bytes()))
+	assert.Equal(t, "", c.String())
+	assert.Empty(t, c.Args())
+}
+
+func TestPlaceholderCollectorQuotedAndEscaped(t *testing.T) {
+	sql := `a = ? AND b = '?' AND data ?? 'k' AND data ?| ? -- ?`
+	for style, expected := range map[PlaceholderStyle]string{
+		QUESTION_MARK: `a = ? AND b = '?' AND data ?? 'k' AND data ?| ? -- ?`,
+		DOLLAR_NUMBER: `a = $1 AND b = '?' AND data ? 'k' AND data ?| $2 -- ?`,
+		AT_P_NUMBER:   `a = @p1 AND b = '?' AND data ? 'k' AND data ?| @p2 -- ?`,
+	} {
+		c := NewPlaceholderCollector(style)
+		c.AppendSqlStr(sql)
+		assert.Equal(t, expected, c.String(), style)
+	}
+}
+
+func TestPlaceholderCollectorSqlNamedArgs(t *testing.T) {
+	users := Table("users")
+	m := users.Where(users.Col("status").Eq(sql.Named("status", "new"))).Where(users.Col("id").Gt(5))
+
+	q, args, err := m.ToSqlWith(COLON_NAME)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."status"=:status) AND ("users"."id">:p2)`, q)
+	assert.Equal(t, []interface{}{sql.Named("status", "new"), sql.Named("p2", 5)}, args)
+
+	q, args, err = m.ToSqlWith(AT_NAME)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."status"=@status) AND ("users"."id">@p2)`, q)
+	assert.Equal(t, []interface{}{sql.Named("status", "new"), sql.Named("p2", 5)}, args)
+
+	// numbered styles keep sql.NamedArg as is
+	q, args, err = m.ToSqlWith(AT_P_NUMBER)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."status"=@p1) AND ("users"."id">@p2)`, q)
+	assert.Equal(t, []interface{}{sql.Named("status", "new"), 5}, args)
+
+	// empty names are numbered
+	_, args, err = users.Where(users.Col("id").Eq(sql.Named("", 1))).ToSqlWith(COLON_NAME)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{sql.Named("p1", 1)}, args)
+}
+
+func TestPlaceholderManagersToSqlWith(t *testing.T) {
+	users := Table("users")
+
+	q, args, err := users.Where(users.Col("id").In(1, 2)).Limit(3).ToSqlWith(COLON_NUMBER)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."id" IN(:1,:2)) LIMIT :3`, q)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	q, args, err = users.Insert(1, "a").Into("id", "name").ToSqlWith(DOLLAR_NUMBER)
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("id","name") VALUES ($1,$2)`, q)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+
+	q, args, err = users.Set("name").To("b").Where(users.Col("id").Eq(1)).ToSqlWith(AT_P_NUMBER)
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=@p1 WHERE ("users"."id"=@p2)`, q)
+	assert.Equal(t, []interface{}{"b", 1}, args)
+
+	q, args, err = users.Delete(users.Col("id").Eq(1)).ToSqlWith(COLON_NAME)
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."id"=:p1)`, q)
+	assert.Equal(t, []interface{}{sql.Named("p1", 1)}, args)
+}
+
+func TestPlaceholderDialectVisitors(t *testing.T) {
+	// MySQL quoting with numbered placeholders
+	mysql := Dialect(MYSQL).Table("users")
+	q, _, err := mysql.Where(mysql.Col("id").Eq(1)).ToSqlWith(DOLLAR_NUMBER)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.* FROM `users` WHERE (`users`.`id`=$1)", q)
+
+	// PostgreSQL visitor e.g. ILIKE with question marks
+	psql := Dialect(POSTGRES).Table("users")
+	q, _, err = psql.Where(psql.Col("name").Like("a%")).ToSqlWith(QUESTION_MARK)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."name" ILIKE ?)`, q)
+
+	// errors are returned
+	_, _, err = psql.InnerJoin(1).ToSqlWith(DOLLAR_NUMBER)
+	assert.NotNil(t, err)
+}
+
+func TestVisitorWith(t *testing.T) {
+	c := NewPlaceholderCollector(AT_P_NUMBER)
+
+	v := VisitorWith(adapter(0), c)
+	assert.IsType(t, &ToSqlVisitor{}, v)
+	assert.Equal(t, c, v.(*ToSqlVisitor).CollectorInterface)
+
+	v = VisitorWith(MYSQL, c)
+	assert.IsType(t, &MySqlVisitor{}, v)
+	assert.Equal(t, c, v.(*MySqlVisitor).CollectorInterface)
+	assert.Equal(t, byte(MYSQL_QUOTE), v.(*MySqlVisitor).Quote)
+
+	v = VisitorWith(POSTGRES, c)
+	assert.IsType(t, &PostgresVisitor{}, v)
+	assert.Equal(t, c, v.(*PostgresVisitor).CollectorInterface)
+}
@@ -14,13 +14,17 @@ var _ VisitorInterface = (*PostgresVisitor)(nil)
 // creates PostgresVisitor with PostgresCollector
 func NewPostgresVisitor() *PostgresVisitor {
 	// can not use NewToSqlVisitor() because PostgresCollector needed instead of Collector.
-	return &PostgresVisitor{NewToSqlVisitor(NewPostgresCollector()), 0}
+	v := NewToSqlVisitor(NewPostgresCollector())
+	v.adapter = POSTGRES
+	return &PostgresVisitor{v, 0}
 }
 
 func (v *PostgresVisitor) Accept(o interface{}) (string, []interface{}, error) {
-	err := v.Visit(o, v)
+	if err := v.Visit(o, v); err != nil {
+		return "", nil, err
+	}
 
-	return v.String(), v.Args(), err
+	return v.String(), v.Args(), nil
 }
 
 // TODO obsolete
@@ -50,3 +54,16 @@ func (v *PostgresVisitor) VisitUnlike(o *UnlikeNode, visitor VisitorInterface) (
 	err = visitor.Visit(o.Right, visitor)
 	return
 }
+
+// VisitAlterTableStatement returns ErrUnsupportedByDialect for RENAME combined with other actions.
+func (v *PostgresVisitor) VisitAlterTableStatement(o *AlterTableStatementNode, visitor VisitorInterface) (err error) {
+	if 1 < len(o.Actions) {
+		for _, action := range o.Actions {
+			switch action.(type) {
+			case *RenameColumnNode, *RenameTableNode:
+				return unsupportedByDialect("PostgreSQL", "RENAME combined with other ALTER TABLE actions")
+			}
+		}
+	}
+	return v.ToSqlVisitor.VisitAlterTableStatement(o, visitor)
+}
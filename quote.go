@@ -0,0 +1,115 @@
+package codex
+
+import (
+	"strings"
+)
+
+// reservedWords are rejected as identifiers by strict visitors, per dialect. Keys are upper case.
+// The words of adapter 0 are the ones of generic ToSqlVisitors.
+var reservedWords = map[adapter]map[string]bool{
+	0: wordSet(`
+		ALL ALTER AND ANY AS ASC BETWEEN BOTH BY CASE CAST CHECK COLLATE COLUMN
+		CONSTRAINT CREATE CROSS CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP
+		CURRENT_USER DEFAULT DELETE DESC DISTINCT DROP ELSE END EXCEPT EXISTS
+		FALSE FETCH FOR FOREIGN FROM FULL GRANT GROUP HAVING IN INNER INSERT
+		INTERSECT INTO IS JOIN LEADING LEFT LIKE LIMIT NATURAL NOT NULL OFFSET
+		ON OR ORDER OUTER PRIMARY REFERENCES RIGHT SELECT SET SOME TABLE THEN TO
+		TRAILING TRUE UNION UNIQUE UPDATE USER USING VALUES WHEN WHERE WITH
+	`),
+	// https://www.postgresql.org/docs/current/sql-keywords-appendix.html reserved ones,
+	// including those allowed as function or type names
+	POSTGRES: wordSet(`
+		ALL ANALYSE ANALYZE AND ANY ARRAY AS ASC ASYMMETRIC AUTHORIZATION BINARY BOTH
+		CASE CAST CHECK COLLATE COLLATION COLUMN CONCURRENTLY CONSTRAINT CREATE CROSS
+		CURRENT_CATALOG CURRENT_DATE CURRENT_ROLE CURRENT_SCHEMA CURRENT_TIME CURRENT_TIMESTAMP
+		CURRENT_USER DEFAULT DEFERRABLE DESC DISTINCT DO ELSE END EXCEPT FALSE FETCH FOR
+		FOREIGN FREEZE FROM FULL GRANT GROUP HAVING ILIKE IN INITIALLY INNER INTERSECT INTO
+		IS ISNULL JOIN LATERAL LEADING LEFT LIKE LIMIT LOCALTIME LOCALTIMESTAMP NATURAL NOT
+		NOTNULL NULL OFFSET ON ONLY OR ORDER OUTER OVERLAPS PLACING PRIMARY REFERENCES
+		RETURNING RIGHT SELECT SESSION_USER SIMILAR SOME SYMMETRIC SYSTEM_USER TABLE
+		TABLESAMPLE THEN TO TRAILING TRUE UNION UNIQUE USER USING VARIADIC VERBOSE WHEN
+		WHERE WINDOW WITH
+	`),
+	// https://dev.mysql.com/doc/refman/8.0/en/keywords.html reserved ones
+	MYSQL: wordSet(`
+		ACCESSIBLE ADD ALL ALTER ANALYZE AND AS ASC ASENSITIVE BEFORE BETWEEN BIGINT BINARY
+		BLOB BOTH BY CALL CASCADE CASE CHANGE CHAR CHARACTER CHECK COLLATE COLUMN CONDITION
+		CONSTRAINT CONTINUE CONVERT CREATE CROSS CUBE CUME_DIST CURRENT_DATE CURRENT_TIME
+		CURRENT_TIMESTAMP CURRENT_USER CURSOR DATABASE DATABASES DAY_HOUR DAY_MICROSECOND
+		DAY_MINUTE DAY_SECOND DEC DECIMAL DECLARE DEFAULT DELAYED DELETE DENSE_RANK DESC
+		DESCRIBE DETERMINISTIC DISTINCT DISTINCTROW DIV DOUBLE DROP DUAL EACH ELSE ELSEIF
+		EMPTY ENCLOSED ESCAPED EXCEPT EXISTS EXIT EXPLAIN FALSE FETCH FIRST_VALUE FLOAT
+		FLOAT4 FLOAT8 FOR FORCE FOREIGN FROM FULLTEXT FUNCTION GENERATED GET GRANT GROUP
+		GROUPING GROUPS HAVING HIGH_PRIORITY HOUR_MICROSECOND HOUR_MINUTE HOUR_SECOND IF
+		IGNORE IN INDEX INFILE INNER INOUT INSENSITIVE INSERT INT INT1 INT2 INT3 INT4 INT8
+		INTEGER INTERSECT INTERVAL INTO IO_AFTER_GTIDS IO_BEFORE_GTIDS IS ITERATE JOIN
+		JSON_TABLE KEY KEYS KILL LAG LAST_VALUE LATERAL LEAD LEADING LEAVE LEFT LIKE LIMIT
+		LINEAR LINES LOAD LOCALTIME LOCALTIMESTAMP LOCK LONG LONGBLOB LONGTEXT LOOP
+		LOW_PRIORITY MASTER_BIND MASTER_SSL_VERIFY_SERVER_CERT MATCH MAXVALUE MEDIUMBLOB
+		MEDIUMINT MEDIUMTEXT MIDDLEINT MINUTE_MICROSECOND MINUTE_SECOND MOD MODIFIES NATURAL
+		NOT NO_WRITE_TO_BINLOG NTH_VALUE NTILE NULL NUMERIC OF ON OPTIMIZE OPTIMIZER_COSTS
+		OPTION OPTIONALLY OR ORDER OUT OUTER OUTFILE OVER PARTITION PERCENT_RANK PRECISION
+		PRIMARY PROCEDURE PURGE RANGE RANK READ READS READ_WRITE REAL RECURSIVE REFERENCES
+		REGEXP RELEASE RENAME REPEAT REPLACE REQUIRE RESIGNAL RESTRICT RETURN REVOKE RIGHT
+		RLIKE ROW ROWS ROW_NUMBER SCHEMA SCHEMAS SECOND_MICROSECOND SELECT SENSITIVE
+		SEPARATOR SET SHOW SIGNAL SMALLINT SPATIAL SPECIFIC SQL SQLEXCEPTION SQLSTATE
+		SQLWARNING SQL_BIG_RESULT SQL_CALC_FOUND_ROWS SQL_SMALL_RESULT SSL STARTING STORED
+		STRAIGHT_JOIN SYSTEM TABLE TERMINATED THEN TINYBLOB TINYINT TINYTEXT TO TRAILING
+		TRIGGER TRUE UNDO UNION UNIQUE UNLOCK UNSIGNED UPDATE USAGE USE USING UTC_DATE
+		UTC_TIME UTC_TIMESTAMP VALUES VARBINARY VARCHAR VARCHARACTER VARYING VIRTUAL WHEN
+		WHERE WHILE WINDOW WITH WRITE XOR YEAR_MONTH ZEROFILL
+	`),
+}
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// IsReserved reports whether word is a reserved word of the adapter's dialect,
+// strict visitors reject those as identifiers, see DbDialect.Strict.
+//
+//	IsReserved(MYSQL, "key")    // true
+//	IsReserved(POSTGRES, "key") // false
+func IsReserved(adapter adapter, word string) bool {
+	return reservedWords[adapter.dialect()][strings.ToUpper(word)]
+}
+
+// QuoteIdentifier returns name enclosed in quote,
+// quote characters within name are escaped by doubling them.
+//
+//	QuoteIdentifier(`my "table"`, '"')  // "my ""table"""
+//	QuoteIdentifier("my `table`", '`')  // `my ``table```
+func QuoteIdentifier(name string, quote byte) string {
+	q := string(quote)
+	return q + strings.Replace(name, q, q+q, -1) + q
+}
+
+// checkIdentifier returns an *IdentifierError if name is not usable as identifier.
+// Empty names and names containing a NUL byte are always invalid,
+// strict checks reject reserved words of the adapter's dialect as well, see IsReserved.
+// kind is "table" or "column".
+func checkIdentifier(kind, name string, strict bool, adapter adapter) error {
+	if name == "" || strings.IndexByte(name, 0) >= 0 {
+		return &IdentifierError{Kind: kind, Name: name}
+	}
+
+	if !strict {
+		return nil
+	}
+
+	pattern := VALID_COL_NAME_PATTERN
+	if kind == "table" {
+		pattern = VALID_TABLE_NAME_PATTERN
+	}
+	if !pattern.MatchString(name) {
+		return &IdentifierError{Kind: kind, Name: name}
+	}
+	if IsReserved(adapter, name) {
+		return &IdentifierError{Kind: kind, Name: name, Reserved: true}
+	}
+	return nil
+}
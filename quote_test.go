@@ -0,0 +1,67 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, `"users"`, QuoteIdentifier("users", QUOTE))
+	assert.Equal(t, `"my ""table"""`, QuoteIdentifier(`my "table"`, QUOTE))
+	assert.Equal(t, "`my ``table```", QuoteIdentifier("my `table`", MYSQL_QUOTE))
+	assert.Equal(t, "`my \"table\"`", QuoteIdentifier(`my "table"`, MYSQL_QUOTE))
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	assert.Nil(t, checkIdentifier("column", "1 weird name", false, 0))
+	assert.NotNil(t, checkIdentifier("column", "", false, 0))
+	assert.NotNil(t, checkIdentifier("column", "a\x00b", false, 0))
+
+	assert.Nil(t, checkIdentifier("column", "_name$1", true, 0))
+	assert.NotNil(t, checkIdentifier("column", "1 weird name", true, 0))
+	assert.NotNil(t, checkIdentifier("table", "select", true, 0))
+	assert.Nil(t, checkIdentifier("table", "select", false, 0))
+	assert.NotNil(t, checkIdentifier("column", "key", true, MYSQL))
+	assert.Nil(t, checkIdentifier("column", "key", true, POSTGRES))
+	assert.NotNil(t, checkIdentifier("column", "returning", true, POSTGRES))
+}
+
+func TestIsReserved(t *testing.T) {
+	assert.True(t, IsReserved(MYSQL, "key"))
+	assert.False(t, IsReserved(POSTGRES, "key"))
+	assert.True(t, IsReserved(POSTGRES, "Returning"))
+	assert.False(t, IsReserved(MYSQL, "returning"))
+	assert.True(t, IsReserved(0, "select"))
+	assert.True(t, IsReserved(POSTGRES|strictAdapter, "select"))
+}
+
+func TestDialectStrict(t *testing.T) {
+	mysql := Dialect(MYSQL).Strict()
+	_, _, err := mysql.Table("users").Select("key").ToSql()
+	assert.NotNil(t, err)
+	assert.Equal(t, "reserved word as column name: 'key'", err.Error())
+
+	_, _, err = mysql.Table("users").Select("1 weird").ToSql()
+	assert.Equal(t, "invalid column name: '1 weird'", err.Error())
+
+	// reserved words are dialect specific
+	psql := Dialect(POSTGRES).Strict()
+	sql, args, err := psql.Table("users").Select("key").Where(psql.Table("users").Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."key" FROM "users" WHERE ("users"."id"=$1)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+	_, _, err = psql.Table("users").Select("returning").ToSql()
+	assert.Equal(t, "reserved word as column name: 'returning'", err.Error())
+
+	// strict mode is per dialect
+	sql, _, err = Dialect(MYSQL).Table("users").Select("key").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.`key` FROM `users`", sql)
+
+	// strict dialects parse and interpolate like their plain ones
+	m, err := mysql.ParseSelect("SELECT `users`.`id` FROM `users` WHERE `users`.`name` = 'a' # comment")
+	assert.Nil(t, err)
+	s, err := m.ToDebugSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.`id` FROM `users` WHERE (`users`.`name`='a')", s)
+}
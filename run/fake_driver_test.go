@@ -0,0 +1,134 @@
+package run
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// fakeDriver records the queries and returns the rows of fakeResults.
+type fakeDriver struct {
+	mutex   sync.Mutex
+	queries []fakeQuery
+	results map[string]*fakeResult // by query
+}
+
+type fakeQuery struct {
+	Sql  string
+	Args []interface{}
+}
+
+type fakeResult struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	RowsAffected int64
+	Err          error
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("codexfake", fake)
+}
+
+// reset clears the recorded queries and sets the results.
+func (d *fakeDriver) reset(results map[string]*fakeResult) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.queries = nil
+	d.results = results
+}
+
+func (d *fakeDriver) record(query string, args []driver.NamedValue) (*fakeResult, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	d.queries = append(d.queries, fakeQuery{query, values})
+	result := d.results[query]
+	if result == nil {
+		return &fakeResult{}, nil
+	}
+	return result, result.Err
+}
+
+func (d *fakeDriver) recorded() []fakeQuery {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]fakeQuery(nil), d.queries...)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+var (
+	_ driver.QueryerContext = (*fakeConn)(nil)
+	_ driver.ExecerContext  = (*fakeConn)(nil)
+)
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.driver.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{result: result}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.driver.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(result.RowsAffected), nil
+}
+
+type fakeRows struct {
+	result *fakeResult
+	i      int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.i])
+	r.i++
+	return nil
+}
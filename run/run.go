@@ -0,0 +1,68 @@
+// Package run executes codex managers with database/sql.
+//
+//	users := codex.Dialect(codex.POSTGRES).Table("users")
+//	rows, err := run.QueryContext(ctx, db, users.Select("id", "name").Where(users.Col("active").Eq(true)))
+//	res, err := run.ExecContext(ctx, tx, users.Set("active").To(false).Where(users.Col("id").Eq(7)))
+package run
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Runner is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Runner interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+var (
+	_ Runner = (*sql.DB)(nil)
+	_ Runner = (*sql.Tx)(nil)
+	_ Runner = (*sql.Conn)(nil)
+)
+
+// Statement is satisfied by codex.SelectManager, InsertManager, UpdateManager and DeleteManager.
+type Statement interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// QueryContext renders the statement and runs it as query e.g. a SELECT or INSERT ... RETURNING.
+func QueryContext(ctx context.Context, runner Runner, stmt Statement) (*sql.Rows, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return runner.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext renders the statement and runs it as query returning at most one row.
+// Unlike sql.DB.QueryRowContext rendering errors are returned separately, query errors are deferred
+// to the row's Scan.
+func QueryRowContext(ctx context.Context, runner Runner, stmt Statement) (*sql.Row, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return runner.QueryRowContext(ctx, query, args...), nil
+}
+
+// ScanRowContext runs QueryRowContext and scans the row into dest.
+// It returns sql.ErrNoRows if there is no row.
+func ScanRowContext(ctx context.Context, runner Runner, stmt Statement, dest ...interface{}) error {
+	row, err := QueryRowContext(ctx, runner, stmt)
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest...)
+}
+
+// ExecContext renders the statement and executes it without returning rows e.g. an UPDATE or DELETE.
+func ExecContext(ctx context.Context, runner Runner, stmt Statement) (sql.Result, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return runner.ExecContext(ctx, query, args...)
+}
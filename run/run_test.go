@@ -0,0 +1,158 @@
+package run
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/janmentzel/codex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func openFake(t *testing.T, results map[string]*fakeResult) *sql.DB {
+	fake.reset(results)
+	db, err := sql.Open("codexfake", "")
+	assert.Nil(t, err)
+	return db
+}
+
+func TestQueryContext(t *testing.T) {
+	users := codex.Dialect(codex.POSTGRES).Table("users")
+	m := users.Select("id", "name").Where(users.Col("active").Eq(true))
+	query, _, _ := m.ToSql()
+
+	db := openFake(t, map[string]*fakeResult{query: {
+		Columns: []string{"id", "name"},
+		Rows:    [][]driver.Value{{int64(1), "Jon"}, {int64(2), "Ann"}},
+	}})
+	defer db.Close()
+
+	rows, err := QueryContext(context.Background(), db, m)
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var id int64
+		var name string
+		assert.Nil(t, rows.Scan(&id, &name))
+		names = append(names, name)
+	}
+	assert.Nil(t, rows.Err())
+	assert.Equal(t, []string{"Jon", "Ann"}, names)
+	assert.Equal(t, []fakeQuery{{`SELECT "users"."id","users"."name" FROM "users" WHERE ("users"."active"=$1)`, []interface{}{true}}}, fake.recorded())
+}
+
+func TestQueryRowContext(t *testing.T) {
+	users := codex.Table("users")
+	m := users.Insert("Jon").Into("name").Returning("id")
+	query, _, _ := m.ToSql()
+
+	db := openFake(t, map[string]*fakeResult{query: {
+		Columns: []string{"id"},
+		Rows:    [][]driver.Value{{int64(42)}},
+	}})
+	defer db.Close()
+
+	row, err := QueryRowContext(context.Background(), db, m)
+	assert.Nil(t, err)
+	var id int64
+	assert.Nil(t, row.Scan(&id))
+	assert.Equal(t, int64(42), id)
+
+	id = 0
+	assert.Nil(t, ScanRowContext(context.Background(), db, m, &id))
+	assert.Equal(t, int64(42), id)
+	assert.Equal(t, []interface{}{"Jon"}, fake.recorded()[0].Args)
+}
+
+func TestScanRowContextNoRows(t *testing.T) {
+	db := openFake(t, nil)
+	defer db.Close()
+
+	var id int64
+	users := codex.Table("users")
+	err := ScanRowContext(context.Background(), db, users.Select("id").Where(users.Col("id").Eq(1)), &id)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestExecContext(t *testing.T) {
+	users := codex.Dialect(codex.MYSQL).Table("users")
+	update := users.Set("active").To(false).Where(users.Col("id").Eq(7))
+	remove := users.Delete(users.Col("id").Eq(8))
+	query, _, _ := update.ToSql()
+
+	db := openFake(t, map[string]*fakeResult{query: {RowsAffected: 1}})
+	defer db.Close()
+
+	res, err := ExecContext(context.Background(), db, update)
+	assert.Nil(t, err)
+	n, err := res.RowsAffected()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	res, err = ExecContext(context.Background(), db, remove)
+	assert.Nil(t, err)
+	n, _ = res.RowsAffected()
+	assert.Equal(t, int64(0), n)
+
+	assert.Equal(t, []fakeQuery{
+		{"UPDATE `users` SET `active`=? WHERE (`users`.`id`=?)", []interface{}{false, int64(7)}},
+		{"DELETE FROM `users` WHERE (`users`.`id`=?)", []interface{}{int64(8)}},
+	}, fake.recorded())
+}
+
+func TestTxAndConn(t *testing.T) {
+	db := openFake(t, nil)
+	defer db.Close()
+	ctx := context.Background()
+	users := codex.Table("users")
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.Nil(t, err)
+	_, err = ExecContext(ctx, tx, users.Delete(users.Col("id").Eq(1)))
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit())
+
+	conn, err := db.Conn(ctx)
+	assert.Nil(t, err)
+	defer conn.Close()
+	_, err = ExecContext(ctx, conn, users.Delete(users.Col("id").Eq(2)))
+	assert.Nil(t, err)
+
+	assert.Len(t, fake.recorded(), 2)
+}
+
+func TestRenderErrorIsNotExecuted(t *testing.T) {
+	db := openFake(t, nil)
+	defer db.Close()
+	ctx := context.Background()
+	users := codex.Table("users")
+	broken := users.InnerJoin(1)
+
+	_, err := QueryContext(ctx, db, broken)
+	assert.True(t, errors.Is(err, codex.ErrUnexpectedType))
+	_, err = QueryRowContext(ctx, db, broken)
+	assert.True(t, errors.Is(err, codex.ErrUnexpectedType))
+	err = ScanRowContext(ctx, db, broken)
+	assert.True(t, errors.Is(err, codex.ErrUnexpectedType))
+	_, err = ExecContext(ctx, db, users.Set("a").To(1, 2))
+	assert.True(t, errors.Is(err, codex.ErrArgumentCount))
+
+	assert.Empty(t, fake.recorded())
+}
+
+func TestQueryError(t *testing.T) {
+	users := codex.Table("users")
+	m := users.Select("id")
+	query, _, _ := m.ToSql()
+	queryErr := errors.New("relation does not exist")
+	db := openFake(t, map[string]*fakeResult{query: {Err: queryErr}})
+	defer db.Close()
+
+	_, err := QueryContext(context.Background(), db, m)
+	assert.Equal(t, queryErr, err)
+	_, err = ExecContext(context.Background(), db, m)
+	assert.Equal(t, queryErr, err)
+}
@@ -0,0 +1,226 @@
+package run
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/janmentzel/codex"
+)
+
+// ErrUnmappedColumn is returned by strict Scanners for result columns without struct field.
+var ErrUnmappedColumn = errors.New("unmapped column")
+
+// Scanner maps result columns to struct fields by their `db:"name"` tags,
+// untagged fields by their lower case name. Fields tagged `db:"-"` are ignored.
+// Fields of embedded structs are mapped unless shadowed, see codex.StructFields.
+// Pointer and sql.Null* fields receive NULLs.
+//
+//	type User struct {
+//		Id    int64
+//		Name  string         `db:"name"`
+//		Email sql.NullString `db:"email"`
+//		Timestamps            // embedded, maps created_at and updated_at
+//	}
+//
+//	var users []User
+//	err := run.ScanAll(ctx, db, usersTable.Select("id", "name", "email", "created_at", "updated_at"), &users)
+type Scanner struct {
+	Strict bool // result columns without field are an error, otherwise they are ignored
+}
+
+// ScanAll runs the query of the statement and scans all rows into dest,
+// a pointer to a slice of structs, struct pointers or, for a single column, scalars.
+// The slice is reset before.
+func ScanAll(ctx context.Context, runner Runner, stmt Statement, dest interface{}) error {
+	return Scanner{}.ScanAll(ctx, runner, stmt, dest)
+}
+
+// ScanOne runs the query of the statement and scans the first row into dest,
+// a pointer to a struct or, for a single column, a scalar.
+// It returns sql.ErrNoRows if there is no row.
+func ScanOne(ctx context.Context, runner Runner, stmt Statement, dest interface{}) error {
+	return Scanner{}.ScanOne(ctx, runner, stmt, dest)
+}
+
+// ScanMap runs the query of the statement and returns the rows as maps by column name.
+func ScanMap(ctx context.Context, runner Runner, stmt Statement) ([]map[string]interface{}, error) {
+	return Scanner{}.ScanMap(ctx, runner, stmt)
+}
+
+// ScanAll see package func ScanAll.
+func (s Scanner) ScanAll(ctx context.Context, runner Runner, stmt Statement, dest interface{}) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("run.ScanAll() expected a pointer to a slice but got %T", dest)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	rows, err := QueryContext(ctx, runner, stmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	slice.Set(slice.Slice(0, 0))
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		targets, err := s.targets(elem.Elem(), columns)
+		if err != nil {
+			return err
+		}
+		if err = rows.Scan(targets...); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
+
+// ScanOne see package func ScanOne.
+func (s Scanner) ScanOne(ctx context.Context, runner Runner, stmt Statement, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("run.ScanOne() expected a pointer but got %T", dest)
+	}
+
+	rows, err := QueryContext(ctx, runner, stmt)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	targets, err := s.targets(v.Elem(), columns)
+	if err != nil {
+		return err
+	}
+	if err = rows.Scan(targets...); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// ScanMap see package func ScanMap.
+func (s Scanner) ScanMap(ctx context.Context, runner Runner, stmt Statement) ([]map[string]interface{}, error) {
+	rows, err := QueryContext(ctx, runner, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err = rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// targets returns the scan destinations of the columns within v.
+func (s Scanner) targets(v reflect.Value, columns []string) ([]interface{}, error) {
+	if !isStruct(v.Type()) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("run: scanning %d columns into %s requires a struct", len(columns), v.Type())
+		}
+		return []interface{}{v.Addr().Interface()}, nil
+	}
+
+	fields := fieldsOf(v.Type())
+	targets := make([]interface{}, len(columns))
+	for i, column := range columns {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			if s.Strict {
+				return nil, fmt.Errorf("%w: %s has no field for column '%s'", ErrUnmappedColumn, v.Type(), column)
+			}
+			targets[i] = new(interface{})
+			continue
+		}
+		targets[i] = fieldByIndex(v, index).Addr().Interface()
+	}
+	return targets, nil
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// isStruct is true for structs mapped to columns, false for e.g. time.Time and sql.NullString.
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(scannerType) && t.NumField() > 0 && t.PkgPath() != "time"
+}
+
+var fieldsCache sync.Map // reflect.Type -> map[string][]int
+
+// fieldsOf returns the field indexes of struct type t by lower case column name, see codex.StructFields.
+func fieldsOf(t reflect.Type) map[string][]int {
+	if fields, ok := fieldsCache.Load(t); ok {
+		return fields.(map[string][]int)
+	}
+	fields := map[string][]int{}
+	for _, f := range codex.StructFields(t) {
+		name := strings.ToLower(f.Column)
+		if _, ok := fields[name]; !ok {
+			fields[name] = f.Index
+		}
+	}
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+// fieldByIndex returns the nested field of v allocating nil embedded struct pointers.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
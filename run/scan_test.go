@@ -0,0 +1,168 @@
+package run
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/janmentzel/codex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type scanTimestamps struct {
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// ScanAudit is exported, embedded pointers to unexported structs are not mapped
+type ScanAudit struct {
+	Note string `db:"note"`
+	Id   int64  // shadowed by scanUser.Id
+}
+
+type scanUser struct {
+	Id    int64
+	Name  string         `db:"name"`
+	Email sql.NullString `db:"email"`
+	Age   *int           `db:"age"`
+	Hash  string         `db:"-"`
+	scanTimestamps
+	*ScanAudit
+}
+
+func scanFixture(t *testing.T) (*sql.DB, *codex.SelectManager) {
+	users := codex.Table("users")
+	m := users.Select("id", "name", "email", "age", "created_at", "updated_at", "note")
+	query, _, _ := m.ToSql()
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db := openFake(t, map[string]*fakeResult{query: {
+		Columns: []string{"id", "name", "email", "age", "created_at", "updated_at", "note"},
+		Rows: [][]driver.Value{
+			{int64(1), "Jon", "jon@example.com", int64(42), created, created, "first"},
+			{int64(2), "Ann", nil, nil, created, nil, ""},
+		},
+	}})
+	return db, m
+}
+
+func TestScanAll(t *testing.T) {
+	db, m := scanFixture(t)
+	defer db.Close()
+
+	users := []scanUser{{Name: "stale"}}
+	assert.Nil(t, ScanAll(context.Background(), db, m, &users))
+	assert.Len(t, users, 2)
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	jon, ann := users[0], users[1]
+	assert.Equal(t, int64(1), jon.Id)
+	assert.Equal(t, "Jon", jon.Name)
+	assert.Equal(t, sql.NullString{String: "jon@example.com", Valid: true}, jon.Email)
+	assert.Equal(t, 42, *jon.Age)
+	assert.Equal(t, created, jon.CreatedAt)
+	assert.Equal(t, created, *jon.UpdatedAt)
+	assert.Equal(t, &ScanAudit{Note: "first"}, jon.ScanAudit)
+
+	assert.Equal(t, int64(2), ann.Id)
+	assert.False(t, ann.Email.Valid)
+	assert.Nil(t, ann.Age)
+	assert.Nil(t, ann.UpdatedAt)
+	assert.Equal(t, &ScanAudit{}, ann.ScanAudit)
+}
+
+func TestScanAllPointersAndScalars(t *testing.T) {
+	db, m := scanFixture(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var users []*scanUser
+	assert.Nil(t, ScanAll(ctx, db, m, &users))
+	assert.Len(t, users, 2)
+	assert.Equal(t, "Ann", users[1].Name)
+
+	var ns []string
+	assert.NotNil(t, ScanAll(ctx, db, m, &ns))
+	assert.NotNil(t, ScanAll(ctx, db, m, ns))
+
+	names := codex.Table("users").Select("name")
+	query, _, _ := names.ToSql()
+	fake.reset(map[string]*fakeResult{query: {Columns: []string{"name"}, Rows: [][]driver.Value{{"Jon"}, {"Ann"}}}})
+	assert.Nil(t, ScanAll(ctx, db, names, &ns))
+	assert.Equal(t, []string{"Jon", "Ann"}, ns)
+}
+
+func TestScanOne(t *testing.T) {
+	db, m := scanFixture(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var user scanUser
+	assert.Nil(t, ScanOne(ctx, db, m, &user))
+	assert.Equal(t, "Jon", user.Name)
+
+	users := codex.Table("users")
+	var id int64
+	err := ScanOne(ctx, db, users.Select("id").Where(users.Col("id").Eq(3)), &id)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NotNil(t, ScanOne(ctx, db, m, user))
+}
+
+func TestScanStrict(t *testing.T) {
+	db, m := scanFixture(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	type partial struct {
+		Id   int64
+		Name string `db:"name"`
+	}
+	var rows []partial
+	assert.Nil(t, ScanAll(ctx, db, m, &rows))
+	assert.Equal(t, []partial{{1, "Jon"}, {2, "Ann"}}, rows)
+
+	err := Scanner{Strict: true}.ScanAll(ctx, db, m, &rows)
+	assert.True(t, errors.Is(err, ErrUnmappedColumn))
+	assert.Contains(t, err.Error(), "'email'")
+
+	var row partial
+	err = Scanner{Strict: true}.ScanOne(ctx, db, m, &row)
+	assert.True(t, errors.Is(err, ErrUnmappedColumn))
+
+	var user scanUser
+	assert.Nil(t, Scanner{Strict: true}.ScanOne(ctx, db, m, &user))
+}
+
+func TestScanMap(t *testing.T) {
+	db, m := scanFixture(t)
+	defer db.Close()
+
+	rows, err := ScanMap(context.Background(), db, m)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, int64(1), rows[0]["id"])
+	assert.Equal(t, "Jon", rows[0]["name"])
+	assert.Nil(t, rows[1]["email"])
+
+	users := codex.Table("users")
+	rows, err = ScanMap(context.Background(), db, users.Select("id").Where(users.Col("id").Eq(3)))
+	assert.Nil(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestScanRenderError(t *testing.T) {
+	db := openFake(t, nil)
+	defer db.Close()
+	ctx := context.Background()
+	broken := codex.Table("users").InnerJoin(1)
+
+	var users []scanUser
+	assert.True(t, errors.Is(ScanAll(ctx, db, broken, &users), codex.ErrUnexpectedType))
+	var user scanUser
+	assert.True(t, errors.Is(ScanOne(ctx, db, broken, &user), codex.ErrUnexpectedType))
+	_, err := ScanMap(ctx, db, broken)
+	assert.True(t, errors.Is(err, codex.ErrUnexpectedType))
+	assert.Empty(t, fake.recorded())
+}
@@ -0,0 +1,490 @@
+package codex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Statement is a DDL statement of a migration, see Diff.
+type Statement struct {
+	Sql         string
+	Description string // e.g. "drop column users.legacy"
+	Destructive bool   // data may be lost: drops a table or column or changes the type of a column
+}
+
+// Diff returns the DDL statements migrating the schema old to new, rendered in the dialect of the tables.
+// Statements are ordered: tables are created referenced ones first, then indexes and constraints of
+// altered tables are dropped and their columns added, altered and dropped, then constraints and indexes
+// are added, finally tables are dropped referencing ones first.
+// Foreign keys of created tables referencing columns added by the migration are added after the columns,
+// dropped tables referencing dropped columns are dropped before the columns.
+// Foreign keys closing a cycle between created tables are added after the tables, the ones closing a cycle
+// between dropped tables are dropped first, which requires named table constraints.
+//
+// Tables, columns and indexes are matched by name, constraints by name or, if unnamed, by their SQL.
+// A renamed table or column is dropped and created again. PrimaryKey, Unique and References added to
+// existing columns are added as constraints, removing them or changing Identity returns an ErrInvalidSchema
+// error since the constraints are unnamed, use named table constraints to migrate them.
+//
+//	up, err := Diff(current, next)
+//	down, err := Diff(next, current)
+func Diff(old, new *Definitions) ([]Statement, error) {
+	d := &differ{old: old, new: new}
+
+	var created, dropped []*TableDef
+	for _, def := range new.order {
+		if _, ok := old.Lookup(def.Table); !ok {
+			created = append(created, def)
+		}
+	}
+	for _, def := range old.order {
+		if _, ok := new.Lookup(def.Table); !ok {
+			dropped = append(dropped, def)
+		}
+	}
+
+	var deferred []*ForeignKeyNode // of created tables referencing added columns or closing a cycle
+	var deferredDefs []*TableDef
+	created, cyclic := sortByReferences(created)
+	for _, def := range created {
+		tree := &CreateTableStatementNode{Table: def.Table, Columns: def.Columns, Constraints: def.Constraints}
+		var fks []*ForeignKeyNode
+		tree.Columns, tree.Constraints, fks = d.deferForeignKeys(def, cyclic[def])
+		d.add(fmt.Sprintf("create table %s", tableKey(def.Table)), false, def.Table.Adapter, tree)
+		for _, index := range def.Indexes {
+			d.createIndex(def, index)
+		}
+		for _, fk := range fks {
+			deferred = append(deferred, fk)
+			deferredDefs = append(deferredDefs, def)
+		}
+	}
+
+	dropped, cyclic = sortByReferences(dropped)
+	for _, def := range dropped {
+		d.dropCyclicForeignKeys(def, cyclic[def])
+	}
+
+	// dropped tables referencing dropped columns are dropped before the columns
+	early := map[*TableDef]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, def := range dropped {
+			if !early[def] && d.referencesDropped(def, early) {
+				early[def], changed = true, true
+			}
+		}
+	}
+	for i := len(dropped) - 1; i >= 0; i-- {
+		if def := dropped[i]; early[def] {
+			d.add(fmt.Sprintf("drop table %s", tableKey(def.Table)), true, def.Table.Adapter, DropTableStatement(def.Table))
+		}
+	}
+
+	var altered [][2]*TableDef
+	for _, def := range new.order {
+		if from, ok := old.Lookup(def.Table); ok {
+			altered = append(altered, [2]*TableDef{from, def})
+		}
+	}
+	adds := make([]func(), len(altered))
+	for i, defs := range altered {
+		adds[i] = d.alterTable(defs[0], defs[1])
+	}
+	for _, add := range adds {
+		add()
+	}
+	for i, fk := range deferred {
+		def := deferredDefs[i]
+		d.alter(def, fmt.Sprintf("add foreign key of %s", tableKey(def.Table)), false, &AddConstraintNode{fk})
+	}
+
+	for i := len(dropped) - 1; i >= 0; i-- {
+		if def := dropped[i]; !early[def] {
+			d.add(fmt.Sprintf("drop table %s", tableKey(def.Table)), true, def.Table.Adapter, DropTableStatement(def.Table))
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.statements, nil
+}
+
+// differ collects the statements of Diff, the first error is kept.
+type differ struct {
+	old, new   *Definitions
+	statements []Statement
+	err        error
+}
+
+// deferForeignKeys returns the columns and constraints of the created table def without the foreign keys
+// referencing columns added to existing tables or closing a cycle i.e. referencing a table in cyclic,
+// those are returned as constraints to be added later.
+func (d *differ) deferForeignKeys(def *TableDef, cyclic map[string]bool) (columns []*ColumnDef, constraints []interface{}, deferred []*ForeignKeyNode) {
+	for _, column := range def.Columns {
+		if ref := column.References; ref != nil && (cyclic[ref.Table] || newColumn(d.old, d.new, referencedTable(ref), ref.Column)) {
+			stripped := *column
+			stripped.References = nil
+			column = &stripped
+			deferred = append(deferred, columnForeignKey(column.Name, ref))
+		}
+		columns = append(columns, column)
+	}
+	for _, constraint := range def.Constraints {
+		if fk, ok := constraint.(*ForeignKeyNode); ok && fk.Table != nil && (cyclic[tableKey(fk.Table)] || newColumns(d.old, d.new, fk.Table, fk.RefColumns)) {
+			deferred = append(deferred, fk)
+			continue
+		}
+		constraints = append(constraints, constraint)
+	}
+	return
+}
+
+// dropCyclicForeignKeys drops the foreign keys of the dropped table def closing a cycle i.e. referencing
+// a table in cyclic. Unnamed ones fail, they can not be dropped by name.
+func (d *differ) dropCyclicForeignKeys(def *TableDef, cyclic map[string]bool) {
+	table := tableKey(def.Table)
+	for _, fk := range foreignKeys(def) {
+		if !cyclic[tableKey(fk.Table)] {
+			continue
+		}
+		if fk.Name == "" {
+			d.fail("%w: unnamed foreign key of '%s' referencing '%s' closes a cycle and can not be dropped", ErrInvalidSchema, table, tableKey(fk.Table))
+			return
+		}
+		d.alter(def, fmt.Sprintf("drop constraint %s of %s", fk.Name, table), false, &DropConstraintNode{fk.Name})
+	}
+}
+
+// referencesDropped reports whether the dropped table def references a column dropped from an existing table
+// or one of the tables dropped early.
+func (d *differ) referencesDropped(def *TableDef, early map[*TableDef]bool) bool {
+	for _, fk := range foreignKeys(def) {
+		if newColumns(d.new, d.old, fk.Table, fk.RefColumns) {
+			return true
+		}
+		if ref, ok := d.old.Lookup(fk.Table); ok && early[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+// newColumn reports whether column of table is defined in the schema to but not in from,
+// the table being defined in both.
+func newColumn(from, to *Definitions, table *TableNode, column string) bool {
+	a, ok := from.Lookup(table)
+	b, ok2 := to.Lookup(table)
+	return ok && ok2 && column != "" && a.Column(column) == nil && b.Column(column) != nil
+}
+
+func newColumns(from, to *Definitions, table *TableNode, columns []string) bool {
+	for _, column := range columns {
+		if newColumn(from, to, table, column) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) add(description string, destructive bool, adapter adapter, tree interface{}) {
+	if d.err != nil {
+		return
+	}
+	sql, _, err := renderDdl(nil, "", nil, adapter, nil, tree)
+	if err != nil {
+		d.err = fmt.Errorf("%s: %w", description, err)
+		return
+	}
+	d.statements = append(d.statements, Statement{Sql: sql, Description: description, Destructive: destructive})
+}
+
+func (d *differ) alter(def *TableDef, description string, destructive bool, action interface{}) {
+	tree := AlterTableStatement(def.Table)
+	tree.Actions = []interface{}{action}
+	d.add(description, destructive, def.Table.Adapter, tree)
+}
+
+func (d *differ) createIndex(def *TableDef, index *IndexDef) {
+	description := "create index on " + tableKey(def.Table)
+	if index.Name != "" {
+		description = fmt.Sprintf("create index %s on %s", index.Name, tableKey(def.Table))
+	}
+	d.add(description, false, def.Table.Adapter, indexStatement(def.Table, index))
+}
+
+// alterTable adds the statements altering the table from to, except for the constraints and indexes to add,
+// those are added by the returned func after all tables are altered.
+func (d *differ) alterTable(from, to *TableDef) func() {
+	table := tableKey(to.Table)
+	adapter := to.Table.Adapter
+	none := func() {}
+
+	// indexes
+	var addIndexes []*IndexDef
+	for _, index := range from.Indexes {
+		if index.Name == "" {
+			d.fail("%w: index without name on '%s'", ErrInvalidSchema, table)
+			return none
+		}
+		if other := lookupIndex(to, index.Name); other == nil || !d.sameSql(adapter, indexStatement(from.Table, index), indexStatement(to.Table, other)) {
+			d.add(fmt.Sprintf("drop index %s on %s", index.Name, table), false, adapter, DropIndexStatement(index.Name, to.Table))
+		}
+	}
+	for _, index := range to.Indexes {
+		if other := lookupIndex(from, index.Name); other == nil || !d.sameSql(adapter, indexStatement(from.Table, other), indexStatement(to.Table, index)) {
+			addIndexes = append(addIndexes, index)
+		}
+	}
+
+	// constraints
+	var addConstraints []interface{}
+	for _, constraint := range from.Constraints {
+		if other := d.lookupConstraint(adapter, to, constraint); other == nil {
+			name := constraintName(constraint)
+			if name == "" {
+				d.fail("%w: unnamed constraint of '%s' can not be dropped", ErrInvalidSchema, table)
+				return none
+			}
+			d.alter(to, fmt.Sprintf("drop constraint %s of %s", name, table), false, &DropConstraintNode{name})
+		}
+	}
+	for _, constraint := range to.Constraints {
+		if other := d.lookupConstraint(adapter, from, constraint); other == nil {
+			addConstraints = append(addConstraints, constraint)
+		}
+	}
+	addConstraints = append(addConstraints, d.columnConstraints(from, to)...)
+
+	// columns
+	for _, column := range to.Columns {
+		if from.Column(column.Name) == nil {
+			d.alter(to, fmt.Sprintf("add column %s.%s", table, column.Name), false, &AddColumnNode{column})
+		}
+	}
+	for _, column := range to.Columns {
+		old := from.Column(column.Name)
+		if old == nil {
+			continue
+		}
+		typeChanged := normalizeType(old.Type) != normalizeType(column.Type)
+		if typeChanged || old.Nullable != column.Nullable {
+			d.alter(to, fmt.Sprintf("alter column %s.%s", table, column.Name), typeChanged, &AlterColumnNode{Column: column, From: old})
+		} else if !column.Identity && !reflect.DeepEqual(old.Default, column.Default) {
+			d.alter(to, fmt.Sprintf("alter default of column %s.%s", table, column.Name), false, &ColumnDefaultNode{column.Name, column.Default})
+		}
+	}
+	for _, column := range from.Columns {
+		if to.Column(column.Name) == nil {
+			d.alter(to, fmt.Sprintf("drop column %s.%s", table, column.Name), true, &DropColumnNode{column.Name})
+		}
+	}
+
+	return func() {
+		for _, constraint := range addConstraints {
+			description := "add constraint of " + table
+			if name := constraintName(constraint); name != "" {
+				description = fmt.Sprintf("add constraint %s of %s", name, table)
+			}
+			d.alter(to, description, false, &AddConstraintNode{constraint})
+		}
+		for _, index := range addIndexes {
+			d.createIndex(to, index)
+		}
+	}
+}
+
+// columnConstraints returns the constraints added by PrimaryKey, Unique and References of the columns
+// defined in from and to. Removed or changed ones and changes of Identity fail, they can not be dropped by name.
+func (d *differ) columnConstraints(from, to *TableDef) (constraints []interface{}) {
+	table := tableKey(to.Table)
+	var primaryKey []string
+	for _, column := range to.Columns {
+		old := from.Column(column.Name)
+		if old == nil {
+			continue
+		}
+		key := table + "." + column.Name
+		switch {
+		case old.Identity != column.Identity:
+			d.fail("%w: identity of column '%s' can not be changed", ErrInvalidSchema, key)
+		case old.PrimaryKey && !column.PrimaryKey:
+			d.fail("%w: unnamed primary key of column '%s' can not be dropped", ErrInvalidSchema, key)
+		case old.Unique && !column.Unique:
+			d.fail("%w: unnamed unique constraint of column '%s' can not be dropped", ErrInvalidSchema, key)
+		case old.References != nil && !reflect.DeepEqual(old.References, column.References):
+			d.fail("%w: unnamed foreign key of column '%s' can not be dropped", ErrInvalidSchema, key)
+		}
+		if column.PrimaryKey && !old.PrimaryKey {
+			primaryKey = append(primaryKey, column.Name)
+		}
+		if column.Unique && !old.Unique {
+			constraints = append(constraints, Unique(column.Name))
+		}
+		if column.References != nil && old.References == nil {
+			constraints = append(constraints, columnForeignKey(column.Name, column.References))
+		}
+	}
+	if 0 < len(primaryKey) {
+		if 0 < len(from.PrimaryKey()) {
+			d.fail("%w: primary key of '%s' can not be changed", ErrInvalidSchema, table)
+		}
+		constraints = append([]interface{}{PrimaryKey(primaryKey...)}, constraints...)
+	}
+	return
+}
+
+func (d *differ) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf(format, args...)
+	}
+}
+
+// sameSql reports whether both trees render the same SQL.
+func (d *differ) sameSql(adapter adapter, a, b interface{}) bool {
+	sqlA, _, errA := renderDdl(nil, "", nil, adapter, nil, a)
+	sqlB, _, errB := renderDdl(nil, "", nil, adapter, nil, b)
+	if errA != nil {
+		d.fail("%w", errA)
+		return false
+	}
+	if errB != nil {
+		d.fail("%w", errB)
+		return false
+	}
+	return sqlA == sqlB
+}
+
+// lookupConstraint returns the constraint of def with the name of constraint
+// or, if unnamed, the one with the same SQL. nil if there is none or it differs.
+func (d *differ) lookupConstraint(adapter adapter, def *TableDef, constraint interface{}) interface{} {
+	name := constraintName(constraint)
+	for _, other := range def.Constraints {
+		if constraintName(other) != name {
+			continue
+		}
+		if d.sameSql(adapter, constraint, other) {
+			return other
+		}
+		if name != "" {
+			return nil
+		}
+	}
+	return nil
+}
+
+// constraintName returns the name of a constraint node, empty if unnamed.
+func constraintName(constraint interface{}) string {
+	switch c := constraint.(type) {
+	case *PrimaryKeyNode:
+		return c.Name
+	case *UniqueNode:
+		return c.Name
+	case *ForeignKeyNode:
+		return c.Name
+	case *CheckNode:
+		return c.Name
+	}
+	return ""
+}
+
+func lookupIndex(def *TableDef, name string) *IndexDef {
+	for _, index := range def.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// indexStatement returns the CREATE INDEX tree of the index.
+func indexStatement(table *TableNode, index *IndexDef) *CreateIndexStatementNode {
+	tree := CreateIndexStatement(index.Name, table)
+	tree.Unique = index.Unique
+	tree.Using = index.Using
+	tree.Columns = index.Columns
+	if index.Where != nil {
+		tree.Wheres = []interface{}{whereExpr(index.Where)}
+	}
+	return tree
+}
+
+// normalizeType returns the SQL type lower case with single spaces for comparison.
+func normalizeType(t string) string {
+	return strings.ToLower(strings.Join(strings.Fields(t), " "))
+}
+
+// sortByReferences returns the tables ordered so that referenced tables precede the ones referencing them.
+// Tables outside defs and references of a table to itself are ignored. References closing a cycle
+// can not be ordered, they are returned as the keys of the referenced tables per referencing table.
+func sortByReferences(defs []*TableDef) (sorted []*TableDef, cyclic map[*TableDef]map[string]bool) {
+	byKey := make(map[string]*TableDef, len(defs))
+	for _, def := range defs {
+		byKey[tableKey(def.Table)] = def
+	}
+
+	sorted = make([]*TableDef, 0, len(defs))
+	cyclic = map[*TableDef]map[string]bool{}
+	visited := map[*TableDef]bool{}
+	visiting := map[*TableDef]bool{} // on the path of the current visit
+	var visit func(def *TableDef)
+	visit = func(def *TableDef) {
+		if visited[def] {
+			return
+		}
+		visited[def], visiting[def] = true, true
+		for _, key := range referencedTables(def) {
+			ref, ok := byKey[key]
+			switch {
+			case !ok || ref == def:
+			case visiting[ref]:
+				if cyclic[def] == nil {
+					cyclic[def] = map[string]bool{}
+				}
+				cyclic[def][key] = true
+			default:
+				visit(ref)
+			}
+		}
+		visiting[def] = false
+		sorted = append(sorted, def)
+	}
+	for _, def := range defs {
+		visit(def)
+	}
+	return
+}
+
+// referencedTables returns the (schema qualified) names of the tables def references.
+func referencedTables(def *TableDef) (keys []string) {
+	for _, fk := range foreignKeys(def) {
+		keys = append(keys, tableKey(fk.Table))
+	}
+	return
+}
+
+// foreignKeys returns the foreign keys of the columns and constraints of def.
+func foreignKeys(def *TableDef) (fks []*ForeignKeyNode) {
+	for _, column := range def.Columns {
+		if column.References != nil {
+			fks = append(fks, columnForeignKey(column.Name, column.References))
+		}
+	}
+	for _, constraint := range def.Constraints {
+		if fk, ok := constraint.(*ForeignKeyNode); ok && fk.Table != nil {
+			fks = append(fks, fk)
+		}
+	}
+	return
+}
+
+// columnForeignKey returns the FOREIGN KEY constraint of the column referencing ref.
+func columnForeignKey(column string, ref *ForeignKey) *ForeignKeyNode {
+	fk := Foreign(column).References(referencedTable(ref)).OnDelete(ref.OnDelete)
+	if ref.Column != "" {
+		fk.RefColumns = []string{ref.Column}
+	}
+	return fk
+}
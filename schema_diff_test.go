@@ -0,0 +1,270 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func diffSchemas() (old, new *Definitions) {
+	psql := Dialect(POSTGRES)
+
+	old = NewDefinitions()
+	old.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "varchar(100)"},
+		&ColumnDef{Name: "legacy", Type: "text", Nullable: true},
+		&ColumnDef{Name: "active", Type: "boolean", Default: true},
+	).Indexes = []*IndexDef{{Name: "users_email_idx", Columns: []interface{}{"email"}}}
+	old.Define(psql.Table("sessions"),
+		&ColumnDef{Name: "id", Type: "uuid", PrimaryKey: true},
+		&ColumnDef{Name: "user_id", Type: "bigint", References: &ForeignKey{Table: "users", Column: "id"}},
+	)
+
+	new = NewDefinitions()
+	users := new.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "varchar(255)"},
+		&ColumnDef{Name: "nick", Type: "text", Nullable: true},
+		&ColumnDef{Name: "active", Type: "BOOLEAN", Default: false},
+	)
+	users.Constraints = []interface{}{Check("length(nick) > 2").Named("users_nick_check")}
+	users.Indexes = []*IndexDef{{Name: "users_email_idx", Columns: []interface{}{"email"}, Unique: true}}
+	// defined before the referenced table
+	new.Define(psql.Table("comments"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "post_id", Type: "bigint", References: &ForeignKey{Table: "posts", Column: "id", OnDelete: "CASCADE"}},
+	)
+	new.Define(psql.Table("posts"),
+		&ColumnDef{Name: "id", Type: "bigserial", PrimaryKey: true},
+		&ColumnDef{Name: "user_id", Type: "bigint", References: &ForeignKey{Table: "users", Column: "id"}},
+		&ColumnDef{Name: "title", Type: "text"},
+	).Indexes = []*IndexDef{{Name: "posts_title_idx", Columns: []interface{}{"title"}, Where: "title <> ''"}}
+	return
+}
+
+func TestDiff(t *testing.T) {
+	old, new := diffSchemas()
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Statement{
+		{Sql: `CREATE TABLE "posts" ("id" bigserial NOT NULL PRIMARY KEY,"user_id" bigint NOT NULL REFERENCES "users" ("id"),"title" text NOT NULL)`, Description: "create table posts"},
+		{Sql: `CREATE INDEX "posts_title_idx" ON "posts" ("title") WHERE (title <> '')`, Description: "create index posts_title_idx on posts"},
+		{Sql: `CREATE TABLE "comments" ("id" bigserial NOT NULL PRIMARY KEY,"post_id" bigint NOT NULL REFERENCES "posts" ("id") ON DELETE CASCADE)`, Description: "create table comments"},
+		{Sql: `DROP INDEX "users_email_idx"`, Description: "drop index users_email_idx on users"},
+		{Sql: `ALTER TABLE "users" ADD COLUMN "nick" text`, Description: "add column users.nick"},
+		{Sql: `ALTER TABLE "users" ALTER COLUMN "email" TYPE varchar(255)`, Description: "alter column users.email", Destructive: true},
+		{Sql: `ALTER TABLE "users" ALTER COLUMN "active" SET DEFAULT FALSE`, Description: "alter default of column users.active"},
+		{Sql: `ALTER TABLE "users" DROP COLUMN "legacy"`, Description: "drop column users.legacy", Destructive: true},
+		{Sql: `ALTER TABLE "users" ADD CONSTRAINT "users_nick_check" CHECK (length(nick) > 2)`, Description: "add constraint users_nick_check of users"},
+		{Sql: `CREATE UNIQUE INDEX "users_email_idx" ON "users" ("email")`, Description: "create index users_email_idx on users"},
+		{Sql: `DROP TABLE "sessions"`, Description: "drop table sessions", Destructive: true},
+	}, statements)
+}
+
+func TestDiffDown(t *testing.T) {
+	old, new := diffSchemas()
+	statements, err := Diff(new, old)
+	assert.Nil(t, err)
+
+	var sqls []string
+	for _, s := range statements {
+		sqls = append(sqls, s.Sql)
+	}
+	assert.Equal(t, []string{
+		`CREATE TABLE "sessions" ("id" uuid NOT NULL PRIMARY KEY,"user_id" bigint NOT NULL REFERENCES "users" ("id"))`,
+		`DROP INDEX "users_email_idx"`,
+		`ALTER TABLE "users" DROP CONSTRAINT "users_nick_check"`,
+		`ALTER TABLE "users" ADD COLUMN "legacy" text`,
+		`ALTER TABLE "users" ALTER COLUMN "email" TYPE varchar(100)`,
+		`ALTER TABLE "users" ALTER COLUMN "active" SET DEFAULT TRUE`,
+		`ALTER TABLE "users" DROP COLUMN "nick"`,
+		`CREATE INDEX "users_email_idx" ON "users" ("email")`,
+		`DROP TABLE "comments"`,
+		`DROP TABLE "posts"`,
+	}, sqls)
+}
+
+func TestDiffMySql(t *testing.T) {
+	mysql := Dialect(MYSQL)
+	old := NewDefinitions()
+	old.Define(mysql.Table("users"), &ColumnDef{Name: "id", Type: "int", Identity: true, PrimaryKey: true})
+	new := NewDefinitions()
+	new.Define(mysql.Table("users"),
+		&ColumnDef{Name: "id", Type: "bigint", Identity: true, PrimaryKey: true},
+		&ColumnDef{Name: "team_id", Type: "int", Nullable: true, References: &ForeignKey{Table: "teams", Column: "id"}},
+	).Indexes = []*IndexDef{{Name: "users_team_idx", Columns: []interface{}{"team_id"}}}
+
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: "ALTER TABLE `users` ADD COLUMN `team_id` int,ADD FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`)", Description: "add column users.team_id"},
+		{Sql: "ALTER TABLE `users` MODIFY COLUMN `id` bigint NOT NULL AUTO_INCREMENT", Description: "alter column users.id", Destructive: true},
+		{Sql: "CREATE INDEX `users_team_idx` ON `users` (`team_id`)", Description: "create index users_team_idx on users"},
+	}, statements)
+
+	statements, err = Diff(new, old)
+	assert.Nil(t, err)
+	assert.Equal(t, "DROP INDEX `users_team_idx` ON `users`", statements[0].Sql)
+}
+
+func TestDiffReferencesAddedColumn(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	old := NewDefinitions()
+	old.Define(psql.Table("users"), &ColumnDef{Name: "id", Type: "bigint", PrimaryKey: true})
+	new := NewDefinitions()
+	new.Define(psql.Table("tokens"),
+		&ColumnDef{Name: "token", Type: "text", PrimaryKey: true},
+		&ColumnDef{Name: "user_uid", Type: "uuid", References: &ForeignKey{Table: "users", Column: "uid"}},
+	)
+	new.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "bigint", PrimaryKey: true},
+		&ColumnDef{Name: "uid", Type: "uuid", Unique: true},
+	)
+
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `CREATE TABLE "tokens" ("token" text NOT NULL PRIMARY KEY,"user_uid" uuid NOT NULL)`, Description: "create table tokens"},
+		{Sql: `ALTER TABLE "users" ADD COLUMN "uid" uuid NOT NULL UNIQUE`, Description: "add column users.uid"},
+		{Sql: `ALTER TABLE "tokens" ADD FOREIGN KEY ("user_uid") REFERENCES "users" ("uid")`, Description: "add foreign key of tokens"},
+	}, statements)
+
+	// the referencing table is dropped before the column
+	statements, err = Diff(new, old)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `DROP TABLE "tokens"`, Description: "drop table tokens", Destructive: true},
+		{Sql: `ALTER TABLE "users" DROP COLUMN "uid"`, Description: "drop column users.uid", Destructive: true},
+	}, statements)
+}
+
+func TestDiffAlterColumn(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	old := NewDefinitions()
+	old.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "int", Identity: true},
+		&ColumnDef{Name: "score", Type: "int", Default: 0},
+	)
+	new := NewDefinitions()
+	new.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "bigint", Identity: true, Default: 1},
+		&ColumnDef{Name: "score", Type: "int", Nullable: true, Default: 1},
+	)
+
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `ALTER TABLE "users" ALTER COLUMN "id" TYPE bigint`, Description: "alter column users.id", Destructive: true},
+		{Sql: `ALTER TABLE "users" ALTER COLUMN "score" DROP NOT NULL,ALTER COLUMN "score" SET DEFAULT 1`, Description: "alter column users.score"},
+	}, statements)
+}
+
+func TestDiffCyclicReferences(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	old := NewDefinitions()
+	old.Define(psql.Table("users"), &ColumnDef{Name: "id", Type: "int", PrimaryKey: true})
+	new := NewDefinitions()
+	new.Define(psql.Table("users"), &ColumnDef{Name: "id", Type: "int", PrimaryKey: true})
+	new.Define(psql.Table("teams"),
+		&ColumnDef{Name: "id", Type: "int", PrimaryKey: true},
+		&ColumnDef{Name: "owner_id", Type: "int", References: &ForeignKey{Table: "members", Column: "id"}},
+	)
+	new.Define(psql.Table("members"),
+		&ColumnDef{Name: "id", Type: "int", PrimaryKey: true},
+		&ColumnDef{Name: "team_id", Type: "int"},
+		&ColumnDef{Name: "user_id", Type: "int", References: &ForeignKey{Table: "users", Column: "id"}},
+	).Constraints = []interface{}{Foreign("team_id").References(Table("teams"), "id").Named("members_team_fkey")}
+
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `CREATE TABLE "members" ("id" int NOT NULL PRIMARY KEY,"team_id" int NOT NULL,"user_id" int NOT NULL REFERENCES "users" ("id"))`, Description: "create table members"},
+		{Sql: `CREATE TABLE "teams" ("id" int NOT NULL PRIMARY KEY,"owner_id" int NOT NULL REFERENCES "members" ("id"))`, Description: "create table teams"},
+		{Sql: `ALTER TABLE "members" ADD CONSTRAINT "members_team_fkey" FOREIGN KEY ("team_id") REFERENCES "teams" ("id")`, Description: "add foreign key of members"},
+	}, statements)
+
+	// the named foreign key closing the cycle is dropped first
+	statements, err = Diff(new, old)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `ALTER TABLE "members" DROP CONSTRAINT "members_team_fkey"`, Description: "drop constraint members_team_fkey of members"},
+		{Sql: `DROP TABLE "teams"`, Description: "drop table teams", Destructive: true},
+		{Sql: `DROP TABLE "members"`, Description: "drop table members", Destructive: true},
+	}, statements)
+
+	// an unnamed one can not be dropped
+	cyclic := NewDefinitions()
+	cyclic.Define(psql.Table("a"), &ColumnDef{Name: "b_id", Type: "int", References: &ForeignKey{Table: "b", Column: "a_id"}})
+	cyclic.Define(psql.Table("b"), &ColumnDef{Name: "a_id", Type: "int", References: &ForeignKey{Table: "a", Column: "b_id"}})
+	_, err = Diff(cyclic, NewDefinitions())
+	assert.EqualError(t, err, "invalid schema: unnamed foreign key of 'b' referencing 'a' closes a cycle and can not be dropped")
+}
+
+func TestDiffColumnConstraints(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	old := NewDefinitions()
+	old.Define(psql.Table("teams"), &ColumnDef{Name: "id", Type: "int", PrimaryKey: true})
+	old.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "int"},
+		&ColumnDef{Name: "email", Type: "text"},
+		&ColumnDef{Name: "team_id", Type: "int"},
+	)
+	new := NewDefinitions()
+	new.Define(psql.Table("teams"), &ColumnDef{Name: "id", Type: "int", PrimaryKey: true})
+	new.Define(psql.Table("users"),
+		&ColumnDef{Name: "id", Type: "int", PrimaryKey: true},
+		&ColumnDef{Name: "email", Type: "text", Unique: true},
+		&ColumnDef{Name: "team_id", Type: "int", References: &ForeignKey{Table: "teams", Column: "id", OnDelete: "CASCADE"}},
+	)
+
+	statements, err := Diff(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, []Statement{
+		{Sql: `ALTER TABLE "users" ADD PRIMARY KEY ("id")`, Description: "add constraint of users"},
+		{Sql: `ALTER TABLE "users" ADD UNIQUE ("email")`, Description: "add constraint of users"},
+		{Sql: `ALTER TABLE "users" ADD FOREIGN KEY ("team_id") REFERENCES "teams" ("id") ON DELETE CASCADE`, Description: "add constraint of users"},
+	}, statements)
+
+	// unnamed column constraints can not be dropped
+	_, err = Diff(new, old)
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+
+	identity := NewDefinitions()
+	identity.Define(psql.Table("teams"), &ColumnDef{Name: "id", Type: "int", PrimaryKey: true, Identity: true})
+	_, err = Diff(old, identity)
+	assert.EqualError(t, err, "invalid schema: identity of column 'teams.id' can not be changed")
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	old, _ := diffSchemas()
+	again, _ := diffSchemas()
+	again.Define(Dialect(POSTGRES).Table("users"), old.Tables()[0].Columns...).Indexes = old.Tables()[0].Indexes
+
+	statements, err := Diff(old, again)
+	assert.Nil(t, err)
+	assert.Empty(t, statements)
+}
+
+func TestDiffErrors(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	old := NewDefinitions()
+	old.Define(users, &ColumnDef{Name: "id", Type: "int"}).Constraints = []interface{}{Unique("id")}
+	new := NewDefinitions()
+	new.Define(users, &ColumnDef{Name: "id", Type: "int"})
+
+	_, err := Diff(old, new)
+	assert.True(t, errors.Is(err, ErrInvalidSchema))
+
+	// adding is fine
+	statements, err := Diff(new, old)
+	assert.Nil(t, err)
+	assert.Equal(t, `ALTER TABLE "users" ADD UNIQUE ("id")`, statements[0].Sql)
+
+	new.Define(users, &ColumnDef{Name: "id"})
+	new.Define(Table("posts"), &ColumnDef{Name: "id"})
+	_, err = Diff(old, new)
+	assert.EqualError(t, err, "create table posts: invalid schema: column 'id' without type")
+}
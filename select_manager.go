@@ -1,38 +1,38 @@
 package codex
 
-import (
-	"fmt"
-)
+import "fmt"
 
 // SelectManager manages a tree that compiles to a SQL select statement.
 type SelectManager struct {
 	Tree    *SelectStatementNode // The AST for the SQL SELECT statement.
 	Adapter adapter              // The SQL adapter.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
 }
 
 var _ Scoper = (*SelectManager)(nil)
 
 func (self *SelectManager) Scopes(scopes ...ScopeFunc) *SelectManager {
+	self = self.chain()
 	for _, scope := range scopes {
 		scope(self)
 	}
 	return self
 }
 
+// Scope appends a WHERE condition like Where.
+// It always modifies the manager - even in immutable mode - as it is called by the ScopeFuncs of Scopes.
 func (self *SelectManager) Scope(expr interface{}, args ...interface{}) {
-	self.Where(expr, args...)
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 }
 
-// // Clone returns
-// func (m *SelectManager) Clone() *SelectManager{
-// 	newMng := deepcopy.Copy(m)
-// 	return  newMng.(*SelectManager)
-// }
-
 // Appends a projection to the current Context's Cols slice,
 // typically an AttributeNode or string.  If a string is provided, it is
 // inserted as a LiteralNode.
 func (self *SelectManager) Select(cols ...interface{}) *SelectManager {
+	self = self.chain()
 	for _, col := range cols {
 		if _, ok := col.(string); ok {
 			col = Column(col)
@@ -54,40 +54,44 @@ func (self *SelectManager) Select(cols ...interface{}) *SelectManager {
 //
 //
 func (self *SelectManager) Where(expr interface{}, args ...interface{}) *SelectManager {
-
-	if str, ok := expr.(string); ok {
-		expr = Literal(str, args...)
-	}
-	// enclose expr in Grouping - except if expr is already a Grouping
-	if _, ok := expr.(*GroupingNode); !ok {
-		expr = Grouping(expr)
-	}
-
-	self.Tree.Wheres = append(self.Tree.Wheres, expr)
+	self = self.chain()
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 	return self
 }
 
 // Sets the Tree's Offset to the given integer.
 func (self *SelectManager) Offset(skip int) *SelectManager {
+	self = self.chain()
 	self.Tree.Offset = Offset(skip)
 	return self
 }
 
 // Sets the Tree's Limit to the given integer.
 func (self *SelectManager) Limit(take int) *SelectManager {
+	self = self.chain()
 	self.Tree.Limit = Limit(take)
 	return self
 }
 
 // Appends a new InnerJoin to the current Context's SourceNode.
 func (self *SelectManager) InnerJoin(table interface{}) *SelectManager {
+	self = self.chain()
 	switch table.(type) {
 	case Accessor:
 		self.Tree.Source.Right = append(self.Tree.Source.Right, InnerJoin(table.(Accessor).Table(), nil))
 	case *TableNode:
 		self.Tree.Source.Right = append(self.Tree.Source.Right, InnerJoin(table.(*TableNode), nil))
+	case *DerivedTableNode:
+		self.Tree.Source.Right = append(self.Tree.Source.Right, InnerJoin(table.(*DerivedTableNode), nil))
+		if self.err == nil {
+			self.err = table.(*DerivedTableNode).Err
+		}
+	case *ValuesTableNode:
+		self.Tree.Source.Right = append(self.Tree.Source.Right, InnerJoin(table.(*ValuesTableNode), nil))
 	default:
-		panic(fmt.Sprintf("code.SelectManager.InnerJoin() type not expected! %#v", table))
+		if self.err == nil {
+			self.err = unexpectedType("SelectManager.InnerJoin()", table)
+		}
 	}
 
 	return self
@@ -95,24 +99,38 @@ func (self *SelectManager) InnerJoin(table interface{}) *SelectManager {
 
 // Appends a new InnerJoin to the current Context's SourceNode.
 func (self *SelectManager) OuterJoin(table interface{}) *SelectManager {
+	self = self.chain()
 	switch table.(type) {
 	case Accessor:
 		self.Tree.Source.Right = append(self.Tree.Source.Right, OuterJoin(table.(Accessor).Table(), nil))
 	case *TableNode:
 		self.Tree.Source.Right = append(self.Tree.Source.Right, OuterJoin(table.(*TableNode), nil))
+	case *DerivedTableNode:
+		self.Tree.Source.Right = append(self.Tree.Source.Right, OuterJoin(table.(*DerivedTableNode), nil))
+		if self.err == nil {
+			self.err = table.(*DerivedTableNode).Err
+		}
+	case *ValuesTableNode:
+		self.Tree.Source.Right = append(self.Tree.Source.Right, OuterJoin(table.(*ValuesTableNode), nil))
 	default:
-		panic(fmt.Sprintf("code.SelectManager.OuterJoin() type not expected! %#v", table))
+		if self.err == nil {
+			self.err = unexpectedType("SelectManager.OuterJoin()", table)
+		}
 	}
 
 	return self
 }
 
 // Sets the last stored Join's Right leaf to a OnNode containing the
-// given expression.
+// given expression. Without a preceding join an ErrArgumentCount error is recorded, see Err().
 func (self *SelectManager) On(expr interface{}) *SelectManager {
+	self = self.chain()
 	joins := self.Tree.Source.Right
 
 	if 0 == len(joins) {
+		if self.err == nil {
+			self.err = fmt.Errorf("%w: SelectManager.On() without preceding join", ErrArgumentCount)
+		}
 		return self
 	}
 
@@ -131,6 +149,7 @@ func (self *SelectManager) On(expr interface{}) *SelectManager {
 // Appends an expression to the current Context's Orders slice,
 // typically an attribute.
 func (self *SelectManager) Order(expr interface{}) *SelectManager {
+	self = self.chain()
 	if str, ok := expr.(string); ok {
 		expr = Literal(str)
 	}
@@ -142,6 +161,7 @@ func (self *SelectManager) Order(expr interface{}) *SelectManager {
 // Appends a node to the current Context's Groups slice,
 // typically an attribute or column.
 func (self *SelectManager) Group(groupings ...interface{}) *SelectManager {
+	self = self.chain()
 	for _, group := range groupings {
 		if str, ok := group.(string); ok {
 			group = Literal(str)
@@ -154,6 +174,7 @@ func (self *SelectManager) Group(groupings ...interface{}) *SelectManager {
 
 // Sets the Tree's Having member to the given expression.
 func (self *SelectManager) Having(expr interface{}) *SelectManager {
+	self = self.chain()
 	if str, ok := expr.(string); ok {
 		expr = Literal(str)
 	}
@@ -172,76 +193,189 @@ func (self *SelectManager) Count(expr interface{}) *SelectManager {
 	cols[0] = Count(expr)
 
 	tree := &SelectStatementNode{
-		Table:      self.Tree.Table,
-		Source:     self.Tree.Source,
-		Cols:       cols,
-		Wheres:     self.Tree.Wheres,
-		Groups:     self.Tree.Groups,
-		Having:     self.Tree.Having,
-		Orders:     make([]interface{}, 0),
-		Combinator: self.Tree.Combinator,
+		Table:       self.Tree.Table,
+		Source:      CloneNode(self.Tree.Source).(*JoinSourceNode),
+		Cols:        cols,
+		Wheres:      cloneSlice(self.Tree.Wheres),
+		Groups:      cloneSlice(self.Tree.Groups),
+		Having:      CloneNode(self.Tree.Having),
+		Orders:      make([]interface{}, 0),
+		Combinators: cloneSlice(self.Tree.Combinators),
 	}
 
 	m := &SelectManager{
-		Tree:    tree,
-		Adapter: self.Adapter,
+		Tree:      tree,
+		Adapter:   self.Adapter,
+		immutable: self.immutable,
+		err:       self.err,
+		hooks:     self.hooks,
 	}
 
 	return m
 }
 
-// Union sets the SelectManager's Tree's Combination member to a
-// UnionNode of itself and the parameter `manager`'s Tree.
+// Union appends an UNION of the parameter `manager`'s Tree to the
+// SelectManager's Tree's Combinators. Order, Limit and Offset
+// apply to the combined result. A copy of the Tree is appended,
+// modifying `manager` afterwards does not affect the combined query.
+// An error recorded by `manager` is recorded as well, see Err().
+//
+//   a.Union(b).UnionAll(c).Order("id").Limit(10)
+//   // SELECT ... FROM a UNION SELECT ... FROM b UNION ALL SELECT ... FROM c ORDER BY id LIMIT ?
 func (self *SelectManager) Union(manager *SelectManager) *SelectManager {
-	self.Tree.Combinator = Union(self.Tree, manager.Tree)
-	return self
+	return self.combine(Union(nil, cloneSelectStatement(manager.Tree)), manager)
+}
+
+// UnionAll appends an UNION ALL of the parameter `manager`'s Tree
+// to the SelectManager's Tree's Combinators.
+func (self *SelectManager) UnionAll(manager *SelectManager) *SelectManager {
+	return self.combine(UnionAll(nil, cloneSelectStatement(manager.Tree)), manager)
 }
 
-// Intersect sets the SelectManager's Tree's Combination member to a
-// IntersectNode of itself and the parameter `manager`'s Tree.
+// Intersect appends an INTERSECT of the parameter `manager`'s Tree
+// to the SelectManager's Tree's Combinators.
 func (self *SelectManager) Intersect(manager *SelectManager) *SelectManager {
-	self.Tree.Combinator = Intersect(self.Tree, manager.Tree)
-	return self
+	return self.combine(Intersect(nil, cloneSelectStatement(manager.Tree)), manager)
+}
+
+// IntersectAll appends an INTERSECT ALL of the parameter `manager`'s Tree
+// to the SelectManager's Tree's Combinators.
+func (self *SelectManager) IntersectAll(manager *SelectManager) *SelectManager {
+	return self.combine(IntersectAll(nil, cloneSelectStatement(manager.Tree)), manager)
 }
 
-// Except sets the SelectManager's Tree's Combination member to a
-// ExceptNode of itself and the parameter `manager`'s Tree.
+// Except appends an EXCEPT of the parameter `manager`'s Tree
+// to the SelectManager's Tree's Combinators.
 func (self *SelectManager) Except(manager *SelectManager) *SelectManager {
-	self.Tree.Combinator = Except(self.Tree, manager.Tree)
+	return self.combine(Except(nil, cloneSelectStatement(manager.Tree)), manager)
+}
+
+// ExceptAll appends an EXCEPT ALL of the parameter `manager`'s Tree
+// to the SelectManager's Tree's Combinators.
+func (self *SelectManager) ExceptAll(manager *SelectManager) *SelectManager {
+	return self.combine(ExceptAll(nil, cloneSelectStatement(manager.Tree)), manager)
+}
+
+// combine appends the combinator of `manager` and records its error, see Err().
+func (self *SelectManager) combine(combinator interface{}, manager *SelectManager) *SelectManager {
+	self = self.chain()
+	if self.err == nil {
+		self.err = manager.err
+	}
+	self.Tree.Combinators = append(self.Tree.Combinators, combinator)
 	return self
 }
 
 // Modification returns an *UpdateManager while keeping
-// wheres, limit and adapter
+// copies of wheres, limit and adapter
 func (self *SelectManager) Modification() *UpdateManager {
 	m := Modification(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
 func (self *SelectManager) Insertion() *InsertManager {
 	m := Insertion(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
 func (self *SelectManager) Deletion() *DeleteManager {
 	m := Deletion(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
+// Hooks adds hooks called by ToSql and ToSqlWith, see Hook.
+func (self *SelectManager) Hooks(hooks ...Hook) *SelectManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a deep copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *SelectManager) Clone() *SelectManager {
+	m := *self
+	m.Tree = cloneSelectStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+// Thus a base query can be branched into several variants.
+func (self *SelectManager) Immutable() *SelectManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *SelectManager) chain() *SelectManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the query e.g. an unexpected argument type.
+// ToSql returns it as well.
+func (self *SelectManager) Err() error {
+	return self.err
+}
+
 // ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// The Tree is not modified, so ToSql is safe to be called repeatedly and concurrently.
+// On error neither SQL nor arguments are returned.
 func (self *SelectManager) ToSql() (string, []interface{}, error) {
-	if 0 == len(self.Tree.Cols) {
-		self.Tree.Cols = append(self.Tree.Cols, Attribute(Star(), self.Tree.Table))
+	return render(self.hooks, "SELECT", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorFor(self.Adapter))
+	})
+}
+
+// ToSqlWith is like ToSql but renders the placeholders in `style` e.g. COLON_NUMBER for Oracle.
+func (self *SelectManager) ToSqlWith(style PlaceholderStyle) (string, []interface{}, error) {
+	return render(self.hooks, "SELECT", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(style)))
+	})
+}
+
+// ToDebugSql returns the SQL with the args inlined for logs and EXPLAIN copy-paste, see Interpolate.
+// NEVER execute it - use ToSql instead. Hooks are not called.
+func (self *SelectManager) ToDebugSql() (string, error) {
+	sql, args, err := self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(QUESTION_MARK)))
+	if err != nil {
+		return "", err
+	}
+	return Interpolate(sql, args, self.Adapter)
+}
+
+func (self *SelectManager) accept(visitor VisitorInterface) (string, []interface{}, error) {
+	if self.err != nil {
+		return "", nil, self.err
+	}
+	tree := self.Tree
+	if 0 == len(tree.Cols) {
+		// select "table".* by default - rendered from a shallow copy to leave the tree untouched
+		star := *tree
+		star.Cols = []interface{}{Attribute(Star(), tree.Table)}
+		tree = &star
+	}
+	if err := validateSchema(tree); err != nil {
+		return "", nil, err
 	}
 
-	return VisitorFor(self.Adapter).Accept(self.Tree)
+	return visitor.Accept(tree)
 }
 
 func (self *SelectManager) Table() *TableNode {
@@ -253,5 +387,6 @@ func Selection(relation *TableNode) (m *SelectManager) {
 	m = new(SelectManager)
 	m.Tree = SelectStatement(relation)
 	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
 	return
 }
@@ -1,6 +1,7 @@
 package codex
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -27,6 +28,9 @@ func TestSelectManager(t *testing.T) {
 	_ = mgr.Union(Selection(relation))
 	_ = mgr.Intersect(Selection(relation))
 	_ = mgr.Except(Selection(relation))
+	_ = mgr.UnionAll(Selection(relation))
+	_ = mgr.IntersectAll(Selection(relation))
+	_ = mgr.ExceptAll(Selection(relation))
 	_, _, _ = mgr.ToSql()
 }
 
@@ -186,3 +190,210 @@ func TestSelectManagerInnerJoin(t *testing.T) {
 	assert.Equal(t, `SELECT "users".* FROM "users" INNER JOIN "companies" ON "companies"."id"="users"."company_id"`, sql)
 	assert.Empty(t, args)
 }
+
+func TestSelectManagerOnWithoutJoin(t *testing.T) {
+	users := Table("users")
+	m := users.Select("id").On(users.Col("id").Eq(1))
+	assert.True(t, errors.Is(m.Err(), ErrArgumentCount))
+
+	_, _, err := m.ToSql()
+	assert.EqualError(t, err, "wrong number of arguments: SelectManager.On() without preceding join")
+}
+
+func TestSelectManagerUnionChained(t *testing.T) {
+	users := Table("users")
+	admins := Table("admins")
+	guests := Table("guests")
+
+	q := users.Select("id").Where("active = ?", true).
+		Union(admins.Select("id").Where("level > ?", 2)).
+		UnionAll(guests.Select("id")).
+		Order("id").Limit(10).Offset(20)
+
+	sql, args, err := q.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE (active = ?) UNION SELECT "admins"."id" FROM "admins" WHERE (level > ?) UNION ALL SELECT "guests"."id" FROM "guests" ORDER BY id LIMIT ? OFFSET ?`, sql)
+	assert.Equal(t, []interface{}{true, 2, 10, 20}, args)
+
+	// rendering twice gives the same result
+	sql2, args2, err := q.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, sql, sql2)
+	assert.Equal(t, args, args2)
+}
+
+func TestSelectManagerUnionWithLimitedOperand(t *testing.T) {
+	users := Table("users")
+	admins := Table("admins")
+
+	sql, args, err := users.Select("id").Except(admins.Select("id").Order("id").Limit(5)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" EXCEPT (SELECT "admins"."id" FROM "admins" ORDER BY id LIMIT ?)`, sql)
+	assert.Equal(t, []interface{}{5}, args)
+}
+
+func TestSelectManagerUnionLimitedLeft(t *testing.T) {
+	users := Table("users")
+	admins := Table("admins")
+
+	sql, args, err := users.Select("id").Where(Union(users.Select("id").Order("id").Limit(5), admins.Select("id"))).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ((SELECT "users"."id" FROM "users" ORDER BY id LIMIT ?) UNION SELECT "admins"."id" FROM "admins")`, sql)
+	assert.Equal(t, []interface{}{5}, args)
+}
+
+func TestSelectManagerUnionCopiesOperand(t *testing.T) {
+	users := Table("users")
+	admins := Table("admins")
+	b := admins.Select("id")
+	a := users.Select("id").Union(b)
+	b.Where("level > ?", 2)
+
+	sql, args, err := a.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" UNION SELECT "admins"."id" FROM "admins"`, sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectManagerUnionOperandError(t *testing.T) {
+	users := Table("users")
+	broken := Table("admins").Select("id").InnerJoin(42)
+
+	for _, m := range []*SelectManager{
+		users.Select("id").Union(broken), users.Select("id").UnionAll(broken),
+		users.Select("id").Intersect(broken), users.Select("id").IntersectAll(broken),
+		users.Select("id").Except(broken), users.Select("id").ExceptAll(broken),
+	} {
+		assert.True(t, errors.Is(m.Err(), ErrUnexpectedType))
+		sql, args, err := m.ToSql()
+		assert.True(t, errors.Is(err, ErrUnexpectedType))
+		assert.Equal(t, "", sql)
+		assert.Nil(t, args)
+	}
+}
+
+func TestSelectManagerNestedErrors(t *testing.T) {
+	users := Table("users")
+	orders := Table("orders")
+	broken := users.Select("id").InnerJoin(42)
+
+	// the sub select is modified after being nested, so the error is recorded by it only
+	sub := orders.Select("user_id")
+	subselect := users.Where(users.Col("id").In(sub))
+	sub.InnerJoin(42)
+
+	d := From(broken, "t")
+	for _, m := range []interface{ ToSql() (string, []interface{}, error) }{
+		users.Where(users.Col("id").In(broken)),
+		subselect,
+		users.Select("id").InnerJoin(d).On(d.Col("id").Eq(users.Col("id"))),
+		d.Select("id"),
+		users.Select("id").Union(broken),
+		users.Modification().Set("a").To(1).Where(users.Col("id").In(broken)),
+		users.Deletion().Where(users.Col("id").In(broken)),
+	} {
+		sql, args, err := m.ToSql()
+		assert.True(t, errors.Is(err, ErrUnexpectedType), "%T", m)
+		assert.Equal(t, "", sql)
+		assert.Nil(t, args)
+	}
+}
+
+func TestSelectManagerUnionPostgres(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+	admins := psql.Table("admins")
+
+	sql, args, err := users.Select("id").Where("a = ?", 1).IntersectAll(admins.Select("id").Where("b = ?", 2)).Limit(3).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE (a = $1) INTERSECT ALL SELECT "admins"."id" FROM "admins" WHERE (b = $2) LIMIT $3`, sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
+
+func TestSelectManagerClone(t *testing.T) {
+	users := Table("users")
+	base := users.Select("id").Where(users.Col("active").Eq(true))
+	clone := base.Clone().Where(users.Col("admin").Eq(true)).Limit(1)
+
+	sql, args, err := base.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."active"=?)`, sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	sql, args, err = clone.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."id" FROM "users" WHERE ("users"."active"=?) AND ("users"."admin"=?) LIMIT ?`, sql)
+	assert.Equal(t, []interface{}{true, true, 1}, args)
+}
+
+func TestSelectManagerImmutable(t *testing.T) {
+	users := Table("users")
+	companies := Table("companies")
+	base := users.Where(users.Col("active").Eq(true)).Immutable()
+
+	admins := base.Where(users.Col("admin").Eq(true))
+	joined := base.InnerJoin(companies).On(companies.Col("id").Eq(users.Col("company_id")))
+	base.Limit(1)
+
+	sql, args, err := base.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."active"=?)`, sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	sql, args, err = admins.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."active"=?) AND ("users"."admin"=?)`, sql)
+	assert.Equal(t, []interface{}{true, true}, args)
+
+	sql, args, err = joined.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" INNER JOIN "companies" ON "companies"."id"="users"."company_id" WHERE ("users"."active"=?)`, sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestSelectManagerImmutableScopes(t *testing.T) {
+	users := Table("users")
+	base := Selection(users).Immutable()
+	scope := func(s Scoper) {
+		s.Scope(users.Col("owner_id").Eq(77))
+	}
+
+	scoped := base.Scopes(scope)
+
+	sql, args, err := scoped.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."owner_id"=?)`, sql)
+	assert.Equal(t, []interface{}{77}, args)
+	assert.Empty(t, base.Tree.Wheres)
+}
+
+func TestSelectManagerCountDoesNotShareWheres(t *testing.T) {
+	users := Table("users")
+	mgr := users.Where("id > ?", 0)
+	count := mgr.Count(Star())
+	count.Where("active = ?", true)
+
+	sql, args, err := mgr.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE (id > ?)`, sql)
+	assert.Equal(t, []interface{}{0}, args)
+}
+
+func TestSelectManagerModificationDoesNotShareWheres(t *testing.T) {
+	users := Table("users")
+	mgr := users.Where("id > ?", 0)
+	mod := mgr.Modification().Set("name").To("x").Where("active = ?", true)
+	del := mgr.Deletion().Where("locked = ?", false)
+
+	sql, _, err := mgr.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE (id > ?)`, sql)
+
+	sql, _, err = mod.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=? WHERE (id > ?) AND (active = ?)`, sql)
+
+	sql, _, err = del.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE (id > ?) AND (locked = ?)`, sql)
+}
@@ -0,0 +1,137 @@
+package codex
+
+import (
+	"strings"
+)
+
+// Kinds of question marks returned by questionAt.
+const (
+	questionNone        = iota // no question mark
+	questionPlaceholder        // ? bind parameter
+	questionEscaped            // ?? literal question mark e.g. the JSONB operator
+	questionOperator           // ?| and ?& JSONB operators
+)
+
+// questionAt returns the kind and byte width of the question mark at s[i].
+// Placeholders are "?", a literal question mark is written "??".
+// The JSONB operators "?|" and "?&" are no placeholders,
+// the JSONB operator "?" must be escaped as "??".
+func questionAt(s string, i int) (kind int, width int) {
+	if s[i] != QUESTION {
+		return questionNone, 0
+	}
+	if i+1 < len(s) {
+		switch s[i+1] {
+		case QUESTION:
+			return questionEscaped, 2
+		case '|', '&':
+			// but ?|| and ?&& are a placeholder followed by an operator
+			if i+2 >= len(s) || s[i+2] != s[i+1] {
+				return questionOperator, 2
+			}
+		}
+	}
+	return questionPlaceholder, 1
+}
+
+// scanPlaceholders splits s into sql text and ? placeholders, see questionAt.
+// text is called for the sql between placeholders, placeholder for each placeholder.
+// Escaped question marks "??" are passed to text as "?" if unescape is true, as "??" otherwise.
+func scanPlaceholders(s string, unescape bool, text func(string), placeholder func()) {
+	last := 0
+	for i := 0; i < len(s); {
+		if j := skipQuoted(s, i); j > i {
+			i = j
+			continue
+		}
+		switch kind, width := questionAt(s, i); kind {
+		case questionPlaceholder:
+			text(s[last:i])
+			placeholder()
+			i += width
+			last = i
+		case questionEscaped:
+			if unescape {
+				text(s[last : i+1])
+				last = i + width
+			}
+			i += width
+		case questionOperator:
+			i += width
+		default:
+			i++
+		}
+	}
+	text(s[last:])
+}
+
+// isNameByte is true for bytes of identifiers, digits are not allowed as first byte.
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || !first && '0' <= c && c <= '9'
+}
+
+// skipQuoted returns the index after the quoted string, quoted identifier or comment starting at s[i],
+// i if there is none. Unterminated ones extend to the end of s.
+//
+// Supported are 'strings' (E'escaped \' strings'), "identifiers", `identifiers`,
+// $$dollar quoted$$ and $tag$dollar quoted$tag$ strings, -- line comments and /* nested */ block comments.
+func skipQuoted(s string, i int) int {
+	switch c := s[i]; c {
+	case '\'':
+		escapes := i > 0 && (s[i-1] == 'E' || s[i-1] == 'e') && (i == 1 || !isNameByte(s[i-2], false))
+		for j := i + 1; j < len(s); j++ {
+			if escapes && s[j] == '\\' {
+				j++
+			} else if s[j] == c {
+				return j + 1
+			}
+		}
+		return len(s)
+	case '"', '`':
+		if j := strings.IndexByte(s[i+1:], c); j >= 0 {
+			return i + j + 2
+		}
+		return len(s)
+	case '$':
+		if i > 0 && (isNameByte(s[i-1], false) || s[i-1] == '$') {
+			return i // e.g. identifier foo$bar
+		}
+		j := i + 1
+		for j < len(s) && isNameByte(s[j], j == i+1) {
+			j++
+		}
+		if j >= len(s) || s[j] != '$' {
+			return i // e.g. $1
+		}
+		tag := s[i : j+1]
+		if k := strings.Index(s[j+1:], tag); k >= 0 {
+			return j + 1 + k + len(tag)
+		}
+		return len(s)
+	case '-':
+		if strings.HasPrefix(s[i:], "--") {
+			if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+				return i + j + 1
+			}
+			return len(s)
+		}
+	case '/':
+		if strings.HasPrefix(s[i:], "/*") {
+			depth := 0
+			for j := i; j+1 < len(s); j++ {
+				if s[j] == '/' && s[j+1] == '*' {
+					depth++
+					j++
+				} else if s[j] == '*' && s[j+1] == '/' {
+					depth--
+					j++
+					if depth == 0 {
+						return j + 1
+					}
+				}
+			}
+			return len(s)
+		}
+	}
+	return i
+}
@@ -0,0 +1,56 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSkipQuoted(t *testing.T) {
+	for _, c := range []struct {
+		s        string
+		i, after int
+	}{
+		{`a`, 0, 0},
+		{`'it''s' x`, 0, 4},
+		{`'it''s' x`, 4, 7},
+		{`E'it\'s?' x`, 1, 9},
+		{`'it\'s?' x`, 0, 5}, // no E prefix - backslash is no escape
+		{`"na?me" x`, 0, 7},
+		{"`na?me` x", 0, 7},
+		{`$$a?b$$ x`, 0, 7},
+		{`$fn$a $$ ?b$fn$ x`, 0, 15},
+		{`$1 x`, 0, 0},
+		{`foo$bar$ x`, 3, 3},
+		{"-- a?b\nx", 0, 7},
+		{"-- a?b", 0, 6},
+		{`/* a /* b? */ c? */ x`, 0, 19},
+		{`/ x`, 0, 0},
+		{`- x`, 0, 0},
+		{`'unterminated ?`, 0, 15},
+		{`/* unterminated ?`, 0, 17},
+		{`$x$ unterminated ?`, 0, 18},
+	} {
+		assert.Equal(t, c.after, skipQuoted(c.s, c.i), c.s)
+	}
+}
+
+func TestQuestionAt(t *testing.T) {
+	for _, c := range []struct {
+		s     string
+		kind  int
+		width int
+	}{
+		{`a`, questionNone, 0},
+		{`?`, questionPlaceholder, 1},
+		{`? `, questionPlaceholder, 1},
+		{`??`, questionEscaped, 2},
+		{`?| array['a']`, questionOperator, 2},
+		{`?& array['a']`, questionOperator, 2},
+		{`?||'x'`, questionPlaceholder, 1},
+		{`?&&arr`, questionPlaceholder, 1},
+	} {
+		kind, width := questionAt(c.s, 0)
+		assert.Equal(t, c.kind, kind, c.s)
+		assert.Equal(t, c.width, width, c.s)
+	}
+}
@@ -0,0 +1,35 @@
+package codex
+
+// SqlNode is implemented by nodes rendering themselves.
+// Visit dispatches to Render for any value implementing SqlNode
+// before falling back to binding the value as argument.
+// Thus custom nodes can be added without touching the visitors:
+//
+//	type DWithinNode struct {
+//		Geom, Other interface{}
+//		Distance    float64
+//	}
+//
+//	func (o *DWithinNode) Render(visitor codex.VisitorInterface) error {
+//		visitor.AppendSqlStr("ST_DWithin(")
+//		if err := visitor.Visit(o.Geom, visitor); err != nil {
+//			return err
+//		}
+//		visitor.AppendSqlByte(',')
+//		if err := visitor.Visit(o.Other, visitor); err != nil {
+//			return err
+//		}
+//		visitor.AppendSqlByte(',')
+//		if err := visitor.Visit(o.Distance, visitor); err != nil { // bound as argument
+//			return err
+//		}
+//		visitor.AppendSqlByte(')')
+//		return nil
+//	}
+//
+//	users.Where(&DWithinNode{users.Col("location"), point, 1000})
+type SqlNode interface {
+	// Render appends the node's SQL and arguments to the visitor.
+	// Child nodes are rendered via visitor.Visit(child, visitor).
+	Render(visitor VisitorInterface) error
+}
@@ -0,0 +1,72 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type dWithinNode struct {
+	Geom, Other interface{}
+	Distance    float64
+}
+
+func (o *dWithinNode) Render(visitor VisitorInterface) error {
+	visitor.AppendSqlStr("ST_DWithin(")
+	if err := visitor.Visit(o.Geom, visitor); err != nil {
+		return err
+	}
+	visitor.AppendSqlByte(COMMA)
+	if err := visitor.Visit(o.Other, visitor); err != nil {
+		return err
+	}
+	visitor.AppendSqlByte(COMMA)
+	if err := visitor.Visit(o.Distance, visitor); err != nil {
+		return err
+	}
+	visitor.AppendSqlByte(')')
+	return nil
+}
+
+func TestSqlNodeCustom(t *testing.T) {
+	users := Table("users")
+	m := users.Where(users.Col("active").Eq(true)).Where(&dWithinNode{users.Col("location"), "POINT(1 2)", 1000})
+
+	sql, args, err := m.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."active"=?) AND (ST_DWithin("users"."location",?,?))`, sql)
+	assert.Equal(t, []interface{}{true, "POINT(1 2)", float64(1000)}, args)
+}
+
+func TestSqlNodeCustomPostgres(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	m := users.Where(users.Col("active").Eq(true)).Where(&dWithinNode{users.Col("location"), "POINT(1 2)", 1000})
+
+	sql, args, err := m.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."active"=$1) AND (ST_DWithin("users"."location",$2,$3))`, sql)
+	assert.Equal(t, []interface{}{true, "POINT(1 2)", float64(1000)}, args)
+}
+
+func TestSqlNodeCustomMySql(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+	m := users.Where(&dWithinNode{users.Col("location"), "POINT(1 2)", 1000})
+
+	sql, args, err := m.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `users`.* FROM `users` WHERE (ST_DWithin(`users`.`location`,?,?))", sql)
+	assert.Equal(t, []interface{}{"POINT(1 2)", float64(1000)}, args)
+}
+
+func TestSqlNodeAscendingDescending(t *testing.T) {
+	users := Table("users")
+
+	sql, args, err := NewToSqlVisitor().Accept(Ascending(users.Col("id")))
+	assert.Nil(t, err)
+	assert.Equal(t, `"users"."id" ASC`, sql)
+	assert.Empty(t, args)
+
+	sql, args, err = NewToSqlVisitor().Accept(Descending(Literal("?", 5)))
+	assert.Nil(t, err)
+	assert.Equal(t, `? DESC`, sql)
+	assert.Equal(t, []interface{}{5}, args)
+}
@@ -0,0 +1,1561 @@
+package codex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a SELECT, INSERT, UPDATE or DELETE statement of the dialect into a
+// *SelectManager, *InsertManager, *UpdateManager or *DeleteManager,
+// so existing SQL can be rewritten with the codex API and rendered again:
+//
+//	psql := Dialect(POSTGRES)
+//	m, err := psql.ParseSelect("SELECT id, name FROM users WHERE active = $1 ORDER BY name", true)
+//	m.Where(psql.Table("users").Col("tenant_id").Eq(7))
+//	// SELECT "id","name" FROM "users" WHERE ("active"=$1) AND ("users"."tenant_id"=$2) ORDER BY "name"
+//
+// The placeholders "?" or "$1", "$2" ... are bound to args, all args must be used.
+// Tables are created by the dialect, unqualified columns become ColumnNodes, qualified ones AttributeNodes.
+// Unquoted identifiers are folded to lower case for POSTGRES.
+// The top level AND conditions of WHERE become separate Wheres, each enclosed in parentheses like Where does.
+//
+// Supported are
+//   - SELECT [DISTINCT] with aliases, FROM a table, sub select or VALUES list, [INNER] JOIN and LEFT [OUTER] JOIN ... ON,
+//     WHERE, GROUP BY, HAVING, UNION, INTERSECT and EXCEPT [ALL], ORDER BY, LIMIT and OFFSET,
+//   - INSERT INTO ... (columns) VALUES (...), (...) [RETURNING ...],
+//   - UPDATE ... SET ... [FROM ...] [WHERE ...] [LIMIT ...] and DELETE FROM ... [WHERE ...] [LIMIT ...],
+//   - comparisons, [NOT] IN, [NOT] LIKE, IS [NOT] NULL, AND, OR, NOT, function calls and sub selects.
+//
+// Other expressions e.g. CASE, casts and operators like || or @> are kept as LiteralNodes.
+// Statements beyond that e.g. RIGHT JOIN, WITH or ON CONFLICT return ErrSyntax.
+func (db DbDialect) Parse(sql string, args ...interface{}) (interface{}, error) {
+	p, err := newSqlParser(db, sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var m interface{}
+	switch {
+	case p.isWord("SELECT"):
+		m, err = p.selectManager()
+	case p.isWord("INSERT"):
+		m, err = p.insertManager()
+	case p.isWord("UPDATE"):
+		m, err = p.updateManager()
+	case p.isWord("DELETE"):
+		m, err = p.deleteManager()
+	default:
+		err = p.errorf("expected SELECT, INSERT, UPDATE or DELETE")
+	}
+	if err == nil {
+		err = p.end()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseSelect parses a SELECT statement, see Parse.
+func (db DbDialect) ParseSelect(sql string, args ...interface{}) (*SelectManager, error) {
+	m, err := db.Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := m.(*SelectManager); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: expected SELECT statement", ErrSyntax)
+}
+
+// ParseInsert parses an INSERT statement, see Parse.
+func (db DbDialect) ParseInsert(sql string, args ...interface{}) (*InsertManager, error) {
+	m, err := db.Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := m.(*InsertManager); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: expected INSERT statement", ErrSyntax)
+}
+
+// ParseUpdate parses an UPDATE statement, see Parse.
+func (db DbDialect) ParseUpdate(sql string, args ...interface{}) (*UpdateManager, error) {
+	m, err := db.Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := m.(*UpdateManager); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: expected UPDATE statement", ErrSyntax)
+}
+
+// ParseDelete parses a DELETE statement, see Parse.
+func (db DbDialect) ParseDelete(sql string, args ...interface{}) (*DeleteManager, error) {
+	m, err := db.Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := m.(*DeleteManager); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: expected DELETE statement", ErrSyntax)
+}
+
+// Placeholders returns the number of args Parse expects for sql:
+// the number of "?" placeholders or the highest n of "$n" placeholders.
+func (db DbDialect) Placeholders(sql string) (int, error) {
+	p, err := newSqlParser(db, sql, nil)
+	if err != nil {
+		return 0, err
+	}
+	return p.placeholders(), nil
+}
+
+// Kinds of sqlTokens.
+const (
+	sqlWord       = iota + 1 // keyword or unquoted identifier
+	sqlIdentifier            // quoted identifier, text is the unquoted name
+	sqlString                // string constant e.g. 'it''s' or $$text$$
+	sqlNumber                //
+	sqlParam                 // placeholder ? or $1
+	sqlOperator              // e.g. = <> || @> ::
+	sqlPunct                 // ( ) [ ] , . ;
+)
+
+type sqlToken struct {
+	kind       int
+	text       string // unquoted name of identifiers, the source otherwise
+	start, end int    // position within the source
+}
+
+// tokenizeSql splits src into tokens, comments are skipped.
+// "?" is a placeholder unless src has numbered placeholders "$1", then it is the JSONB operator.
+func tokenizeSql(src string, adapter adapter) (tokens []sqlToken, err error) {
+	adapter = adapter.dialect()
+	numbered := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		kind := 0
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case c == '#' && adapter == MYSQL:
+			if j := strings.IndexByte(src[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(src)
+			}
+			continue
+		case strings.HasPrefix(src[i:], "--") || strings.HasPrefix(src[i:], "/*"):
+			i = skipQuoted(src, i)
+			continue
+		case c == '\'' || c == '"' && adapter == MYSQL:
+			kind = sqlString
+			i, err = skipString(src, i, adapter == MYSQL)
+		case (c == 'E' || c == 'e') && i+1 < len(src) && src[i+1] == '\'' && adapter != MYSQL:
+			kind = sqlString
+			i, err = skipString(src, i+1, true)
+		case c == '"' || c == '`':
+			if c == '`' && adapter != MYSQL {
+				return nil, fmt.Errorf("%w: unexpected quote %c", ErrSyntax, c)
+			}
+			var name strings.Builder
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == c {
+					if j+1 < len(src) && src[j+1] == c { // doubled quote
+						name.WriteByte(c)
+						j++
+						continue
+					}
+					break
+				}
+				name.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("%w: unterminated quote %c", ErrSyntax, c)
+			}
+			i = j + 1
+			tokens = append(tokens, sqlToken{kind: sqlIdentifier, text: name.String(), start: start, end: i})
+			continue
+		case c == '$' && i+1 < len(src) && '0' <= src[i+1] && src[i+1] <= '9':
+			kind = sqlParam
+			numbered = true
+			for i++; i < len(src) && '0' <= src[i] && src[i] <= '9'; i++ {
+			}
+		case c == '$':
+			if i = skipQuoted(src, start); i == start {
+				return nil, fmt.Errorf("%w: unexpected $", ErrSyntax)
+			}
+			kind = sqlString
+		case c == QUESTION:
+			qkind, width := questionAt(src, i)
+			i += width
+			kind = sqlOperator
+			if qkind == questionPlaceholder {
+				kind = sqlParam
+			}
+			if qkind == questionEscaped {
+				tokens = append(tokens, sqlToken{kind: sqlOperator, text: "?", start: start, end: i})
+				continue
+			}
+		case '0' <= c && c <= '9' || c == '.' && i+1 < len(src) && '0' <= src[i+1] && src[i+1] <= '9':
+			kind = sqlNumber
+			i = skipNumber(src, i)
+		case isNameByte(c, true) || c >= 0x80:
+			kind = sqlWord
+			for i++; i < len(src) && (isNameByte(src[i], false) || src[i] == '$' || src[i] >= 0x80); i++ {
+			}
+		case strings.IndexByte("()[],.;", c) >= 0:
+			kind = sqlPunct
+			i++
+		case strings.HasPrefix(src[i:], "::"):
+			kind = sqlOperator
+			i += 2
+		case strings.IndexByte(sqlOperatorBytes, c) >= 0:
+			kind = sqlOperator
+			i = skipOperator(src, i)
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, c)
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, sqlToken{kind: kind, text: src[start:i], start: start, end: i})
+	}
+
+	if numbered {
+		for i, tok := range tokens {
+			if tok.kind == sqlParam && tok.text == "?" {
+				tokens[i].kind = sqlOperator
+			}
+		}
+	}
+	return
+}
+
+// sqlOperatorBytes are the bytes operators consist of.
+const sqlOperatorBytes = "+-*/<>=~!@#%^&|"
+
+// skipString returns the index after the string starting with the quote at src[i].
+// Backslashes escape the following byte if escapes is true.
+func skipString(src string, i int, escapes bool) (int, error) {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case escapes && src[j] == '\\':
+			j++
+		case src[j] == quote:
+			if j+1 < len(src) && src[j+1] == quote { // doubled quote
+				j++
+				continue
+			}
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated quote %c", ErrSyntax, quote)
+}
+
+// skipNumber returns the index after the number starting at src[i] e.g. 42, 3.14 or 1e-3.
+func skipNumber(src string, i int) int {
+	for i < len(src) && ('0' <= src[i] && src[i] <= '9' || src[i] == '.') {
+		i++
+	}
+	if i+1 < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if src[j] == '+' || src[j] == '-' {
+			j++
+		}
+		if j < len(src) && '0' <= src[j] && src[j] <= '9' {
+			for i = j; i < len(src) && '0' <= src[i] && src[i] <= '9'; i++ {
+			}
+		}
+	}
+	return i
+}
+
+// skipOperator returns the index after the operator starting at src[i].
+// Like postgres an operator ends before comments and a trailing + or - is no part of it
+// unless it contains one of ~!@#%^&|, so "=-1" is "=" followed by "-1".
+func skipOperator(src string, i int) int {
+	start := i
+	for i < len(src) && strings.IndexByte(sqlOperatorBytes, src[i]) >= 0 &&
+		!strings.HasPrefix(src[i:], "--") && !strings.HasPrefix(src[i:], "/*") {
+		i++
+	}
+	for i-start > 1 && (src[i-1] == '+' || src[i-1] == '-') && !strings.ContainsAny(src[start:i], "~!@#%^&|") {
+		i--
+	}
+	if i == start { // e.g. "-" of "--"
+		i++
+	}
+	return i
+}
+
+// sqlKeywords end a table or column expression, they are no aliases.
+var sqlKeywords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true, "CROSS": true, "DESC": true,
+	"EXCEPT": true, "FETCH": true, "FOR": true, "FROM": true, "FULL": true, "GROUP": true, "HAVING": true,
+	"ILIKE": true, "IN": true, "INNER": true, "INTERSECT": true, "IS": true, "JOIN": true, "LEFT": true,
+	"LIKE": true, "LIMIT": true, "NATURAL": true, "NOT": true, "OFFSET": true, "ON": true, "OR": true,
+	"ORDER": true, "OUTER": true, "RETURNING": true, "RIGHT": true, "SET": true, "UNION": true,
+	"USING": true, "VALUES": true, "WHERE": true, "WINDOW": true,
+}
+
+// sqlConstants are keywords rendered as they are e.g. NULL or CURRENT_TIMESTAMP.
+var sqlConstants = map[string]bool{
+	"NULL": true, "TRUE": true, "FALSE": true, "DEFAULT": true, "UNKNOWN": true,
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "LOCALTIME": true,
+	"LOCALTIMESTAMP": true, "CURRENT_USER": true, "SESSION_USER": true,
+}
+
+// sqlComparisons are the comparison operators having nodes of their own.
+var sqlComparisons = map[string]func(left, right interface{}) interface{}{
+	"=":  func(left, right interface{}) interface{} { return Equal(left, right) },
+	"!=": func(left, right interface{}) interface{} { return NotEqual(left, right) },
+	"<>": func(left, right interface{}) interface{} { return NotEqual(left, right) },
+	">":  func(left, right interface{}) interface{} { return GreaterThan(left, right) },
+	">=": func(left, right interface{}) interface{} { return GreaterThanOrEqual(left, right) },
+	"<":  func(left, right interface{}) interface{} { return LessThan(left, right) },
+	"<=": func(left, right interface{}) interface{} { return LessThanOrEqual(left, right) },
+}
+
+// sqlCombinators are the set operations chained onto a SelectStatementNode.
+var sqlCombinators = map[string]func(right interface{}) interface{}{
+	"UNION":         func(right interface{}) interface{} { return Union(nil, right) },
+	"UNION ALL":     func(right interface{}) interface{} { return UnionAll(nil, right) },
+	"INTERSECT":     func(right interface{}) interface{} { return Intersect(nil, right) },
+	"INTERSECT ALL": func(right interface{}) interface{} { return IntersectAll(nil, right) },
+	"EXCEPT":        func(right interface{}) interface{} { return Except(nil, right) },
+	"EXCEPT ALL":    func(right interface{}) interface{} { return ExceptAll(nil, right) },
+}
+
+type sqlParser struct {
+	src     string
+	tokens  []sqlToken
+	pos     int
+	db      DbDialect
+	adapter adapter
+	args    []interface{}
+	next    int                   // index of the argument of the next "?"
+	used    []bool                // args bound to a placeholder
+	tables  map[string]*TableNode // tables and aliases by their qualified name
+}
+
+// sqlMark is a position of the parser to return to, see mark and rawFrom.
+type sqlMark struct {
+	pos, next int
+}
+
+func newSqlParser(db DbDialect, sql string, args []interface{}) (*sqlParser, error) {
+	adapter := db("").Table.Adapter.dialect()
+	tokens, err := tokenizeSql(sql, adapter)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlParser{
+		src:     sql,
+		tokens:  tokens,
+		db:      db,
+		adapter: adapter,
+		args:    args,
+		used:    make([]bool, len(args)),
+		tables:  map[string]*TableNode{},
+	}, nil
+}
+
+// end checks that all tokens and arguments are consumed.
+func (p *sqlParser) end() error {
+	p.punct(";")
+	if p.pos < len(p.tokens) {
+		return p.errorf("expected end of statement")
+	}
+	for _, used := range p.used {
+		if !used {
+			return fmt.Errorf("%w: %d arguments for %d placeholders", ErrArgumentCount, len(p.args), p.placeholders())
+		}
+	}
+	return nil
+}
+
+// placeholders returns the number of the placeholders of the statement.
+func (p *sqlParser) placeholders() (n int) {
+	for _, tok := range p.tokens {
+		if tok.kind != sqlParam {
+			continue
+		}
+		if tok.text == "?" {
+			n++
+		} else if i, _ := strconv.Atoi(tok.text[1:]); i > n {
+			n = i
+		}
+	}
+	return
+}
+
+// Begin statements.
+
+func (p *sqlParser) selectManager() (*SelectManager, error) {
+	stm, err := p.selectStatement()
+	if err != nil {
+		return nil, err
+	}
+	m := Selection(stm.Table)
+	m.Tree = stm
+	m.Adapter = p.adapter
+	return m, nil
+}
+
+// selectStatement parses a SELECT with its combinators, ORDER BY, LIMIT and OFFSET.
+func (p *sqlParser) selectStatement() (stm *SelectStatementNode, err error) {
+	if stm, err = p.selectCore(); err != nil {
+		return
+	}
+
+	for {
+		keyword := strings.ToUpper(p.peek().text)
+		combinator := sqlCombinators[keyword]
+		if p.peek().kind != sqlWord || combinator == nil {
+			break
+		}
+		p.pos++
+		if p.word("ALL") {
+			combinator = sqlCombinators[keyword+" ALL"]
+		} else {
+			p.word("DISTINCT")
+		}
+
+		var right *SelectStatementNode
+		if p.punct("(") {
+			if right, err = p.selectStatement(); err != nil {
+				return
+			}
+			if err = p.expectPunct(")"); err != nil {
+				return
+			}
+		} else if right, err = p.selectCore(); err != nil {
+			return
+		}
+		stm.Combinators = append(stm.Combinators, combinator(right))
+	}
+
+	if p.words("ORDER", "BY") {
+		if stm.Orders, err = p.orders(); err != nil {
+			return
+		}
+	}
+	stm.Limit, stm.Offset, err = p.limit(true)
+	return
+}
+
+// selectCore parses SELECT ... FROM ... WHERE ... GROUP BY ... HAVING ...
+func (p *sqlParser) selectCore() (stm *SelectStatementNode, err error) {
+	if err = p.expectWord("SELECT"); err != nil {
+		return
+	}
+
+	var distinct interface{}
+	if start := p.mark(); p.word("DISTINCT") {
+		if p.word("ON") {
+			if err = p.expectPunct("("); err != nil {
+				return
+			}
+			if err = p.skipGroup(); err != nil {
+				return
+			}
+		}
+		if distinct, err = p.rawFrom(start); err != nil {
+			return
+		}
+	} else {
+		p.word("ALL")
+	}
+
+	cols, err := p.list(p.selectItem)
+	if err != nil {
+		return
+	}
+	if distinct != nil {
+		cols[0] = &BinaryLiteralNode{Left: distinct, Right: cols[0]}
+	}
+
+	var source interface{}
+	table := p.db("").Table
+	if p.word("FROM") {
+		if source, table, err = p.tableSource(); err != nil {
+			return
+		}
+	}
+	stm = SelectStatement(table)
+	if source != nil {
+		stm.Source.Left = source
+	}
+	stm.Cols = cols
+
+	if stm.Source.Right, err = p.joins(); err != nil {
+		return
+	}
+	if p.word("WHERE") {
+		if stm.Wheres, err = p.wheres(); err != nil {
+			return
+		}
+	}
+	if p.words("GROUP", "BY") {
+		if stm.Groups, err = p.list(p.expr); err != nil {
+			return
+		}
+	}
+	if p.word("HAVING") {
+		var having interface{}
+		if having, err = p.expr(); err != nil {
+			return
+		}
+		stm.Having = Having(having)
+	}
+	return
+}
+
+func (p *sqlParser) insertManager() (m *InsertManager, err error) {
+	p.pos++
+	if err = p.expectWord("INTO"); err != nil {
+		return
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return
+	}
+	m = Insertion(table)
+	m.Adapter = p.adapter
+
+	if p.punct("(") {
+		var names []string
+		if names, err = p.names(); err != nil {
+			return
+		}
+		for _, name := range names {
+			m.Tree.Columns = append(m.Tree.Columns, name)
+			m.Tree.Values.Columns = append(m.Tree.Values.Columns, name)
+		}
+	}
+
+	if err = p.expectWord("VALUES"); err != nil {
+		return
+	}
+	rows, err := p.rows()
+	if err != nil {
+		return
+	}
+	m.Tree.Values.Expressions = rows[0]
+	m.Tree.Values.Rows = rows[1:]
+
+	if p.word("RETURNING") {
+		start := p.mark()
+		var cols []interface{}
+		if cols, err = p.list(p.selectItem); err != nil {
+			return
+		}
+		if m.Tree.Returning = cols[0]; 1 < len(cols) {
+			m.Tree.Returning, err = p.rawFrom(start)
+		}
+	}
+	return
+}
+
+func (p *sqlParser) updateManager() (m *UpdateManager, err error) {
+	p.pos++
+	table, err := p.tableName()
+	if err != nil {
+		return
+	}
+	m = Modification(table)
+	m.Adapter = p.adapter
+
+	// MySQL multi table update
+	for p.punct(",") {
+		source, _, err := p.tableSource()
+		if err != nil {
+			return nil, err
+		}
+		m.Tree.Froms = append(m.Tree.Froms, source)
+	}
+
+	if err = p.expectWord("SET"); err != nil {
+		return
+	}
+	if m.Tree.Values, err = p.list(p.assignment); err != nil {
+		return
+	}
+	if p.word("FROM") {
+		if m.Tree.Froms, err = p.list(p.fromSource); err != nil {
+			return
+		}
+	}
+	if p.word("WHERE") {
+		if m.Tree.Wheres, err = p.wheres(); err != nil {
+			return
+		}
+	}
+	m.Tree.Limit, _, err = p.limit(false)
+	return
+}
+
+func (p *sqlParser) deleteManager() (m *DeleteManager, err error) {
+	p.pos++
+	if err = p.expectWord("FROM"); err != nil {
+		return
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return
+	}
+	m = Deletion(table)
+	m.Adapter = p.adapter
+
+	if p.word("WHERE") {
+		if m.Tree.Wheres, err = p.wheres(); err != nil {
+			return
+		}
+	}
+	m.Tree.Limit, _, err = p.limit(false)
+	return
+}
+
+// End statements.
+
+// Begin clauses.
+
+// selectItem parses a column of SELECT or RETURNING: *, an expression or an expression with alias.
+func (p *sqlParser) selectItem() (interface{}, error) {
+	if p.operator("*") {
+		return Star(), nil
+	}
+	expr, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	alias, ok, err := p.alias()
+	if err != nil || !ok {
+		return expr, err
+	}
+	return As(expr, Column(alias)), nil
+}
+
+// alias parses [AS] alias.
+func (p *sqlParser) alias() (string, bool, error) {
+	if p.word("AS") {
+		name, err := p.identifier()
+		return name, err == nil, err
+	}
+	tok := p.peek()
+	if tok.kind == sqlIdentifier || tok.kind == sqlWord && !sqlKeywords[strings.ToUpper(tok.text)] {
+		name, err := p.identifier()
+		return name, err == nil, err
+	}
+	return "", false, nil
+}
+
+// tableSource parses a table, a sub select or a VALUES list with optional alias.
+// It returns the node of the join source and the table the columns are scoped to.
+func (p *sqlParser) tableSource() (interface{}, *TableNode, error) {
+	if p.punct("(") {
+		if p.word("VALUES") {
+			return p.valuesTable()
+		}
+		sub, err := p.selectStatement()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = p.expectPunct(")"); err != nil {
+			return nil, nil, err
+		}
+		alias, ok, err := p.alias()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, p.errorf("expected alias of sub select")
+		}
+		d := DerivedTable(sub, alias)
+		d.Table = p.aliasTable(alias)
+		return d, d.Table, nil
+	}
+
+	table, err := p.tableName()
+	if err != nil {
+		return nil, nil, err
+	}
+	alias, ok, err := p.alias()
+	if err != nil || !ok {
+		return table, table, err
+	}
+	aliased := p.aliasTable(alias)
+	return As(table, aliased), aliased, nil
+}
+
+// fromSource parses a source of UPDATE ... FROM.
+func (p *sqlParser) fromSource() (interface{}, error) {
+	source, _, err := p.tableSource()
+	return source, err
+}
+
+// valuesTable parses the rows, alias and columns of (VALUES (...), (...)) AS alias (columns).
+// Values cast with CAST(value AS type) set the Types of the ValuesTableNode, the casts of all rows must be the same.
+func (p *sqlParser) valuesTable() (interface{}, *TableNode, error) {
+	v := &ValuesTableNode{}
+	for {
+		types := []string{}
+		p.word("ROW") // MySQL 8
+		row, err := p.row(func() (interface{}, error) {
+			if !p.isWord("CAST") || p.peekAt(1).text != "(" {
+				types = append(types, "")
+				return p.expr()
+			}
+			p.pos += 2
+			value, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expectWord("AS"); err != nil {
+				return nil, err
+			}
+			start := p.pos
+			for p.pos < len(p.tokens) && !p.isPunct(")") {
+				if p.pos++; p.tokens[p.pos-1].text == "(" {
+					if err = p.skipGroup(); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected type")
+			}
+			types = append(types, p.src[p.tokens[start].start:p.tokens[p.pos-1].end])
+			return value, p.expectPunct(")")
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if 0 == len(v.Rows) {
+			v.Types = types
+		} else if strings.Join(types, ",") != strings.Join(v.Types, ",") {
+			return nil, nil, p.errorf("expected the CASTs of the first row")
+		}
+		v.Row(row.([]interface{})...)
+		if !p.punct(",") {
+			break
+		}
+	}
+	if strings.Join(v.Types, "") == "" {
+		v.Types = nil
+	}
+
+	if err := p.expectPunct(")"); err != nil {
+		return nil, nil, err
+	}
+	alias, ok, err := p.alias()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, p.errorf("expected alias of VALUES")
+	}
+	v.Table = p.aliasTable(alias)
+	if p.punct("(") {
+		if v.Columns, err = p.names(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return v, v.Table, nil
+}
+
+// joins parses [INNER] JOIN and LEFT [OUTER] JOIN with their ON conditions.
+func (p *sqlParser) joins() (joins []interface{}, err error) {
+	for {
+		var join func(left, right interface{}) interface{}
+		switch {
+		case p.word("JOIN"), p.words("INNER", "JOIN"):
+			join = func(left, right interface{}) interface{} { return InnerJoin(left, right) }
+		case p.words("LEFT", "JOIN"), p.words("LEFT", "OUTER", "JOIN"):
+			join = func(left, right interface{}) interface{} { return OuterJoin(left, right) }
+		case p.isWord("RIGHT"), p.isWord("FULL"), p.isWord("CROSS"), p.isWord("NATURAL"), p.isPunct(","):
+			return nil, p.errorf("unsupported join, use [INNER] JOIN or LEFT [OUTER] JOIN")
+		default:
+			return
+		}
+
+		source, _, err := p.tableSource()
+		if err != nil {
+			return nil, err
+		}
+		var on interface{}
+		if p.word("ON") {
+			expr, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			on = On(expr)
+		} else if p.isWord("USING") {
+			return nil, p.errorf("unsupported USING, use ON")
+		}
+		joins = append(joins, join(source, on))
+	}
+}
+
+// wheres parses the condition of WHERE, each top level AND condition becomes a Grouping like Where does.
+func (p *sqlParser) wheres() ([]interface{}, error) {
+	expr, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return conditions(expr, nil), nil
+}
+
+// conditions appends the AND conditions of expr as Groupings to wheres.
+func conditions(expr interface{}, wheres []interface{}) []interface{} {
+	if and, ok := expr.(*AndNode); ok {
+		return conditions(and.Right, conditions(and.Left, wheres))
+	}
+	return append(wheres, whereExpr(expr))
+}
+
+// orders parses the expressions of ORDER BY with their optional ASC or DESC.
+func (p *sqlParser) orders() ([]interface{}, error) {
+	return p.list(func() (interface{}, error) {
+		start := p.mark()
+		expr, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.word("ASC"):
+			expr = Ascending(expr)
+		case p.word("DESC"):
+			expr = Descending(expr)
+		}
+		if p.word("NULLS") {
+			if !p.word("FIRST") && !p.word("LAST") {
+				return nil, p.errorf("expected FIRST or LAST")
+			}
+			return p.rawFrom(start)
+		}
+		return expr, nil
+	})
+}
+
+// limit parses LIMIT count [OFFSET skip], MySQL's LIMIT skip, count and OFFSET skip if offset is true.
+func (p *sqlParser) limit(offset bool) (limit *LimitNode, skip *OffsetNode, err error) {
+	var expr interface{}
+	if p.word("LIMIT") {
+		if expr, err = p.operand(); err != nil {
+			return
+		}
+		if offset && p.punct(",") {
+			skip = Offset(expr)
+			if expr, err = p.operand(); err != nil {
+				return
+			}
+		}
+		limit = Limit(expr)
+	}
+	if offset && skip == nil && p.word("OFFSET") {
+		if expr, err = p.operand(); err != nil {
+			return
+		}
+		skip = Offset(expr)
+		p.word("ROWS")
+	}
+	return
+}
+
+// assignment parses column = value of UPDATE ... SET.
+func (p *sqlParser) assignment() (interface{}, error) {
+	column, err := p.columnRef()
+	if err != nil {
+		return nil, err
+	}
+	if !p.operator("=") {
+		return nil, p.errorf("expected '='")
+	}
+	value, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return Assignment(column, value), nil
+}
+
+// rows parses (...), (...) of VALUES.
+func (p *sqlParser) rows() ([][]interface{}, error) {
+	list, err := p.list(func() (interface{}, error) {
+		return p.row(p.expr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]interface{}, len(list))
+	for i, row := range list {
+		rows[i] = row.([]interface{})
+	}
+	return rows, nil
+}
+
+// row parses a parenthesized list of values.
+func (p *sqlParser) row(value func() (interface{}, error)) (interface{}, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	values, err := p.list(value)
+	if err != nil {
+		return nil, err
+	}
+	return values, p.expectPunct(")")
+}
+
+// names parses the identifiers of a column list up to the closing parenthesis.
+func (p *sqlParser) names() (names []string, err error) {
+	for {
+		var name string
+		if name, err = p.identifier(); err != nil {
+			return
+		}
+		names = append(names, name)
+		if !p.punct(",") {
+			return names, p.expectPunct(")")
+		}
+	}
+}
+
+// list parses one or more comma separated items.
+func (p *sqlParser) list(item func() (interface{}, error)) (items []interface{}, err error) {
+	for {
+		var o interface{}
+		if o, err = item(); err != nil {
+			return
+		}
+		items = append(items, o)
+		if !p.punct(",") {
+			return
+		}
+	}
+}
+
+// End clauses.
+
+// Begin expressions.
+
+// expr parses an expression, from lowest to highest precedence:
+// OR, AND, NOT, comparisons, other operators and operands.
+func (p *sqlParser) expr() (interface{}, error) {
+	left, err := p.and()
+	for err == nil && p.word("OR") {
+		var right interface{}
+		if right, err = p.and(); err == nil {
+			left = Or(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *sqlParser) and() (interface{}, error) {
+	left, err := p.not()
+	for err == nil && p.word("AND") {
+		var right interface{}
+		if right, err = p.not(); err == nil {
+			left = And(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *sqlParser) not() (interface{}, error) {
+	if !p.word("NOT") {
+		return p.comparison()
+	}
+	expr, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	// NotNode renders the parentheses
+	if grouping, ok := expr.(*GroupingNode); ok {
+		expr = grouping.Expr
+	}
+	return Not(expr), nil
+}
+
+// comparison parses comparisons, [NOT] IN, [NOT] LIKE, [NOT] BETWEEN and IS [NOT] ...
+func (p *sqlParser) comparison() (interface{}, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind == sqlOperator && sqlComparisons[tok.text] != nil {
+		p.pos++
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return sqlComparisons[tok.text](left, right), nil
+	}
+
+	if p.word("IS") {
+		not := p.word("NOT")
+		switch {
+		case p.word("NULL"):
+			if not {
+				return NotEqual(left, nil), nil
+			}
+			return Equal(left, nil), nil
+		case p.words("DISTINCT", "FROM"):
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return infix(left, joinKeywords("IS", not, "DISTINCT FROM"), right), nil
+		}
+		tok := p.peek()
+		if keyword := strings.ToUpper(tok.text); tok.kind == sqlWord && sqlConstants[keyword] {
+			p.pos++
+			return infix(left, joinKeywords("IS", not, ""), Literal(keyword)), nil
+		}
+		return nil, p.errorf("expected NULL, TRUE, FALSE or DISTINCT FROM")
+	}
+
+	not := p.isWord("NOT") && (p.isWordAt(1, "IN") || p.isWordAt(1, "LIKE") || p.isWordAt(1, "ILIKE") || p.isWordAt(1, "BETWEEN"))
+	if not {
+		p.pos++
+	}
+	switch {
+	case p.word("IN"):
+		if err = p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		var values []interface{}
+		if p.isWord("SELECT") {
+			var sub *SelectStatementNode
+			sub, err = p.selectStatement()
+			values = []interface{}{sub}
+		} else {
+			values, err = p.list(p.expr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		if not {
+			return Not(In(left, values...)), nil
+		}
+		return In(left, values...), nil
+
+	case p.isWord("LIKE"), p.isWord("ILIKE"):
+		keyword := strings.ToUpper(p.peek().text)
+		p.pos++
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		if p.isWord("ESCAPE") {
+			return nil, p.errorf("unsupported ESCAPE")
+		}
+		// LikeNode renders ILIKE for postgres and LIKE otherwise
+		if keyword == "ILIKE" != (p.adapter == POSTGRES) {
+			return infix(left, joinKeywords("", not, keyword), right), nil
+		}
+		if not {
+			return Unlike(left, right), nil
+		}
+		return Like(left, right), nil
+
+	case p.word("BETWEEN"):
+		low, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectWord("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return infix(left, joinKeywords("", not, "BETWEEN"), infix(low, "AND", high)), nil
+	}
+	return left, nil
+}
+
+// joinKeywords joins e.g. IS NOT DISTINCT FROM.
+func joinKeywords(before string, not bool, after string) string {
+	parts := []string{}
+	if before != "" {
+		parts = append(parts, before)
+	}
+	if not {
+		parts = append(parts, "NOT")
+	}
+	if after != "" {
+		parts = append(parts, after)
+	}
+	return strings.Join(parts, " ")
+}
+
+// operand parses operands combined by operators other than comparisons e.g. + or ||.
+func (p *sqlParser) operand() (interface{}, error) {
+	left, err := p.unary()
+	for err == nil {
+		tok := p.peek()
+		if tok.kind != sqlOperator || sqlComparisons[tok.text] != nil {
+			break
+		}
+		p.pos++
+		var right interface{}
+		if right, err = p.unary(); err == nil {
+			left = infix(left, escapeQuestion(tok.text), right)
+		}
+	}
+	return left, err
+}
+
+// unary parses an operand with an optional sign, a cast, a subscript or a COLLATE clause.
+func (p *sqlParser) unary() (interface{}, error) {
+	start := p.mark()
+	if tok := p.peek(); tok.kind == sqlOperator && (tok.text == "-" || tok.text == "+" || tok.text == "~") {
+		p.pos++
+		if _, err := p.unary(); err != nil {
+			return nil, err
+		}
+		return p.rawFrom(start)
+	}
+
+	expr, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	raw := false
+	for {
+		switch {
+		case p.operator("::"):
+			if _, err = p.identifier(); err != nil {
+				return nil, err
+			}
+			if p.isPunct("(") {
+				p.pos++
+				if err = p.skipGroup(); err != nil {
+					return nil, err
+				}
+			}
+		case p.isPunct("["):
+			p.pos++
+			if err = p.skipGroup(); err != nil {
+				return nil, err
+			}
+		case p.word("COLLATE"):
+			if _, err = p.identifier(); err != nil {
+				return nil, err
+			}
+		default:
+			if raw {
+				return p.rawFrom(start)
+			}
+			return expr, nil
+		}
+		raw = true
+	}
+}
+
+// primary parses a placeholder, a constant, a column, a function call, a sub select or a parenthesized expression.
+func (p *sqlParser) primary() (interface{}, error) {
+	start := p.mark()
+	tok := p.peek()
+	switch tok.kind {
+	case sqlParam:
+		p.pos++
+		return p.bind(tok)
+
+	case sqlNumber, sqlString:
+		p.pos++
+		return Literal(tok.text), nil
+
+	case sqlOperator:
+		if tok.text == "*" {
+			p.pos++
+			return Star(), nil
+		}
+
+	case sqlPunct:
+		if tok.text != "(" {
+			break
+		}
+		p.pos++
+		var expr interface{}
+		var err error
+		if p.isWord("SELECT") {
+			expr, err = p.selectStatement()
+		} else {
+			expr, err = p.expr()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.isPunct(",") { // row constructor e.g. (a, b) IN (...)
+			p.pos = start.pos + 1
+			if err = p.skipGroup(); err != nil {
+				return nil, err
+			}
+			return p.rawFrom(start)
+		}
+		return Grouping(expr), p.expectPunct(")")
+
+	case sqlWord:
+		keyword := strings.ToUpper(tok.text)
+		switch {
+		case sqlConstants[keyword]:
+			p.pos++
+			return Literal(keyword), nil
+		case keyword == "CASE":
+			for depth := 0; p.pos < len(p.tokens); {
+				if p.isWord("CASE") {
+					depth++
+				} else if p.isWord("END") {
+					depth--
+				}
+				p.pos++
+				if depth == 0 {
+					return p.rawFrom(start)
+				}
+			}
+			return nil, p.errorf("expected END")
+		case keyword == "ARRAY" && p.peekAt(1).text == "[":
+			p.pos += 2
+			if err := p.skipGroup(); err != nil {
+				return nil, err
+			}
+			return p.rawFrom(start)
+		case p.peekAt(1).kind == sqlString && !sqlKeywords[keyword]:
+			// typed constant e.g. DATE '2020-01-01' or INTERVAL '1 day'
+			p.pos += 2
+			return p.rawFrom(start)
+		case sqlKeywords[keyword] && keyword != "LEFT" && keyword != "RIGHT":
+			return nil, p.errorf("unexpected %s", keyword)
+		}
+		if p.peekAt(1).text == "(" {
+			return p.function()
+		}
+		return p.columnRef()
+
+	case sqlIdentifier:
+		return p.columnRef()
+	}
+	return nil, p.errorf("expected expression")
+}
+
+// function parses a function call e.g. COUNT(*), COUNT(DISTINCT x) or LOWER(x).
+// Calls with other arguments e.g. CAST(x AS int) and window functions are kept as LiteralNode.
+func (p *sqlParser) function() (interface{}, error) {
+	start := p.mark()
+	fn := &FunctionNode{Name: p.peek().text, Args: []interface{}{}}
+	p.pos += 2
+
+	var err error
+	switch {
+	case p.operator("*"):
+		fn.Args = nil
+	case p.isPunct(")"):
+	default:
+		fn.Distinct = p.word("DISTINCT")
+		if p.isWord("SELECT") {
+			var sub *SelectStatementNode
+			sub, err = p.selectStatement()
+			fn.Args = []interface{}{sub}
+		} else {
+			fn.Args, err = p.list(p.expr)
+		}
+	}
+	if err == nil && p.punct(")") && !p.isWord("OVER") && !p.isWord("FILTER") && !p.isWord("WITHIN") {
+		return fn, nil
+	}
+
+	// keep the call as it is
+	p.pos = start.pos + 2
+	if err = p.skipGroup(); err != nil {
+		return nil, err
+	}
+	for _, keyword := range []string{"WITHIN", "FILTER", "OVER"} {
+		if p.word(keyword) {
+			if keyword == "WITHIN" {
+				p.word("GROUP")
+			}
+			if p.isPunct("(") {
+				p.pos++
+				if err = p.skipGroup(); err != nil {
+					return nil, err
+				}
+			} else if _, err = p.identifier(); err != nil { // named window
+				return nil, err
+			}
+		}
+	}
+	return p.rawFrom(start)
+}
+
+// columnRef parses a column, optionally qualified by its table e.g. "users"."id" or users.*.
+func (p *sqlParser) columnRef() (interface{}, error) {
+	names := []string{}
+	for {
+		if 0 < len(names) && p.operator("*") {
+			return Attribute(Star(), p.table(names)), nil
+		}
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.punct(".") {
+			break
+		}
+	}
+	if 4 < len(names) {
+		return nil, p.errorf("too many qualifiers")
+	}
+
+	column := Column(names[len(names)-1])
+	if 1 == len(names) {
+		return column, nil
+	}
+	return Attribute(column, p.table(names[:len(names)-1])), nil
+}
+
+// tableName parses a table name, optionally qualified by schema and catalog.
+func (p *sqlParser) tableName() (*TableNode, error) {
+	names := []string{}
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.punct(".") {
+			break
+		}
+	}
+	if 3 < len(names) {
+		return nil, p.errorf("too many qualifiers")
+	}
+	return p.table(names), nil
+}
+
+// table returns the table or alias of the qualified names,
+// tables referenced by columns before the FROM clause are the ones of the FROM clause.
+func (p *sqlParser) table(names []string) *TableNode {
+	key := strings.Join(names, ".")
+	if table, ok := p.tables[key]; ok {
+		return table
+	}
+	n := len(names)
+	table := p.db(names[n-1]).Table
+	if 1 < n {
+		table.Schema = names[n-2]
+	}
+	if 2 < n {
+		table.Catalog = names[n-3]
+	}
+	p.tables[key] = table
+	return table
+}
+
+// aliasTable returns the table named by an alias, it is not qualified by a schema.
+func (p *sqlParser) aliasTable(alias string) *TableNode {
+	table := p.table([]string{alias})
+	table.Schema = ""
+	table.Catalog = ""
+	return table
+}
+
+// identifier parses a quoted or unquoted identifier, unquoted ones are folded to lower case for POSTGRES.
+func (p *sqlParser) identifier() (string, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case sqlIdentifier:
+		p.pos++
+		return tok.text, nil
+	case sqlWord:
+		p.pos++
+		if p.adapter == POSTGRES {
+			return strings.ToLower(tok.text), nil
+		}
+		return tok.text, nil
+	}
+	return "", p.errorf("expected identifier")
+}
+
+// bind returns the argument of the placeholder.
+func (p *sqlParser) bind(tok sqlToken) (interface{}, error) {
+	index := p.next
+	if tok.text == "?" {
+		p.next++
+	} else {
+		index, _ = strconv.Atoi(tok.text[1:])
+		index--
+	}
+	if index < 0 || index >= len(p.args) {
+		return nil, fmt.Errorf("%w: %d arguments for %d placeholders", ErrArgumentCount, len(p.args), p.placeholders())
+	}
+	p.used[index] = true
+	return p.args[index], nil
+}
+
+// infix returns `left op right` for operators without node of their own e.g. || or @>.
+func infix(left interface{}, op string, right interface{}) *BinaryLiteralNode {
+	return &BinaryLiteralNode{Left: left, Right: &BinaryLiteralNode{Left: Literal(op), Right: right}}
+}
+
+// escapeQuestion escapes the JSONB operator "?" for the placeholder collectors, see questionAt.
+func escapeQuestion(op string) string {
+	if op == "?" {
+		return "??"
+	}
+	return op
+}
+
+// End expressions.
+
+// Begin token helpers.
+
+func (p *sqlParser) mark() sqlMark {
+	return sqlMark{pos: p.pos, next: p.next}
+}
+
+// rawFrom returns the tokens from start up to the current one as LiteralNode, kept as they are
+// except for placeholders which are bound again.
+func (p *sqlParser) rawFrom(start sqlMark) (*LiteralNode, error) {
+	p.next = start.next
+	var b strings.Builder
+	var args []interface{}
+	for i := start.pos; i < p.pos; i++ {
+		tok := p.tokens[i]
+		if i > start.pos && tok.start > p.tokens[i-1].end {
+			b.WriteByte(SPACE)
+		}
+		switch tok.kind {
+		case sqlParam:
+			arg, err := p.bind(tok)
+			if err != nil {
+				return nil, err
+			}
+			b.WriteByte(QUESTION)
+			args = append(args, arg)
+		case sqlOperator:
+			b.WriteString(escapeQuestion(tok.text))
+		default:
+			b.WriteString(p.src[tok.start:tok.end])
+		}
+	}
+	return &LiteralNode{Sql: b.String(), Args: args}, nil
+}
+
+// skipGroup advances after the parenthesis or bracket closing the one before the current token.
+func (p *sqlParser) skipGroup() error {
+	for depth := 1; p.pos < len(p.tokens); p.pos++ {
+		if tok := p.tokens[p.pos]; tok.kind == sqlPunct {
+			switch tok.text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				if depth--; depth == 0 {
+					p.pos++
+					return nil
+				}
+			}
+		}
+	}
+	return p.errorf("expected ')'")
+}
+
+func (p *sqlParser) peek() sqlToken {
+	return p.peekAt(0)
+}
+
+func (p *sqlParser) peekAt(n int) sqlToken {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return sqlToken{}
+}
+
+// isWord is true if the current token is the keyword.
+func (p *sqlParser) isWord(keyword string) bool {
+	return p.isWordAt(0, keyword)
+}
+
+func (p *sqlParser) isWordAt(n int, keyword string) bool {
+	tok := p.peekAt(n)
+	return tok.kind == sqlWord && strings.EqualFold(tok.text, keyword)
+}
+
+// word consumes the next token if it is the keyword.
+func (p *sqlParser) word(keyword string) bool {
+	return p.words(keyword)
+}
+
+// words consumes the next tokens if they are the keywords.
+func (p *sqlParser) words(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !p.isWordAt(i, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *sqlParser) expectWord(keyword string) error {
+	if !p.word(keyword) {
+		return p.errorf("expected %s", keyword)
+	}
+	return nil
+}
+
+func (p *sqlParser) isPunct(s string) bool {
+	tok := p.peek()
+	return tok.kind == sqlPunct && tok.text == s
+}
+
+func (p *sqlParser) punct(s string) bool {
+	if p.isPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectPunct(s string) error {
+	if !p.punct(s) {
+		return p.errorf("expected '%s'", s)
+	}
+	return nil
+}
+
+func (p *sqlParser) operator(s string) bool {
+	if tok := p.peek(); tok.kind == sqlOperator && tok.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+	near := "end of statement"
+	if p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		near = fmt.Sprintf("%q", p.src[tok.start:tok.end])
+	}
+	return fmt.Errorf("%w: %s near %s", ErrSyntax, fmt.Sprintf(format, args...), near)
+}
+
+// End token helpers.
@@ -0,0 +1,302 @@
+package codex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlRenderer interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// parseArgs returns the args 1, 2 ... n for the n placeholders of sql.
+func parseArgs(db DbDialect, sql string) []interface{} {
+	n, _ := db.Placeholders(sql)
+	var args []interface{}
+	for i := 1; i <= n; i++ {
+		args = append(args, i)
+	}
+	return args
+}
+
+// TestParseRoundTrip parses SQL rendered by the visitors and renders it again.
+func TestParseRoundTrip(t *testing.T) {
+	generic, psql, mysql := Dialect(0), Dialect(POSTGRES), Dialect(MYSQL)
+	tests := []struct {
+		db   DbDialect
+		sql  string
+		want string // empty if the sql is rendered as it is
+	}{
+		{generic, `SELECT "foo".* FROM "foo"`, ``},
+		{generic, `SELECT * FROM "foo"`, ``},
+		{generic, `SELECT "foo"."id","company","bar"."name" FROM "foo"`, ``},
+		{generic, `SELECT "foo"."id","name",age FROM "foo" WHERE ("foo"."owner_id"=?)`,
+			`SELECT "foo"."id","name","age" FROM "foo" WHERE ("foo"."owner_id"=?)`},
+		{generic, `SELECT "foo".* FROM "foo" GROUP BY "foo"."id","foo"."bar_id"`, ``},
+		{generic, `SELECT "foo".* FROM "foo" WHERE ("foo"."owner_id"=?) HAVING "foo"."id"`, ``},
+		{generic, `SELECT "foo".* FROM "foo" INNER JOIN "bar" WHERE ("foo"."owner_id"=?)`, ``},
+		{generic, `SELECT "foo".* FROM "foo" LEFT OUTER JOIN "bar"`, ``},
+		{generic, `SELECT "foo".* FROM "foo" ORDER BY "foo"."group_id" DESC,"foo"."name" ASC`, ``},
+		{generic, `SELECT "foo".* FROM "foo" WHERE ("foo"."owner_id"=?) AND ("foo"."id"=?)`, ``},
+		{generic, `SELECT "foo".* FROM "foo" WHERE (a = ? AND b = ?)`, `SELECT "foo".* FROM "foo" WHERE ("a"=? AND "b"=?)`},
+		{generic, `SELECT "id","name" FROM "foo" INNER JOIN "bar" ON bar.id=foo.bar_id WHERE "foo"."id"=? AND "foo"."name" IS NOT NULL`,
+			`SELECT "id","name" FROM "foo" INNER JOIN "bar" ON "bar"."id"="foo"."bar_id" WHERE ("foo"."id"=?) AND ("foo"."name" IS NOT NULL)`},
+		{generic, `SELECT "t"."user_id","t"."total" FROM (SELECT "orders"."user_id",SUM("orders"."total") AS "total" FROM "orders" WHERE ("orders"."state"=?) GROUP BY "orders"."user_id") AS "t" WHERE ("t"."total">?)`, ``},
+		{generic, `SELECT "t".* FROM (SELECT * FROM "orders" WHERE (id > ?)) AS "t"`,
+			`SELECT "t".* FROM (SELECT * FROM "orders" WHERE ("id">?)) AS "t"`},
+		{generic, `SELECT "users".* FROM "users" INNER JOIN (SELECT "orders"."user_id" FROM "orders" WHERE ("orders"."total">?)) AS "big" ON "big"."user_id"="users"."id" WHERE ("users"."active"=?)`, ``},
+		{generic, `SELECT "v"."id" FROM (VALUES (?,?),(?,?)) AS "v"("id","qty") WHERE ("v"."qty">?)`, ``},
+		{generic, `SELECT "users"."id" FROM "users" EXCEPT (SELECT "admins"."id" FROM "admins" ORDER BY id LIMIT ?)`,
+			`SELECT "users"."id" FROM "users" EXCEPT (SELECT "admins"."id" FROM "admins" ORDER BY "id" LIMIT ?)`},
+		{generic, `SELECT "users"."id" FROM "users" WHERE (active = ?) UNION SELECT "admins"."id" FROM "admins" WHERE (level > ?) UNION ALL SELECT "guests"."id" FROM "guests" ORDER BY id LIMIT ? OFFSET ?`,
+			`SELECT "users"."id" FROM "users" WHERE ("active"=?) UNION SELECT "admins"."id" FROM "admins" WHERE ("level">?) UNION ALL SELECT "guests"."id" FROM "guests" ORDER BY "id" LIMIT ? OFFSET ?`},
+		{generic, `SELECT * FROM "table_one" INTERSECT (SELECT * FROM "table_two" INTERSECT SELECT * FROM "table_three")`, ``},
+		{generic, `SELECT * FROM "table_one" INTERSECT ALL SELECT * FROM "table_two" EXCEPT ALL SELECT * FROM "table_three"`, ``},
+		{generic, `SELECT * FROM "table_one" UNION (SELECT * FROM "table_two" LIMIT ?)`, ``},
+		{generic, `SELECT COUNT("id") FROM "foo" WHERE ("foo"."id"=?)`, ``},
+		{generic, `SELECT COUNT(*) FROM "users" WHERE (id > ?)`, `SELECT COUNT(*) FROM "users" WHERE ("id">?)`},
+		{generic, `SELECT "my ""schema"""."events".* FROM "my ""schema"""."events"`, ``},
+		{generic, `SELECT "users".* FROM "users" WHERE (status = ? AND id IN(?,?) OR prev = ?)`,
+			`SELECT "users".* FROM "users" WHERE ("status"=? AND "id" IN(?,?) OR "prev"=?)`},
+		{generic, `SELECT "users".* FROM "users" WHERE ("users"."name"='O''Hara') AND (tags @> ARRAY['a','b']) LIMIT 3`,
+			`SELECT "users".* FROM "users" WHERE ("users"."name"='O''Hara') AND ("tags" @> ARRAY['a','b']) LIMIT 3`},
+		{generic, `SELECT "users".* FROM "users" WHERE ("users"."owner_id"=?) AND ("users"."active") AND (id = ?)`,
+			`SELECT "users".* FROM "users" WHERE ("users"."owner_id"=?) AND ("users"."active") AND ("id"=?)`},
+		{generic, `DELETE FROM "users"  LIMIT ?`, ``},
+		{generic, `DELETE FROM "users" WHERE ("users"."active"=?) AND ("users"."id"=?)`, ``},
+		{generic, `DELETE FROM "users" WHERE ("users"."owner_id"=?) AND (id > ?) LIMIT ?`,
+			`DELETE FROM "users" WHERE ("users"."owner_id"=?) AND ("id">?) LIMIT ?`},
+		{generic, `DELETE FROM "warehouse"."analytics"."events" WHERE ("warehouse"."analytics"."events"."id"=?)`, ``},
+		{generic, `INSERT INTO "users" ("id","name") VALUES (?,?),(?,?),(?,?)`, ``},
+		{generic, `INSERT INTO "users" ("name") VALUES (?) RETURNING "id"`, ``},
+		{generic, `INSERT INTO "users" ("name","email") VALUES ('x',NULL)`, ``},
+		{generic, `INSERT INTO "foo" VALUES (?)`, ``},
+		{generic, `UPDATE "foo" SET "id"=? WHERE ("foo"."owner_id"=?)`, ``},
+		{generic, `UPDATE "table" SET "name"=?,"enabled"=?  LIMIT ?`, ``},
+		{generic, `UPDATE "users" SET "name"=? `, ``},
+		{generic, `UPDATE "users" SET "id"=? WHERE ("users"."owner_id"=?) AND ("users"."active") AND (id = ?) LIMIT ?`,
+			`UPDATE "users" SET "id"=? WHERE ("users"."owner_id"=?) AND ("users"."active") AND ("id"=?) LIMIT ?`},
+
+		{psql, `SELECT "items"."id","v"."qty" FROM "items" INNER JOIN (VALUES (CAST($1 AS bigint),CAST($2 AS int)),(CAST($3 AS bigint),CAST($4 AS int))) AS "v"("id","qty") ON "v"."id"="items"."id" WHERE ("items"."active"=$5)`, ``},
+		{psql, `SELECT "users"."id" FROM "users" LEFT OUTER JOIN (SELECT * FROM "orders" WHERE (total > $1)) AS "o" ON "o"."user_id"="users"."id" WHERE (users.id > $2) LIMIT $3`,
+			`SELECT "users"."id" FROM "users" LEFT OUTER JOIN (SELECT * FROM "orders" WHERE ("total">$1)) AS "o" ON "o"."user_id"="users"."id" WHERE ("users"."id">$2) LIMIT $3`},
+		{psql, `SELECT "users".* FROM "users" WHERE ("users"."active"=$1) AND (ST_DWithin("users"."location",$2,$3))`, ``},
+		{psql, `SELECT "users"."id","users"."email" FROM "users" INNER JOIN "posts" ON "posts"."user_id"="users"."id" WHERE ("posts"."title" ILIKE $1)`, ``},
+		{psql, `SELECT "products".* FROM "products" WHERE ("products"."tags" @> ARRAY[$1,$2,$3])`, ``},
+		{psql, `SELECT "products".* FROM "products" WHERE (attrs ? 'color' AND attrs ?| array['a','b?'] AND name <> '?' AND id IN($1,$2))`,
+			`SELECT "products".* FROM "products" WHERE ("attrs" ? 'color' AND "attrs" ?| array['a','b?'] AND "name"!='?' AND "id" IN($1,$2))`},
+		{psql, `SELECT "users"."id" FROM "users" WHERE (a = $1) INTERSECT ALL SELECT "admins"."id" FROM "admins" WHERE (b = $2) LIMIT $3`,
+			`SELECT "users"."id" FROM "users" WHERE ("a"=$1) INTERSECT ALL SELECT "admins"."id" FROM "admins" WHERE ("b"=$2) LIMIT $3`},
+		{psql, `INSERT INTO "analytics"."events" ("id") VALUES ($1)`, ``},
+		{psql, `UPDATE "items" SET "qty"="v"."qty" FROM (VALUES ($1,$2),($3,$4)) AS "v"("id","qty") WHERE ("items"."id"="v"."id") AND (items.locked = $5)`,
+			`UPDATE "items" SET "qty"="v"."qty" FROM (VALUES ($1,$2),($3,$4)) AS "v"("id","qty") WHERE ("items"."id"="v"."id") AND ("items"."locked"=$5)`},
+
+		{mysql, "SELECT `items`.* FROM `items` INNER JOIN (VALUES ROW(?,?),ROW(?,?)) AS `v`(`id`,`qty`) ON `v`.`id`=`items`.`id`", ``},
+		{mysql, "SELECT `t`.`id` FROM (SELECT `orders`.`id` FROM `orders`) AS `t`", ``},
+		{mysql, "SELECT `users`.* FROM `users` WHERE (status = ? AND id IN(?,?) OR prev = ?)",
+			"SELECT `users`.* FROM `users` WHERE (`status`=? AND `id` IN(?,?) OR `prev`=?)"},
+		{mysql, "INSERT INTO `users` (`name`,`nick`,`age`) VALUES (?,DEFAULT,?),(?,?,?)", ``},
+		{mysql, "UPDATE `items`,(VALUES ROW(CAST(? AS SIGNED),CAST(? AS SIGNED))) AS `v`(`id`,`qty`) SET `items`.`qty`=`v`.`qty` WHERE (`items`.`id`=`v`.`id`)", ``},
+		{mysql, "UPDATE `shop`.`users` SET `name`=? WHERE (`shop`.`users`.`id`=?)", ``},
+		{mysql, "UPDATE `users` SET `name`='a\\\\b' WHERE (`users`.`id`=1)", ``},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			want := test.want
+			if want == "" {
+				want = test.sql
+			}
+			args := parseArgs(test.db, test.sql)
+
+			m, err := test.db.Parse(test.sql, args...)
+			if !assert.Nil(t, err) {
+				return
+			}
+			sql, bound, err := m.(sqlRenderer).ToSql()
+			assert.Nil(t, err)
+			assert.Equal(t, want, sql)
+			assert.Equal(t, len(args), len(bound))
+			if 0 < len(args) {
+				assert.Equal(t, args, bound)
+			}
+
+			// the rendered sql is a fixed point
+			m, err = test.db.Parse(sql, bound...)
+			assert.Nil(t, err)
+			again, _, _ := m.(sqlRenderer).ToSql()
+			assert.Equal(t, sql, again)
+		})
+	}
+}
+
+func TestParseRewrite(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	tenant := func(s Scoper) {
+		s.Scope(psql.Table("users").Col("tenant_id").Eq(7))
+	}
+
+	m, err := psql.ParseSelect("SELECT id, name FROM users WHERE active = $1 OR admin = $1 ORDER BY name", true)
+	assert.Nil(t, err)
+	sql, args, err := m.Scopes(tenant).Limit(10).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "id","name" FROM "users" WHERE ("active"=$1 OR "admin"=$2) AND ("users"."tenant_id"=$3) ORDER BY "name" LIMIT $4`, sql)
+	assert.Equal(t, []interface{}{true, true, 7, 10}, args)
+
+	// columns of the parsed table
+	m, err = psql.ParseSelect("SELECT * FROM users")
+	assert.Nil(t, err)
+	sql, _, err = m.Where(m.Table().Col("id").In(1, 2)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE ("users"."id" IN($1,$2))`, sql)
+
+	u, err := psql.ParseUpdate("UPDATE users SET name = 'x' WHERE id = $1", 3)
+	assert.Nil(t, err)
+	sql, _, err = u.Scopes(tenant).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"='x' WHERE ("id"=$1) AND ("users"."tenant_id"=$2)`, sql)
+
+	d, err := psql.ParseDelete("DELETE FROM users")
+	assert.Nil(t, err)
+	sql, _, err = d.Scopes(tenant).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."tenant_id"=$1)`, sql)
+
+	i, err := psql.ParseInsert("INSERT INTO users (name) VALUES ($1)", "Jon")
+	assert.Nil(t, err)
+	sql, _, err = i.Row("Ann").Returning("id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("name") VALUES ($1),($2) RETURNING "id"`, sql)
+}
+
+func TestParseTables(t *testing.T) {
+	psql := Dialect(POSTGRES)
+
+	// aliases, columns before the FROM clause refer to its tables
+	m, err := psql.ParseSelect("SELECT u.id, c.name FROM users u LEFT JOIN companies c ON c.id = u.company_id")
+	assert.Nil(t, err)
+	sql, _, _ := m.ToSql()
+	assert.Equal(t, `SELECT "u"."id","c"."name" FROM "users" AS "u" LEFT OUTER JOIN "companies" AS "c" ON "c"."id"="u"."company_id"`, sql)
+	assert.Equal(t, "u", m.Table().Name)
+
+	// unquoted identifiers are folded to lower case for postgres only
+	m, _ = psql.ParseSelect(`SELECT Users.Name, "Nick" FROM Public.Users`)
+	sql, _, _ = m.ToSql()
+	assert.Equal(t, `SELECT "users"."name","Nick" FROM "public"."users"`, sql)
+	m, _ = Dialect(MYSQL).ParseSelect("SELECT Users.Name FROM Users")
+	sql, _, _ = m.ToSql()
+	assert.Equal(t, "SELECT `Users`.`Name` FROM `Users`", sql)
+
+	// tables of the dialect
+	m, _ = psql.InSchema("app").ParseSelect("SELECT users.id FROM users")
+	sql, _, _ = m.ToSql()
+	assert.Equal(t, `SELECT "app"."users"."id" FROM "app"."users"`, sql)
+	assert.Equal(t, "app", m.Table().Schema)
+
+	// no FROM
+	m, _ = psql.ParseSelect("SELECT 1")
+	sql, _, _ = m.ToSql()
+	assert.Equal(t, `SELECT 1`, sql)
+}
+
+func TestParseExpressions(t *testing.T) {
+	psql, mysql := Dialect(POSTGRES), Dialect(MYSQL)
+	tests := []struct {
+		db   DbDialect
+		sql  string
+		want string
+	}{
+		{psql, `SELECT DISTINCT name FROM users`, `SELECT DISTINCT "name" FROM "users"`},
+		{psql, `SELECT DISTINCT ON (email) email, id FROM users`, `SELECT DISTINCT ON (email) "email","id" FROM "users"`},
+		{psql, `SELECT COUNT(DISTINCT email) AS n, now() FROM users`, `SELECT COUNT(DISTINCT "email") AS "n",now() FROM "users"`},
+		{psql, `SELECT id FROM users WHERE NOT (a = 1 OR b IS NULL)`, `SELECT "id" FROM "users" WHERE (NOT("a"=1 OR "b" IS NULL))`},
+		{psql, `SELECT id FROM users WHERE id NOT IN (1, 2) AND id IN (SELECT user_id FROM posts)`,
+			`SELECT "id" FROM "users" WHERE (NOT("id" IN(1,2))) AND ("id" IN(SELECT "user_id" FROM "posts"))`},
+		{psql, `SELECT id FROM users WHERE EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)`,
+			`SELECT "id" FROM "users" WHERE (EXISTS(SELECT 1 FROM "posts" WHERE ("posts"."user_id"="users"."id")))`},
+		{psql, `SELECT id FROM users WHERE age BETWEEN 18 AND 65 AND name LIKE 'J%' AND nick NOT ILIKE 'x'`,
+			`SELECT "id" FROM "users" WHERE ("age" BETWEEN 18 AND 65) AND ("name" LIKE 'J%') AND ("nick" NOT ILIKE 'x')`},
+		{mysql, `SELECT id FROM users WHERE name LIKE 'J%' AND nick NOT LIKE 'x'`,
+			"SELECT `id` FROM `users` WHERE (`name` LIKE 'J%') AND (`nick` NOT LIKE 'x')"},
+		{psql, `SELECT id::text, CAST(age AS int), CASE WHEN age > 17 THEN 'adult' ELSE 'minor' END AS kind FROM users`,
+			`SELECT id::text,CAST(age AS int),CASE WHEN age > 17 THEN 'adult' ELSE 'minor' END AS "kind" FROM "users"`},
+		{psql, `SELECT id FROM users WHERE created_at > now() - INTERVAL '1 day' AND -score < 0 AND deleted IS NOT TRUE`,
+			`SELECT "id" FROM "users" WHERE ("created_at">now() - INTERVAL '1 day') AND (-score<0) AND ("deleted" IS NOT TRUE)`},
+		{psql, `SELECT first || ' ' || last AS name, rank() OVER (ORDER BY score) FROM users ORDER BY score DESC NULLS LAST LIMIT 5 OFFSET 10`,
+			`SELECT "first" || ' ' || "last" AS "name",rank() OVER (ORDER BY score) FROM "users" ORDER BY score DESC NULLS LAST LIMIT 5 OFFSET 10`},
+		{mysql, `SELECT id FROM users LIMIT 10, 5 # comment`, "SELECT `id` FROM `users` LIMIT 5 OFFSET 10"},
+		{psql, `INSERT INTO users (name) VALUES ($$it's$$) RETURNING id, name;`, `INSERT INTO "users" ("name") VALUES ($$it's$$) RETURNING id, name`},
+	}
+
+	for _, test := range tests {
+		m, err := test.db.Parse(test.sql)
+		if !assert.Nil(t, err, test.sql) {
+			continue
+		}
+		sql, _, err := m.(sqlRenderer).ToSql()
+		assert.Nil(t, err)
+		assert.Equal(t, test.want, sql)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	psql := Dialect(POSTGRES)
+
+	m, err := psql.ParseSelect("SELECT id FROM users WHERE a = $2 AND b = $1 AND c = $2", "x", "y")
+	assert.Nil(t, err)
+	sql, args, _ := m.ToSql()
+	assert.Equal(t, `SELECT "id" FROM "users" WHERE ("a"=$1) AND ("b"=$2) AND ("c"=$3)`, sql)
+	assert.Equal(t, []interface{}{"y", "x", "y"}, args)
+
+	// args of literals are kept
+	m, err = psql.ParseSelect("SELECT id FROM users WHERE a = CAST($1 AS int) AND b = $2", 1, 2)
+	assert.Nil(t, err)
+	sql, args, _ = m.ToSql()
+	assert.Equal(t, `SELECT "id" FROM "users" WHERE ("a"=CAST($1 AS int)) AND ("b"=$2)`, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	n, err := psql.Placeholders("SELECT id FROM users WHERE a = $3 AND b = $1 AND c = '$4'")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	n, _ = Dialect(MYSQL).Placeholders("SELECT id FROM users WHERE a = ? AND b IN (?, ?) AND c = '?'")
+	assert.Equal(t, 3, n)
+
+	_, err = psql.ParseSelect("SELECT id FROM users WHERE a = $1 AND b = $2", 1)
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+	_, err = psql.ParseSelect("SELECT id FROM users WHERE a = $1", 1, 2)
+	assert.EqualError(t, err, "wrong number of arguments: 2 arguments for 1 placeholders")
+	_, err = Dialect(MYSQL).ParseSelect("SELECT id FROM users WHERE a = ? AND b IN (?, ?)", 1, 2)
+	assert.True(t, errors.Is(err, ErrArgumentCount))
+}
+
+func TestParseErrors(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	tests := []struct {
+		sql string
+		err string
+	}{
+		{`SELEC id FROM users`, `syntax error: expected SELECT, INSERT, UPDATE or DELETE near "SELEC"`},
+		{`SELECT id FROM users x y`, `syntax error: expected end of statement near "y"`},
+		{`SELECT id FROM users RIGHT JOIN posts ON true`, `syntax error: unsupported join, use [INNER] JOIN or LEFT [OUTER] JOIN near "RIGHT"`},
+		{`SELECT id FROM users, posts`, `syntax error: unsupported join, use [INNER] JOIN or LEFT [OUTER] JOIN near ","`},
+		{`SELECT id FROM users WHERE (a = 1`, `syntax error: expected ')' near end of statement`},
+		{`SELECT id FROM users WHERE name = 'x`, `syntax error: unterminated quote '`},
+		{`SELECT id FROM (SELECT id FROM users)`, `syntax error: expected alias of sub select near end of statement`},
+		{`SELECT id FROM users WHERE`, `syntax error: expected expression near end of statement`},
+		{"SELECT `id` FROM users", "syntax error: unexpected quote `"},
+		{`INSERT INTO users (id) SELECT 1`, `syntax error: expected VALUES near "SELECT"`},
+		{`WITH x AS (SELECT 1) SELECT * FROM x`, `syntax error: expected SELECT, INSERT, UPDATE or DELETE near "WITH"`},
+	}
+	for _, test := range tests {
+		_, err := psql.Parse(test.sql)
+		assert.EqualError(t, err, test.err, test.sql)
+		assert.True(t, errors.Is(err, ErrSyntax))
+	}
+
+	_, err := psql.ParseSelect("DELETE FROM users")
+	assert.EqualError(t, err, "syntax error: expected SELECT statement")
+	_, err = psql.ParseInsert("SELECT 1")
+	assert.EqualError(t, err, "syntax error: expected INSERT statement")
+}
@@ -0,0 +1,156 @@
+package codex
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StructField is a field of a struct mapped to a column by its `db` tag, see StructFields.
+type StructField struct {
+	Column    string
+	Index     []int // index sequence of the field within the struct, see reflect.Value.FieldByIndex
+	OmitEmpty bool  // not written when zero
+	ReadOnly  bool  // never written e.g. columns with database defaults
+	PK        bool  // primary key, not inserted when zero, the WHERE condition of UpdateManager.SetStruct
+}
+
+var structFieldsCache sync.Map // reflect.Type -> []StructField
+
+// StructFields returns the column fields of struct type t as InsertManager.FromStruct,
+// UpdateManager.SetStruct and the scanners of package run map them:
+//
+//	type User struct {
+//		Id        int64     `db:"id,pk"`
+//		Name      string    `db:"name"`
+//		Nick      string    `db:"nick,omitempty"`
+//		CreatedAt time.Time `db:"created_at,readonly"`
+//		Password  string    `db:"-"`
+//		Audit               // fields of embedded structs are included unless shadowed
+//	}
+//
+// Untagged fields map to their lower case name. Like Go's promoted fields, a field shadows
+// the fields of the same column nested deeper in embedded structs, at the same depth the first one wins.
+// Embedded time.Time, sql.Scanner and driver.Valuer types are single columns,
+// embedded pointers to unexported struct types are ignored.
+// The result is cached per type and must not be modified.
+func StructFields(t reflect.Type) []StructField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]StructField)
+	}
+
+	var fields []StructField
+	seen := map[string]bool{}
+	expanded := map[reflect.Type]bool{t: true}
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	// breadth first, so outer fields come before the ones of embedded structs
+	for level := []embedded{{t: t}}; 0 < len(level); {
+		var next []embedded
+		for _, e := range level {
+			for i := 0; i < e.t.NumField(); i++ {
+				f := e.t.Field(i)
+				opts := strings.Split(f.Tag.Get("db"), ",")
+				if opts[0] == "-" {
+					continue
+				}
+				index := append(e.index[:len(e.index):len(e.index)], i)
+				if ft, ok := embeddedStruct(f, opts[0]); ok {
+					// nil pointers to unexported embedded structs can not be allocated
+					if !expanded[ft] && (f.Type.Kind() != reflect.Ptr || f.PkgPath == "") {
+						expanded[ft] = true
+						next = append(next, embedded{t: ft, index: index})
+					}
+					continue
+				}
+				if f.PkgPath != "" { // unexported
+					continue
+				}
+
+				field := StructField{Column: opts[0], Index: index}
+				if field.Column == "" {
+					field.Column = strings.ToLower(f.Name)
+				}
+				for _, opt := range opts[1:] {
+					switch opt {
+					case "omitempty":
+						field.OmitEmpty = true
+					case "readonly":
+						field.ReadOnly = true
+					case "pk":
+						field.PK = true
+					}
+				}
+				if !seen[field.Column] {
+					seen[field.Column] = true
+					fields = append(fields, field)
+				}
+			}
+		}
+		level = next
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// embeddedStruct returns the struct type of the untagged embedded field f whose fields are columns.
+func embeddedStruct(f reflect.StructField, tag string) (reflect.Type, bool) {
+	if !f.Anonymous || tag != "" {
+		return nil, false
+	}
+	ft := f.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	if ft.Kind() != reflect.Struct || ft == reflect.TypeOf(time.Time{}) ||
+		reflect.PtrTo(ft).Implements(scannerType) || reflect.PtrTo(ft).Implements(valuerType) {
+		return nil, false
+	}
+	return ft, true
+}
+
+// value returns the field of struct rv and whether it is zero.
+// Fields of nil embedded struct pointers are nil.
+func (f StructField) value(rv reflect.Value) (interface{}, bool) {
+	for i, x := range f.Index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil, true
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv.Interface(), rv.IsZero()
+}
+
+// structValue returns the struct of o, a struct or a non nil pointer to one.
+func structValue(o interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(o)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv, rv.Kind() == reflect.Struct
+}
+
+// sortedKeys returns the keys of m in ascending order, so the SQL is deterministic.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
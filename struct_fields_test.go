@@ -0,0 +1,40 @@
+package codex
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldsInner struct {
+	Note string `db:"note"`
+	Id   int64  // shadowed by fieldsRow.Id
+}
+
+type fieldsDeep struct {
+	fieldsInner
+}
+
+type fieldsShallow struct {
+	Note string `db:"note,omitempty"` // shadows fieldsDeep.fieldsInner.Note despite coming later
+}
+
+type fieldsRow struct {
+	Id   int64 `db:"id,pk"`
+	Nick sql.NullString
+	fieldsDeep
+	fieldsShallow
+	sql.NullInt64
+}
+
+func TestStructFields(t *testing.T) {
+	fields := StructFields(reflect.TypeOf(fieldsRow{}))
+	assert.Equal(t, []StructField{
+		{Column: "id", Index: []int{0}, PK: true},
+		{Column: "nick", Index: []int{1}},
+		{Column: "nullint64", Index: []int{4}},
+		{Column: "note", Index: []int{3, 0}, OmitEmpty: true},
+	}, fields)
+}
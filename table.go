@@ -2,10 +2,14 @@ package codex
 
 // TableNode is a specific BinaryNode
 type TableNode struct {
-	Name    string  // Table's Name
-	Alias   *string // Table's Alias
-	Adapter adapter
-	scopes  []ScopeFunc
+	Name        string  // Table's Name
+	Alias       *string // Table's Alias
+	Schema      string  // Optional schema (MySQL: database) the table belongs to
+	Catalog     string  // Optional catalog (database) the schema belongs to
+	Adapter     adapter
+	scopes      []ScopeFunc
+	hooks       []Hook
+	definitions *Definitions // see Definitions.Define
 }
 
 func (self *TableNode) Scopes(scopes ...ScopeFunc) *TableNode {
@@ -15,6 +19,24 @@ func (self *TableNode) Scopes(scopes ...ScopeFunc) *TableNode {
 	return self
 }
 
+// Hooks adds hooks to the managers of the table, see Hook.
+func (self *TableNode) Hooks(hooks ...Hook) *TableNode {
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// InSchema sets the schema of the table e.g. renders to '"analytics"."events"' sql
+func (self *TableNode) InSchema(schema string) *TableNode {
+	self.Schema = schema
+	return self
+}
+
+// InCatalog sets the catalog of the table e.g. renders to '"warehouse"."analytics"."events"' sql
+func (self *TableNode) InCatalog(catalog string) *TableNode {
+	self.Catalog = catalog
+	return self
+}
+
 // TableNode factory method.
 func Table(name string) (relation *TableNode) {
 	relation = new(TableNode)
@@ -120,3 +142,28 @@ func (t *TableNode) Selection() *SelectManager {
 func (t *TableNode) Deletion() *DeleteManager {
 	return Deletion(t)
 }
+
+// Returns a pointer to a CreateTableManager initialized with Table
+func (t *TableNode) CreateTable() *CreateTableManager {
+	return CreateTable(t)
+}
+
+// Returns a pointer to a AlterTableManager initialized with Table
+func (t *TableNode) AlterTable() *AlterTableManager {
+	return AlterTable(t)
+}
+
+// Returns a pointer to a CreateIndexManager for the index `name` on the columns of Table
+func (t *TableNode) CreateIndex(name string, columns ...interface{}) *CreateIndexManager {
+	return CreateIndex(t, name, columns...)
+}
+
+// Returns a pointer to a DropTableManager initialized with Table
+func (t *TableNode) DropTable() *DropTableManager {
+	return DropTable(t)
+}
+
+// Returns a pointer to a DropIndexManager for the index `name` of Table
+func (t *TableNode) DropIndex(name string) *DropIndexManager {
+	return DropIndex(t, name)
+}
@@ -376,3 +376,40 @@ func TestTableColLiteral(t *testing.T) {
 	assert.Equal(t, `SELECT "products".* FROM "products" WHERE ("products"."tags" @> ARRAY[$1,$2,$3])`, sql)
 	assert.Equal(t, []interface{}{"fancy", "cheap", "retro"}, args)
 }
+
+func TestTableInSchema(t *testing.T) {
+	events := Table("events").InSchema("analytics")
+	assert.Equal(t, "analytics", events.Schema)
+
+	sql, args, err := events.Select("id").Where(events.Col("kind").Eq("click")).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "analytics"."events"."id" FROM "analytics"."events" WHERE ("analytics"."events"."kind"=?)`, sql)
+	assert.Equal(t, []interface{}{"click"}, args)
+}
+
+func TestTableInCatalog(t *testing.T) {
+	events := Table("events").InSchema("analytics").InCatalog("warehouse")
+
+	sql, args, err := events.Delete(events.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `DELETE FROM "warehouse"."analytics"."events" WHERE ("warehouse"."analytics"."events"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestTableInSchemaIsEscaped(t *testing.T) {
+	events := Table("events").InSchema(`my "schema"`)
+
+	sql, _, err := events.Selection().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "my ""schema"""."events".* FROM "my ""schema"""."events"`, sql)
+}
+
+func TestTableInSchemaJoin(t *testing.T) {
+	events := Table("events").InSchema("analytics")
+	users := Table("users")
+
+	sql, args, err := users.InnerJoin(events).On(events.Col("user_id").Eq(users.Col("id"))).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" INNER JOIN "analytics"."events" ON "analytics"."events"."user_id"="users"."id"`, sql)
+	assert.Empty(t, args)
+}
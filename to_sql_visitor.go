@@ -2,10 +2,10 @@ package codex
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 )
 
-var DEBUG = false
-
 const (
 	SPACE    = ' '
 	COMMA    = ','
@@ -32,6 +32,10 @@ const (
 
 type ToSqlVisitor struct {
 	CollectorInterface
+	Quote  byte // Identifier quote character, '"' or '`' for MySQL.
+	Strict bool // Reject unusual identifiers and reserved words, see DbDialect.Strict.
+
+	adapter adapter // dialect of the reserved words, see IsReserved
 }
 
 var _ VisitorInterface = (*ToSqlVisitor)(nil)
@@ -44,21 +48,23 @@ func NewToSqlVisitor(collectors ...CollectorInterface) *ToSqlVisitor {
 	} else {
 		collector = collectors[0]
 	}
-	return &ToSqlVisitor{collector}
+	return &ToSqlVisitor{
+		CollectorInterface: collector,
+		Quote:              QUOTE,
+	}
 }
 
+// Accept renders o and returns the SQL and its arguments.
+// On error neither SQL nor arguments are returned.
 func (v *ToSqlVisitor) Accept(o interface{}) (string, []interface{}, error) {
-	err := v.Visit(o, v)
+	if err := v.Visit(o, v); err != nil {
+		return "", nil, err
+	}
 
-	return v.String(), v.Args(), err
+	return v.String(), v.Args(), nil
 }
 
 func (v *ToSqlVisitor) Visit(o interface{}, visitor VisitorInterface) error {
-
-	if DEBUG {
-		fmt.Printf("DEBUG: Visiting %T\n", o)
-	}
-
 	switch o.(type) {
 	// Unary node visitors.
 	case *GroupingNode:
@@ -81,10 +87,6 @@ func (v *ToSqlVisitor) Visit(o interface{}, visitor VisitorInterface) error {
 		return visitor.VisitOffset(o.(*OffsetNode), visitor)
 	case *HavingNode:
 		return visitor.VisitHaving(o.(*HavingNode), visitor)
-	case *AscendingNode:
-		return visitor.VisitAscending(o.(*AscendingNode), visitor)
-	case *DescendingNode:
-		return visitor.VisitDescending(o.(*DescendingNode), visitor)
 
 	// Binary node visitors.
 	case *AsNode:
@@ -123,14 +125,24 @@ func (v *ToSqlVisitor) Visit(o interface{}, visitor VisitorInterface) error {
 		return visitor.VisitOuterJoin(o.(*OuterJoinNode), visitor)
 	case *JoinSourceNode:
 		return visitor.VisitJoinSource(o.(*JoinSourceNode), visitor)
+	case *DerivedTableNode:
+		return visitor.VisitDerivedTable(o.(*DerivedTableNode), visitor)
+	case *ValuesTableNode:
+		return visitor.VisitValuesTable(o.(*ValuesTableNode), visitor)
 	case *ValuesNode:
 		return visitor.VisitValues(o.(*ValuesNode), visitor)
 	case *UnionNode:
 		return visitor.VisitUnion(o.(*UnionNode), visitor)
+	case *UnionAllNode:
+		return visitor.VisitUnionAll(o.(*UnionAllNode), visitor)
 	case *IntersectNode:
 		return visitor.VisitIntersect(o.(*IntersectNode), visitor)
+	case *IntersectAllNode:
+		return visitor.VisitIntersectAll(o.(*IntersectAllNode), visitor)
 	case *ExceptNode:
 		return visitor.VisitExcept(o.(*ExceptNode), visitor)
+	case *ExceptAllNode:
+		return visitor.VisitExceptAll(o.(*ExceptAllNode), visitor)
 	case *BinaryLiteralNode:
 		return visitor.VisitBinaryLiteral(o.(*BinaryLiteralNode), visitor)
 
@@ -147,12 +159,35 @@ func (v *ToSqlVisitor) Visit(o interface{}, visitor VisitorInterface) error {
 	// subselects see TestToSqlVisitorSubSelect
 	case *SelectManager:
 		mgr, _ := o.(*SelectManager)
+		if err := mgr.Err(); err != nil {
+			return err
+		}
 		return visitor.Visit(Grouping(mgr.Tree), visitor)
 
 	// Function node visitors.
 	case *FunctionNode:
 		return visitor.VisitFunction(o.(*FunctionNode), visitor)
 
+	// DDL node visitors.
+	case *CreateTableStatementNode:
+		return visitor.VisitCreateTableStatement(o.(*CreateTableStatementNode), visitor)
+	case *AlterTableStatementNode:
+		return visitor.VisitAlterTableStatement(o.(*AlterTableStatementNode), visitor)
+	case *CreateIndexStatementNode:
+		return visitor.VisitCreateIndexStatement(o.(*CreateIndexStatementNode), visitor)
+	case *DropTableStatementNode:
+		return visitor.VisitDropTableStatement(o.(*DropTableStatementNode), visitor)
+	case *DropIndexStatementNode:
+		return visitor.VisitDropIndexStatement(o.(*DropIndexStatementNode), visitor)
+	case *ColumnDef:
+		return visitor.VisitColumnDef(o.(*ColumnDef), visitor)
+	case *AlterColumnNode:
+		return visitor.VisitAlterColumn(o.(*AlterColumnNode), visitor)
+
+	// Custom nodes rendering themselves e.g. AscendingNode.
+	case SqlNode:
+		return o.(SqlNode).Render(visitor)
+
 	// Base visitor.
 	default:
 		visitor.AppendSqlByte(QUESTION)
@@ -178,6 +213,9 @@ func (_ *ToSqlVisitor) VisitNot(o *NotNode, visitor VisitorInterface) (err error
 }
 
 func (_ *ToSqlVisitor) VisitLiteral(o *LiteralNode, visitor VisitorInterface) (err error) {
+	if o.Err != nil {
+		return o.Err
+	}
 
 	visitor.AppendSqlStr(o.Sql)
 	for _, arg := range o.Args {
@@ -227,18 +265,6 @@ func (_ *ToSqlVisitor) VisitHaving(o *HavingNode, visitor VisitorInterface) (err
 	return
 }
 
-func (_ *ToSqlVisitor) VisitAscending(o *AscendingNode, visitor VisitorInterface) (err error) {
-	err = visitor.Visit(o.Expr, visitor)
-	visitor.AppendSqlStr(" ASC")
-	return
-}
-
-func (_ *ToSqlVisitor) VisitDescending(o *DescendingNode, visitor VisitorInterface) (err error) {
-	err = visitor.Visit(o.Expr, visitor)
-	visitor.AppendSqlStr(" DESC")
-	return
-}
-
 // End Unary node visitors.
 
 // Begin Binary node visitors.
@@ -340,8 +366,7 @@ func (_ *ToSqlVisitor) VisitIn(o *InNode, visitor VisitorInterface) (err error)
 	}
 	vals, ok := o.Right.([]interface{})
 	if !ok {
-		visitor.AppendSqlStr("-- ERROR --")
-		return fmt.Errorf("IN() requires parameters to be []interface{} but is: %#v", o.Right)
+		return unexpectedType("IN() requires parameters to be []interface{} but", o.Right)
 	}
 	visitor.AppendSqlStr(" IN(")
 	for i, val := range vals {
@@ -402,6 +427,22 @@ func (_ *ToSqlVisitor) VisitTable(o *TableNode, visitor VisitorInterface) (err e
 		return visitor.QuoteTableName(o.Alias, visitor)
 	}
 
+	if o.Catalog != "" {
+		err = visitor.QuoteTableName(o.Catalog, visitor)
+		if err != nil {
+			return
+		}
+		visitor.AppendSqlByte(DOT)
+	}
+
+	if o.Schema != "" {
+		err = visitor.QuoteTableName(o.Schema, visitor)
+		if err != nil {
+			return
+		}
+		visitor.AppendSqlByte(DOT)
+	}
+
 	return visitor.QuoteTableName(o.Name, visitor)
 }
 
@@ -461,11 +502,38 @@ func (_ *ToSqlVisitor) VisitJoinSource(o *JoinSourceNode, visitor VisitorInterfa
 	return
 }
 
+func (_ *ToSqlVisitor) VisitDerivedTable(o *DerivedTableNode, visitor VisitorInterface) (err error) {
+	if o.Err != nil {
+		return o.Err
+	}
+	err = visitor.Visit(Grouping(o.Expr), visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlStr(AS)
+	err = visitor.Visit(o.Table, visitor)
+	return
+}
+
+func (_ *ToSqlVisitor) VisitValuesTable(o *ValuesTableNode, visitor VisitorInterface) (err error) {
+	return visitValuesTable(o, "", visitor)
+}
+
 func (_ *ToSqlVisitor) VisitValues(o *ValuesNode, visitor VisitorInterface) (err error) {
 
-	if length := len(o.Expressions) - 1; 0 <= length {
-		visitor.AppendSqlStr("VALUES (")
-		for index, value := range o.Expressions {
+	if 0 == len(o.Expressions) {
+		return
+	}
+
+	visitor.AppendSqlStr("VALUES ")
+	rows := append([][]interface{}{o.Expressions}, o.Rows...)
+	for r, row := range rows {
+		if r > 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		visitor.AppendSqlByte('(')
+		length := len(row) - 1
+		for index, value := range row {
 			err = visitor.Visit(value, visitor)
 			if err != nil {
 				return
@@ -481,45 +549,27 @@ func (_ *ToSqlVisitor) VisitValues(o *ValuesNode, visitor VisitorInterface) (err
 }
 
 func (_ *ToSqlVisitor) VisitUnion(o *UnionNode, visitor VisitorInterface) (err error) {
-	visitor.AppendSqlByte('(')
-	err = visitor.Visit(o.Left, visitor)
-	if err != nil {
-		return
-	}
-	visitor.AppendSqlStr(" UNION ")
-
-	err = visitor.Visit(o.Right, visitor)
+	return visitCombinator(o.Left, " UNION ", o.Right, visitor)
+}
 
-	visitor.AppendSqlByte(')')
-	return
+func (_ *ToSqlVisitor) VisitUnionAll(o *UnionAllNode, visitor VisitorInterface) (err error) {
+	return visitCombinator(o.Left, " UNION ALL ", o.Right, visitor)
 }
 
 func (_ *ToSqlVisitor) VisitIntersect(o *IntersectNode, visitor VisitorInterface) (err error) {
-	visitor.AppendSqlByte('(')
-	err = visitor.Visit(o.Left, visitor)
-	if err != nil {
-		return
-	}
-	visitor.AppendSqlStr(" INTERSECT ")
-
-	err = visitor.Visit(o.Right, visitor)
+	return visitCombinator(o.Left, " INTERSECT ", o.Right, visitor)
+}
 
-	visitor.AppendSqlByte(')')
-	return
+func (_ *ToSqlVisitor) VisitIntersectAll(o *IntersectAllNode, visitor VisitorInterface) (err error) {
+	return visitCombinator(o.Left, " INTERSECT ALL ", o.Right, visitor)
 }
 
 func (_ *ToSqlVisitor) VisitExcept(o *ExceptNode, visitor VisitorInterface) (err error) {
-	visitor.AppendSqlByte('(')
-	err = visitor.Visit(o.Left, visitor)
-	if err != nil {
-		return
-	}
-	visitor.AppendSqlStr(" EXCEPT ")
-
-	err = visitor.Visit(o.Right, visitor)
+	return visitCombinator(o.Left, " EXCEPT ", o.Right, visitor)
+}
 
-	visitor.AppendSqlByte(')')
-	return
+func (_ *ToSqlVisitor) VisitExceptAll(o *ExceptAllNode, visitor VisitorInterface) (err error) {
+	return visitCombinator(o.Left, " EXCEPT ALL ", o.Right, visitor)
 }
 
 func (_ *ToSqlVisitor) VisitBinaryLiteral(o *BinaryLiteralNode, visitor VisitorInterface) (err error) {
@@ -558,7 +608,7 @@ func (_ *ToSqlVisitor) VisitSelectCore(o *SelectStatementNode, visitor VisitorIn
 		}
 	}
 
-	if o.Source.Left.Name != "" {
+	if table, ok := o.Source.Left.(*TableNode); !ok || table.Name != "" {
 		visitor.AppendSqlStr(FROM)
 		err = visitor.Visit(o.Source, visitor)
 		if err != nil {
@@ -601,19 +651,19 @@ func (_ *ToSqlVisitor) VisitSelectCore(o *SelectStatementNode, visitor VisitorIn
 
 func (_ *ToSqlVisitor) VisitSelectStatement(o *SelectStatementNode, visitor VisitorInterface) (err error) {
 
-	// Union, Intersect, Except
-	if nil != o.Combinator {
-		// TODO: do not change the tree
-		combinator := o.Combinator
-		o.Combinator = nil
-		return visitor.Visit(combinator, visitor)
-	}
-
 	err = visitor.VisitSelectCore(o, visitor)
 	if err != nil {
 		return err
 	}
 
+	// Union, Intersect, Except - ORDER BY, LIMIT and OFFSET below apply to the combined result
+	for _, combinator := range o.Combinators {
+		err = visitor.Visit(combinator, visitor)
+		if err != nil {
+			return
+		}
+	}
+
 	if length := len(o.Orders) - 1; 0 <= length {
 		visitor.AppendSqlStr(ORDER_BY)
 		for index, order := range o.Orders {
@@ -699,7 +749,22 @@ func (_ *ToSqlVisitor) VisitUpdateStatement(o *UpdateStatementNode, visitor Visi
 	if length := len(o.Values) - 1; 0 <= length {
 		visitor.AppendSqlStr("SET ")
 		for index, assignment := range o.Values {
-			err = visitor.Visit(assignment, visitor)
+			err = visitor.Visit(unqualifiedAssignment(assignment), visitor)
+			if err != nil {
+				return
+			}
+			if index != length {
+				visitor.AppendSqlByte(COMMA)
+			} else {
+				visitor.AppendSqlByte(SPACE)
+			}
+		}
+	}
+
+	if length := len(o.Froms) - 1; 0 <= length {
+		visitor.AppendSqlStr("FROM ")
+		for index, from := range o.Froms {
+			err = visitor.Visit(from, visitor)
 			if err != nil {
 				return
 			}
@@ -798,45 +863,492 @@ func (v *ToSqlVisitor) VisitFunction(o *FunctionNode, visitor VisitorInterface)
 
 	if nil != o.Alias {
 		visitor.AppendSqlStr(AS)
-		visitor.QuoteColumnName(o.Alias, visitor)
+		err = visitor.QuoteColumnName(o.Alias, visitor)
+	}
+	return
+}
+
+// Begin DDL node visitors.
+
+func (_ *ToSqlVisitor) VisitCreateTableStatement(o *CreateTableStatementNode, visitor VisitorInterface) (err error) {
+	if 0 == len(o.Columns) {
+		return fmt.Errorf("%w: CREATE TABLE without columns", ErrArgumentCount)
+	}
+
+	visitor.AppendSqlStr("CREATE TABLE ")
+	if o.IfNotExists {
+		visitor.AppendSqlStr("IF NOT EXISTS ")
+	}
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlStr(" (")
+
+	// several primary key columns form a table constraint
+	var pk []string
+	for _, column := range o.Columns {
+		if column.PrimaryKey {
+			pk = append(pk, column.Name)
+		}
+	}
+	elements := make([]interface{}, 0, len(o.Columns)+len(o.Constraints)+1)
+	for _, column := range o.Columns {
+		if column.PrimaryKey && 1 < len(pk) {
+			c := *column
+			c.PrimaryKey = false
+			column = &c
+		}
+		elements = append(elements, column)
+	}
+	if 1 < len(pk) {
+		elements = append(elements, PrimaryKey(pk...))
+	}
+	elements = append(elements, o.Constraints...)
+
+	for index, element := range elements {
+		if index != 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		err = visitor.Visit(element, visitor)
+		if err != nil {
+			return
+		}
+	}
+	visitor.AppendSqlByte(')')
+	return
+}
+
+func (_ *ToSqlVisitor) VisitAlterTableStatement(o *AlterTableStatementNode, visitor VisitorInterface) (err error) {
+	if 0 == len(o.Actions) {
+		return fmt.Errorf("%w: ALTER TABLE without actions", ErrArgumentCount)
+	}
+
+	visitor.AppendSqlStr("ALTER TABLE ")
+	if o.IfExists {
+		visitor.AppendSqlStr("IF EXISTS ")
+	}
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+
+	for index, action := range o.Actions {
+		if index != 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		err = visitor.Visit(action, visitor)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// VisitCreateIndexStatement renders Postgres syntax
+// CREATE [UNIQUE ]INDEX [CONCURRENTLY ][IF NOT EXISTS ]["name" ]ON "table"[ USING method] (columns)[ WHERE conditions].
+func (_ *ToSqlVisitor) VisitCreateIndexStatement(o *CreateIndexStatementNode, visitor VisitorInterface) (err error) {
+	visitor.AppendSqlStr("CREATE ")
+	if o.Unique {
+		visitor.AppendSqlStr("UNIQUE ")
+	}
+	visitor.AppendSqlStr("INDEX ")
+	if o.Concurrently {
+		visitor.AppendSqlStr("CONCURRENTLY ")
+	}
+	if o.IfNotExists {
+		visitor.AppendSqlStr("IF NOT EXISTS ")
+	}
+	if o.Name != "" {
+		err = visitor.QuoteColumnName(o.Name, visitor)
+		if err != nil {
+			return
+		}
+		visitor.AppendSqlByte(SPACE)
+	}
+	visitor.AppendSqlStr("ON ")
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
+	}
+	if err = visitIndexMethod(o.Using, visitor); err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+	if err = visitIndexColumns(o.Columns, visitor); err != nil {
+		return
+	}
+
+	if length := len(o.Wheres) - 1; 0 <= length {
+		visitor.AppendSqlStr(WHERE)
+		for index, filter := range o.Wheres {
+			err = visitor.Visit(filter, visitor)
+			if err != nil {
+				return
+			}
+			if index != length {
+				visitor.AppendSqlStr(AND)
+			}
+		}
+	}
+	return
+}
+
+func (_ *ToSqlVisitor) VisitDropTableStatement(o *DropTableStatementNode, visitor VisitorInterface) (err error) {
+	if 0 == len(o.Tables) {
+		return fmt.Errorf("%w: DROP TABLE without tables", ErrArgumentCount)
+	}
+
+	visitor.AppendSqlStr("DROP TABLE ")
+	if o.IfExists {
+		visitor.AppendSqlStr("IF EXISTS ")
+	}
+	for index, table := range o.Tables {
+		if index != 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		err = visitor.Visit(table, visitor)
+		if err != nil {
+			return
+		}
+	}
+	if o.Cascade {
+		visitor.AppendSqlStr(" CASCADE")
+	}
+	return
+}
+
+// VisitDropIndexStatement renders Postgres syntax
+// DROP INDEX [CONCURRENTLY ][IF EXISTS ]"schema"."name"[ CASCADE], the index is qualified by the schema of the table.
+func (_ *ToSqlVisitor) VisitDropIndexStatement(o *DropIndexStatementNode, visitor VisitorInterface) (err error) {
+	visitor.AppendSqlStr("DROP INDEX ")
+	if o.Concurrently {
+		visitor.AppendSqlStr("CONCURRENTLY ")
+	}
+	if o.IfExists {
+		visitor.AppendSqlStr("IF EXISTS ")
+	}
+	if o.Table != nil && o.Table.Schema != "" {
+		err = visitor.QuoteTableName(o.Table.Schema, visitor)
+		if err != nil {
+			return
+		}
+		visitor.AppendSqlByte(DOT)
+	}
+	err = visitor.QuoteColumnName(o.Name, visitor)
+	if err != nil {
+		return
+	}
+	if o.Cascade {
+		visitor.AppendSqlStr(" CASCADE")
+	}
+	return
+}
+
+// VisitColumnDef renders the column definition with the standard GENERATED BY DEFAULT AS IDENTITY for identity columns.
+func (_ *ToSqlVisitor) VisitColumnDef(o *ColumnDef, visitor VisitorInterface) (err error) {
+	return visitColumnDef(o, "GENERATED BY DEFAULT AS IDENTITY", visitor)
+}
+
+// VisitAlterColumn renders Postgres syntax
+// ALTER COLUMN "name" TYPE type,ALTER COLUMN "name" SET NOT NULL,ALTER COLUMN "name" SET DEFAULT value,
+// only the clauses differing from o.From if set. The default of identity columns is never altered.
+func (_ *ToSqlVisitor) VisitAlterColumn(o *AlterColumnNode, visitor VisitorInterface) (err error) {
+	column, from := o.Column, o.From
+	if column.Type == "" {
+		return fmt.Errorf("%w: column '%s' without type", ErrInvalidSchema, column.Name)
+	}
+
+	clauses := 0
+	alter := func() error {
+		if clauses > 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		clauses++
+		visitor.AppendSqlStr("ALTER COLUMN ")
+		return visitor.QuoteColumnName(column.Name, visitor)
+	}
+
+	if from == nil || normalizeType(from.Type) != normalizeType(column.Type) {
+		if err = alter(); err != nil {
+			return
+		}
+		visitor.AppendSqlStr(" TYPE ")
+		visitor.AppendSqlStr(column.Type)
+	}
+
+	if from == nil || from.Nullable != column.Nullable {
+		if err = alter(); err != nil {
+			return
+		}
+		if column.Nullable {
+			visitor.AppendSqlStr(" DROP NOT NULL")
+		} else {
+			visitor.AppendSqlStr(" SET NOT NULL")
+		}
+	}
+
+	// Postgres rejects DROP DEFAULT on identity columns
+	if !column.Identity && (from == nil || !reflect.DeepEqual(from.Default, column.Default)) {
+		if clauses > 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		clauses++
+		if err = visitor.Visit(&ColumnDefaultNode{Name: column.Name, Default: column.Default}, visitor); err != nil {
+			return
+		}
+	}
+
+	if clauses == 0 {
+		return fmt.Errorf("%w: column '%s' is unchanged", ErrNothingToSet, column.Name)
 	}
 	return
 }
 
 // Begin Helpers.
 
-func (_ *ToSqlVisitor) QuoteTableName(o interface{}, visitor VisitorInterface) (err error) {
+// QuoteTableName appends the table name enclosed in the visitor's Quote.
+// Embedded quote characters are escaped, strict visitors reject unusual names.
+func (v *ToSqlVisitor) QuoteTableName(o interface{}, visitor VisitorInterface) (err error) {
 	s, ok := o.(string)
 	if !ok {
-		return fmt.Errorf("ToSqlVisitor.QuoteTableName() expected string but got %#v", o)
+		return unexpectedType("ToSqlVisitor.QuoteTableName() expected string but", o)
 	}
 
-	if !VALID_TABLE_NAME_PATTERN.MatchString(s) {
-		visitor.AppendSqlStr("-- ERROR --")
-		return fmt.Errorf("invalid table name: '%s'", s)
+	if err = checkIdentifier("table", s, v.Strict, v.adapter); err != nil {
+		return
 	}
 
-	visitor.AppendSqlByte(QUOTE)
-	visitor.AppendSqlStr(s)
-	visitor.AppendSqlByte(QUOTE)
+	visitor.AppendSqlStr(QuoteIdentifier(s, v.Quote))
 	return
 }
 
-func (_ *ToSqlVisitor) QuoteColumnName(o interface{}, visitor VisitorInterface) (err error) {
+// QuoteColumnName appends the column name enclosed in the visitor's Quote.
+// Embedded quote characters are escaped, strict visitors reject unusual names.
+func (v *ToSqlVisitor) QuoteColumnName(o interface{}, visitor VisitorInterface) (err error) {
 	s, ok := o.(string)
 	if !ok {
-		return fmt.Errorf("ToSqlVisitor.QuoteColumnName() expected string but got %#v", o)
+		return unexpectedType("ToSqlVisitor.QuoteColumnName() expected string but", o)
+	}
+
+	if err = checkIdentifier("column", s, v.Strict, v.adapter); err != nil {
+		return
+	}
+
+	visitor.AppendSqlStr(QuoteIdentifier(s, v.Quote))
+	return
+}
+
+// visitCombinator renders `left KEYWORD right`. left is omitted when nil,
+// i.e. the combinator is chained onto a SelectStatementNode.
+// Operands having their own ORDER BY, LIMIT, OFFSET or combinators
+// are enclosed in parentheses, see combinatorOperand.
+func visitCombinator(left interface{}, keyword string, right interface{}, visitor VisitorInterface) (err error) {
+	if nil != left {
+		err = visitor.Visit(combinatorOperand(left), visitor)
+		if err != nil {
+			return
+		}
+	}
+	visitor.AppendSqlStr(keyword)
+	return visitor.Visit(combinatorOperand(right), visitor)
+}
+
+// combinatorOperand unwraps a SelectManager to its tree, so it is not rendered as sub select.
+// A statement having its own ORDER BY, LIMIT, OFFSET or combinators is enclosed in parentheses.
+func combinatorOperand(o interface{}) interface{} {
+	if mgr, ok := o.(*SelectManager); ok {
+		o = mgr.Tree
+	}
+	if stm, ok := o.(*SelectStatementNode); ok {
+		if 0 < len(stm.Orders) || 0 < len(stm.Combinators) || nil != stm.Limit || nil != stm.Offset {
+			return Grouping(stm)
+		}
+	}
+	return o
+}
+
+// visitValuesTable renders `(VALUES (?,?),(?,?)) AS "alias"("col1","col2")`.
+// rowKeyword is put in front of each row e.g. "ROW" for MySQL.
+func visitValuesTable(o *ValuesTableNode, rowKeyword string, visitor VisitorInterface) (err error) {
+	visitor.AppendSqlStr("(VALUES ")
+	for i, row := range o.Rows {
+		if i > 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		visitor.AppendSqlStr(rowKeyword)
+		visitor.AppendSqlByte('(')
+		for j, value := range row {
+			if j > 0 {
+				visitor.AppendSqlByte(COMMA)
+			}
+			if j < len(o.Types) && o.Types[j] != "" {
+				visitor.AppendSqlStr("CAST(")
+				err = visitor.Visit(value, visitor)
+				if err != nil {
+					return
+				}
+				visitor.AppendSqlStr(AS)
+				visitor.AppendSqlStr(o.Types[j])
+				visitor.AppendSqlByte(')')
+			} else {
+				err = visitor.Visit(value, visitor)
+				if err != nil {
+					return
+				}
+			}
+		}
+		visitor.AppendSqlByte(')')
 	}
+	visitor.AppendSqlByte(')')
 
-	if !VALID_COL_NAME_PATTERN.MatchString(s) {
-		visitor.AppendSqlStr("-- ERROR --")
-		return fmt.Errorf("invalid column name: '%s'", s)
+	visitor.AppendSqlStr(AS)
+	err = visitor.Visit(o.Table, visitor)
+	if err != nil {
+		return
 	}
 
-	visitor.AppendSqlByte(QUOTE)
-	visitor.AppendSqlStr(s)
-	visitor.AppendSqlByte(QUOTE)
+	if length := len(o.Columns) - 1; 0 <= length {
+		visitor.AppendSqlByte('(')
+		for index, column := range o.Columns {
+			err = visitor.QuoteColumnName(column, visitor)
+			if err != nil {
+				return
+			}
+			if index != length {
+				visitor.AppendSqlByte(COMMA)
+			}
+		}
+		visitor.AppendSqlByte(')')
+	}
 	return
 }
 
+// unqualifiedAssignment returns the assignment of a SET list with an attribute as column
+// replaced by the column name, Postgres rejects qualified columns e.g. SET "users"."name"=$1.
+func unqualifiedAssignment(o interface{}) interface{} {
+	if a, ok := o.(*AssignmentNode); ok {
+		if attr, ok := a.Left.(*AttributeNode); ok {
+			return Assignment(attr.Name, a.Right)
+		}
+	}
+	return o
+}
+
 // End Helpers.
+
+// visitColumnDef renders "name" type[ NOT NULL][ DEFAULT value][ identity][ PRIMARY KEY][ UNIQUE][ REFERENCES "table" ("column")].
+// identity is the dialect's keyword of auto increment columns.
+func visitColumnDef(o *ColumnDef, identity string, visitor VisitorInterface) (err error) {
+	err = visitor.QuoteColumnName(o.Name, visitor)
+	if err != nil {
+		return
+	}
+	visitor.AppendSqlByte(SPACE)
+	if err = visitColumnType(o, visitor); err != nil {
+		return
+	}
+	if o.Identity {
+		visitor.AppendSqlByte(SPACE)
+		visitor.AppendSqlStr(identity)
+	}
+	if o.PrimaryKey {
+		visitor.AppendSqlStr(" PRIMARY KEY")
+	}
+	if o.Unique {
+		visitor.AppendSqlStr(" UNIQUE")
+	}
+
+	if ref := o.References; ref != nil {
+		visitor.AppendSqlStr(" REFERENCES ")
+		if err = visitor.Visit(referencedTable(ref), visitor); err != nil {
+			return
+		}
+		if ref.Column != "" {
+			visitor.AppendSqlByte(SPACE)
+			if err = visitColumnList([]string{ref.Column}, visitor); err != nil {
+				return
+			}
+		}
+		err = visitReferentialAction("ON DELETE", ref.OnDelete, visitor)
+	}
+	return
+}
+
+// visitColumnType renders type[ NOT NULL][ DEFAULT value] of the column.
+// The type is SQL, values of Default are bound as arguments - DDL managers inline them.
+func visitColumnType(o *ColumnDef, visitor VisitorInterface) (err error) {
+	if o.Type == "" {
+		return fmt.Errorf("%w: column '%s' without type", ErrInvalidSchema, o.Name)
+	}
+	visitor.AppendSqlStr(o.Type)
+	if !o.Nullable {
+		visitor.AppendSqlStr(" NOT NULL")
+	}
+	if o.Default != nil {
+		visitor.AppendSqlStr(" DEFAULT ")
+		err = visitor.Visit(o.Default, visitor)
+	}
+	return
+}
+
+// referencedTable returns the table of the foreign key, ref.Table may be qualified by a schema.
+func referencedTable(ref *ForeignKey) *TableNode {
+	if i := strings.LastIndexByte(ref.Table, DOT); i >= 0 {
+		return Table(ref.Table[i+1:]).InSchema(ref.Table[:i])
+	}
+	return Table(ref.Table)
+}
+
+// visitIndexMethod renders " USING method" unless method is empty.
+func visitIndexMethod(method string, visitor VisitorInterface) error {
+	if method == "" {
+		return nil
+	}
+	if !VALID_COL_NAME_PATTERN.MatchString(method) {
+		return fmt.Errorf("%w: index method '%s'", ErrInvalidLiteral, method)
+	}
+	visitor.AppendSqlStr(" USING ")
+	visitor.AppendSqlStr(method)
+	return nil
+}
+
+// visitIndexColumns renders the key parts of an index enclosed in parentheses.
+// Column names and attributes render unqualified, other expressions are enclosed in parentheses.
+func visitIndexColumns(columns []interface{}, visitor VisitorInterface) (err error) {
+	if 0 == len(columns) {
+		return fmt.Errorf("%w: CREATE INDEX without columns", ErrArgumentCount)
+	}
+	visitor.AppendSqlByte('(')
+	for index, column := range columns {
+		if index != 0 {
+			visitor.AppendSqlByte(COMMA)
+		}
+		if err = visitIndexColumn(column, visitor); err != nil {
+			return
+		}
+	}
+	visitor.AppendSqlByte(')')
+	return
+}
+
+func visitIndexColumn(o interface{}, visitor VisitorInterface) (err error) {
+	switch o := o.(type) {
+	case string:
+		return visitor.QuoteColumnName(o, visitor)
+	case *ColumnNode:
+		return visitor.Visit(o, visitor)
+	case *AttributeNode:
+		return visitor.Visit(o.Name, visitor)
+	case *AscendingNode:
+		err = visitIndexColumn(o.Expr, visitor)
+		visitor.AppendSqlStr(" ASC")
+		return
+	case *DescendingNode:
+		err = visitIndexColumn(o.Expr, visitor)
+		visitor.AppendSqlStr(" DESC")
+		return
+	}
+	return visitor.Visit(Grouping(o), visitor)
+}
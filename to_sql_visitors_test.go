@@ -132,18 +132,22 @@ func TestToSqlVisitorInColums(t *testing.T) {
 }
 
 func TestToSqlVisitorInLeftError(t *testing.T) {
-	sql, args, err := NewToSqlVisitor().Accept(In(Column(".raises error"), Column("a")))
+	v := NewToSqlVisitor()
+	v.Strict = true
+	sql, args, err := v.Accept(In(Column(".raises error"), Column("a")))
 	assert.NotNil(t, err)
 	assert.Equal(t, `invalid column name: '.raises error'`, err.Error())
-	assert.Equal(t, `-- ERROR --`, sql)
+	assert.Equal(t, ``, sql)
 	assert.Empty(t, args)
 }
 
 func TestToSqlVisitorInRightError(t *testing.T) {
-	sql, args, err := NewToSqlVisitor().Accept(In(Column("x"), Column(".raises error")))
+	v := NewToSqlVisitor()
+	v.Strict = true
+	sql, args, err := v.Accept(In(Column("x"), Column(".raises error")))
 	assert.NotNil(t, err)
 	assert.Equal(t, `invalid column name: '.raises error'`, err.Error())
-	assert.Equal(t, `"x" IN(-- ERROR --`, sql)
+	assert.Equal(t, ``, sql)
 	assert.Empty(t, args)
 }
 
@@ -473,12 +477,12 @@ func TestToSqlVisitorUnion(t *testing.T) {
 	one := SelectStatement(relationOne)
 	two := SelectStatement(relationTwo)
 	three := SelectStatement(relationThree)
-	one.Combinator = Union(one, two)
-	two.Combinator = Union(two, three)
+	one.Combinators = append(one.Combinators, Union(nil, two))
+	two.Combinators = append(two.Combinators, Union(nil, three))
 
 	sql, args, err := NewToSqlVisitor().Accept(one)
 	assert.Nil(t, err)
-	assert.Equal(t, `(SELECT * FROM "table_one" UNION (SELECT * FROM "table_two" UNION SELECT * FROM "table_three"))`, sql)
+	assert.Equal(t, `SELECT * FROM "table_one" UNION (SELECT * FROM "table_two" UNION SELECT * FROM "table_three")`, sql)
 	assert.Empty(t, args)
 }
 
@@ -489,12 +493,12 @@ func TestToSqlVisitorIntersect(t *testing.T) {
 	one := SelectStatement(relationOne)
 	two := SelectStatement(relationTwo)
 	three := SelectStatement(relationThree)
-	one.Combinator = Intersect(one, two)
-	two.Combinator = Intersect(two, three)
+	one.Combinators = append(one.Combinators, Intersect(nil, two))
+	two.Combinators = append(two.Combinators, Intersect(nil, three))
 
 	sql, args, err := NewToSqlVisitor().Accept(one)
 	assert.Nil(t, err)
-	assert.Equal(t, `(SELECT * FROM "table_one" INTERSECT (SELECT * FROM "table_two" INTERSECT SELECT * FROM "table_three"))`, sql)
+	assert.Equal(t, `SELECT * FROM "table_one" INTERSECT (SELECT * FROM "table_two" INTERSECT SELECT * FROM "table_three")`, sql)
 	assert.Empty(t, args)
 }
 
@@ -505,15 +509,65 @@ func TestToSqlVisitorExcept(t *testing.T) {
 	one := SelectStatement(relationOne)
 	two := SelectStatement(relationTwo)
 	three := SelectStatement(relationThree)
-	one.Combinator = Except(one, two)
-	two.Combinator = Except(two, three)
+	one.Combinators = append(one.Combinators, Except(nil, two))
+	two.Combinators = append(two.Combinators, Except(nil, three))
 
 	sql, args, err := NewToSqlVisitor().Accept(one)
 	assert.Nil(t, err)
-	assert.Equal(t, `(SELECT * FROM "table_one" EXCEPT (SELECT * FROM "table_two" EXCEPT SELECT * FROM "table_three"))`, sql)
+	assert.Equal(t, `SELECT * FROM "table_one" EXCEPT (SELECT * FROM "table_two" EXCEPT SELECT * FROM "table_three")`, sql)
 	assert.Empty(t, args)
 }
 
+func TestToSqlVisitorUnionAll(t *testing.T) {
+	one := SelectStatement(Table("table_one"))
+	two := SelectStatement(Table("table_two"))
+	three := SelectStatement(Table("table_three"))
+	one.Combinators = append(one.Combinators, UnionAll(nil, two), Union(nil, three))
+	one.Orders = append(one.Orders, Literal("id"))
+	one.Limit = Limit(10)
+
+	sql, args, err := NewToSqlVisitor().Accept(one)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM "table_one" UNION ALL SELECT * FROM "table_two" UNION SELECT * FROM "table_three" ORDER BY id LIMIT ?`, sql)
+	assert.Equal(t, []interface{}{10}, args)
+}
+
+func TestToSqlVisitorIntersectAllExceptAll(t *testing.T) {
+	one := SelectStatement(Table("table_one"))
+	two := SelectStatement(Table("table_two"))
+	three := SelectStatement(Table("table_three"))
+	one.Combinators = append(one.Combinators, IntersectAll(nil, two), ExceptAll(nil, three))
+
+	sql, args, err := NewToSqlVisitor().Accept(one)
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM "table_one" INTERSECT ALL SELECT * FROM "table_two" EXCEPT ALL SELECT * FROM "table_three"`, sql)
+	assert.Empty(t, args)
+}
+
+func TestToSqlVisitorUnionStandalone(t *testing.T) {
+	one := SelectStatement(Table("table_one"))
+	two := SelectStatement(Table("table_two"))
+	two.Limit = Limit(1)
+
+	sql, args, err := NewToSqlVisitor().Accept(Union(one, two))
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM "table_one" UNION (SELECT * FROM "table_two" LIMIT ?)`, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestToSqlVisitorUnionDoesNotChangeTree(t *testing.T) {
+	one := SelectStatement(Table("table_one"))
+	two := SelectStatement(Table("table_two"))
+	one.Combinators = append(one.Combinators, Union(nil, two))
+
+	sql1, _, err := NewToSqlVisitor().Accept(one)
+	assert.Nil(t, err)
+	sql2, _, err := NewToSqlVisitor().Accept(one)
+	assert.Nil(t, err)
+	assert.Equal(t, sql1, sql2)
+	assert.Len(t, one.Combinators, 1)
+}
+
 func TestToSqlVisitorSubSelect(t *testing.T) {
 	subTab := Table("sub_table")
 	sub := subTab.Select(Sum(Column("s"))).Group(Column("id"))
@@ -661,20 +715,65 @@ func TestToSqlVisitorQuoteColumnNameDolar(t *testing.T) {
 	assert.Equal(t, `"foo$"`, v.String())
 }
 
-func TestToSqlVisitorQuoteColumnNameWithQuoteReturnsError(t *testing.T) {
+func TestToSqlVisitorQuoteColumnNameWithQuoteIsEscaped(t *testing.T) {
 	v := NewToSqlVisitor()
 	err := v.QuoteColumnName(`id" baaaam`, v)
+	assert.Nil(t, err)
+	assert.Equal(t, `"id"" baaaam"`, v.String())
+}
+
+func TestToSqlVisitorQuoteColumnNameUnusual(t *testing.T) {
+	v := NewToSqlVisitor()
+	err := v.QuoteColumnName(`1foo`, v)
+	assert.Nil(t, err)
+	v.AppendSqlByte(COMMA)
+	err = v.QuoteColumnName(`_Foo bar`, v)
+	assert.Nil(t, err)
+	v.AppendSqlByte(COMMA)
+	err = v.QuoteColumnName(`größe`, v)
+	assert.Nil(t, err)
+	assert.Equal(t, `"1foo","_Foo bar","größe"`, v.String())
+}
+
+func TestToSqlVisitorQuoteColumnNameEmptyReturnsError(t *testing.T) {
+	v := NewToSqlVisitor()
+	err := v.QuoteColumnName(``, v)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid column name: ''", err.Error())
+}
+
+func TestToSqlVisitorQuoteColumnNameStrictWithQuoteReturnsError(t *testing.T) {
+	v := NewToSqlVisitor()
+	v.Strict = true
+	err := v.QuoteColumnName(`id" baaaam`, v)
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid column name: 'id\" baaaam'", err.Error())
-	assert.Equal(t, `-- ERROR --`, v.String())
+	assert.Equal(t, ``, v.String())
 }
 
-func TestToSqlVisitorQuoteColumnNameLeadingNumReturnsError(t *testing.T) {
+func TestToSqlVisitorQuoteColumnNameStrictLeadingNumReturnsError(t *testing.T) {
 	v := NewToSqlVisitor()
+	v.Strict = true
 	err := v.QuoteColumnName(`1foo`, v)
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid column name: '1foo'", err.Error())
-	assert.Equal(t, `-- ERROR --`, v.String())
+	assert.Equal(t, ``, v.String())
+}
+
+func TestToSqlVisitorQuoteColumnNameStrictLeadingUnderscore(t *testing.T) {
+	v := NewToSqlVisitor()
+	v.Strict = true
+	err := v.QuoteColumnName(`_Foo`, v)
+	assert.Nil(t, err)
+	assert.Equal(t, `"_Foo"`, v.String())
+}
+
+func TestToSqlVisitorQuoteColumnNameStrictReservedWordReturnsError(t *testing.T) {
+	v := NewToSqlVisitor()
+	v.Strict = true
+	err := v.QuoteColumnName(`order`, v)
+	assert.NotNil(t, err)
+	assert.Equal(t, "reserved word as column name: 'order'", err.Error())
 }
 
 func TestToSqlVisitorQuoteTableName(t *testing.T) {
@@ -696,23 +795,40 @@ func TestToSqlVisitorQuoteTableNameEmptyReturnsError(t *testing.T) {
 	err := v.QuoteTableName(``, v)
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid table name: ''", err.Error())
-	assert.Equal(t, `-- ERROR --`, v.String())
+	assert.Equal(t, ``, v.String())
+}
+
+func TestToSqlVisitorQuoteTableNameWithQuoteIsEscaped(t *testing.T) {
+	v := NewToSqlVisitor()
+	err := v.QuoteTableName(`foo" baaam`, v)
+	assert.Nil(t, err)
+	assert.Equal(t, `"foo"" baaam"`, v.String())
 }
 
-func TestToSqlVisitorQuoteTableNameWithQuoteReturnsError(t *testing.T) {
+func TestToSqlVisitorQuoteTableNameStrictWithQuoteReturnsError(t *testing.T) {
 	v := NewToSqlVisitor()
+	v.Strict = true
 	err := v.QuoteTableName(`foo" baaam`, v)
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid table name: 'foo\" baaam'", err.Error())
-	assert.Equal(t, `-- ERROR --`, v.String())
+	assert.Equal(t, ``, v.String())
 }
 
-func TestToSqlVisitorQuoteTableNameWithLeadingNumReturnsError(t *testing.T) {
+func TestToSqlVisitorQuoteTableNameStrictWithLeadingNumReturnsError(t *testing.T) {
 	v := NewToSqlVisitor()
+	v.Strict = true
 	err := v.QuoteTableName(`124foo`, v)
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid table name: '124foo'", err.Error())
-	assert.Equal(t, `-- ERROR --`, v.String())
+	assert.Equal(t, ``, v.String())
+}
+
+func TestToSqlVisitorQuoteTableNameStrictReservedWordReturnsError(t *testing.T) {
+	v := NewToSqlVisitor()
+	v.Strict = true
+	err := v.QuoteTableName(`User`, v)
+	assert.NotNil(t, err)
+	assert.Equal(t, "reserved word as table name: 'User'", err.Error())
 }
 
 //         map: 5800 ns/op
@@ -1,29 +1,47 @@
 package codex
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // UpdateManager manages a tree that compiles to a SQL update statement.
 type UpdateManager struct {
 	Tree    *UpdateStatementNode // The AST for the SQL UPDATE statement.
 	Adapter adapter              // The SQL Engine.
+
+	immutable bool  // chained calls return a modified clone, see Immutable()
+	err       error // first error recorded while building, see Err()
+	hooks     []Hook
 }
 
 var _ Scoper = (*UpdateManager)(nil)
 
 func (self *UpdateManager) Scopes(scopes ...ScopeFunc) *UpdateManager {
+	self = self.chain()
 	for _, scope := range scopes {
 		scope(self)
 	}
 	return self
 }
 
+// Scope appends a WHERE condition like Where.
+// It always modifies the manager - even in immutable mode - as it is called by the ScopeFuncs of Scopes.
 func (self *UpdateManager) Scope(expr interface{}, args ...interface{}) {
-	self.Where(expr, args...)
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 }
 
 // Set appends to the trees Values slice a list of ColumnNodes
 // which are to be modified in the query.
+// strings are converted to ColumnNodes, other nodes e.g. AttributeNodes are kept.
+// AttributeNodes are rendered as column name, qualified only by MySQL updating multiple tables, see From.
 func (self *UpdateManager) Set(columns ...interface{}) *UpdateManager {
+	self = self.chain()
 	for _, column := range columns {
-		self.Tree.Values = append(self.Tree.Values, Column(column))
+		if _, ok := column.(string); ok {
+			column = Column(column)
+		}
+		self.Tree.Values = append(self.Tree.Values, column)
 	}
 
 	return self
@@ -31,14 +49,125 @@ func (self *UpdateManager) Set(columns ...interface{}) *UpdateManager {
 
 // To alters the trees Values slice to be an AssignmentNode, containing the
 // column from Set at the same index of the value.
+// More values than columns are recorded as error, see Err().
 func (self *UpdateManager) To(values ...interface{}) *UpdateManager {
+	self = self.chain()
 	for index, value := range values {
 		if index < len(self.Tree.Values) {
 			column := self.Tree.Values[index]
 			self.Tree.Values[index] = Assignment(column, value)
+		} else if self.err == nil {
+			self.err = fmt.Errorf("%w: UpdateManager.To() got %d values for %d columns", ErrArgumentCount, len(values), len(self.Tree.Values))
+			break
+		}
+	}
+
+	return self
+}
+
+// UpdateOptions configure SetStruct.
+type UpdateOptions struct {
+	Original interface{} // only fields differing from this struct of the same type are set e.g. as loaded
+	Columns  []string    // only these columns are set, all if empty
+}
+
+// SetStruct sets the columns to the `db` tagged fields of a struct or a pointer to one,
+// see InsertManager.FromStruct for the tags.
+// Fields tagged pk are not set but appended as WHERE condition, readonly fields are never set.
+// With an Original every field differing from it is set, zero values included.
+// Without one omitempty fields are not set when zero unless they are listed in Columns:
+//
+//	type User struct {
+//		Id   int64  `db:"id,pk"`
+//		Name string `db:"name"`
+//		Nick string `db:"nick,omitempty"`
+//	}
+//
+//	users.Modification().SetStruct(changed, UpdateOptions{Original: loaded})
+//	// UPDATE "users" SET "name"=? WHERE ("users"."id"=?)
+//
+// If no column is left to set ErrNothingToSet is recorded, for a zero or nil pk field
+// e.g. of an unsaved struct ErrMissingPrimaryKey, see Err().
+func (self *UpdateManager) SetStruct(v interface{}, opts UpdateOptions) *UpdateManager {
+	self = self.chain()
+
+	rv, ok := structValue(v)
+	if !ok {
+		if self.err == nil {
+			self.err = unexpectedType("UpdateManager.SetStruct()", v)
 		}
+		return self
 	}
+	var original reflect.Value
+	if opts.Original != nil {
+		if original, ok = structValue(opts.Original); !ok || original.Type() != rv.Type() {
+			if self.err == nil {
+				self.err = unexpectedType("UpdateManager.SetStruct() original", opts.Original)
+			}
+			return self
+		}
+	}
+	only := map[string]bool{}
+	for _, column := range opts.Columns {
+		only[column] = true
+	}
+
+	set := 0
+	for _, field := range StructFields(rv.Type()) {
+		value, zero := field.value(rv)
+		switch {
+		case field.PK:
+			// WHERE "id"=0 would update nothing, WHERE "id" IS NULL unrelated rows
+			if zero && self.err == nil {
+				self.err = fmt.Errorf("%w: UpdateManager.SetStruct() got zero '%s' for %T", ErrMissingPrimaryKey, field.Column, v)
+			}
+			self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(Equal(self.Tree.Table.Col(field.Column), value)))
+			continue
+		case field.ReadOnly:
+			continue
+		case 0 < len(only) && !only[field.Column]:
+			continue
+		}
+		if original.IsValid() {
+			// changed to zero e.g. true to false must be set as well
+			if old, _ := field.value(original); reflect.DeepEqual(old, value) {
+				continue
+			}
+		} else if field.OmitEmpty && zero && !only[field.Column] {
+			continue
+		}
+		self.Tree.Values = append(self.Tree.Values, Assignment(Column(field.Column), value))
+		set++
+	}
+
+	if set == 0 && self.err == nil {
+		self.err = fmt.Errorf("%w: UpdateManager.SetStruct() got no columns to set for %T", ErrNothingToSet, v)
+	}
+	return self
+}
+
+// SetMap sets the columns to the values of the map, ordered by column name:
+//
+//	users.Modification().SetMap(map[string]interface{}{"name": "Jon", "age": 42}).Where(users.Col("id").Eq(7))
+//	// UPDATE "users" SET "age"=?,"name"=? WHERE ("users"."id"=?)
+func (self *UpdateManager) SetMap(m map[string]interface{}) *UpdateManager {
+	self = self.chain()
+	for _, key := range sortedKeys(m) {
+		self.Tree.Values = append(self.Tree.Values, Assignment(Column(key), m[key]))
+	}
+	return self
+}
 
+// From appends table sources e.g. a ValuesTable to the tree's Froms slice.
+//
+//	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+//	items.Set("qty").To(v.Col("qty")).From(v).Where(items.Col("id").Eq(v.Col("id")))
+//	// UPDATE "items" SET "qty"="v"."qty" FROM (VALUES (?,?),(?,?)) AS "v"("id","qty") WHERE ("items"."id"="v"."id")
+//
+// MySQL renders the sources as multi table update: UPDATE `items`, (VALUES ROW(?,?),...) AS `v`(`id`,`qty`) SET ...
+func (self *UpdateManager) From(sources ...interface{}) *UpdateManager {
+	self = self.chain()
+	self.Tree.Froms = append(self.Tree.Froms, sources...)
 	return self
 }
 
@@ -49,32 +178,27 @@ func (self *UpdateManager) To(values ...interface{}) *UpdateManager {
 //   Where("a = ? AND b = ?", 123, true)    // with args -> Group(Literal("a = ? AND b = ?", 123, true))
 //   Where(Equal(Column("a"), Column("b"))) // no   args -> Group(Equal(Column("a"), Column("b")))
 func (self *UpdateManager) Where(expr interface{}, args ...interface{}) *UpdateManager {
-
-	if str, ok := expr.(string); ok {
-		expr = Literal(str, args...)
-	}
-	// enclose expr in Grouping - except if expr is already a Grouping
-	if _, ok := expr.(*GroupingNode); !ok {
-		expr = Grouping(expr)
-	}
-
-	self.Tree.Wheres = append(self.Tree.Wheres, expr)
+	self = self.chain()
+	self.Tree.Wheres = append(self.Tree.Wheres, whereExpr(expr, args...))
 	return self
 }
 
 // Sets the Tree's Limit to the given integer.
 func (self *UpdateManager) Limit(expr interface{}) *UpdateManager {
+	self = self.chain()
 	self.Tree.Limit = Limit(expr)
 	return self
 }
 
 // Selection returns a *SelectManager while keeping
-// wheres, limit and adapter
+// copies of wheres, limit and adapter
 func (self *UpdateManager) Selection() *SelectManager {
 	m := Selection(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
@@ -83,22 +207,95 @@ func (self *UpdateManager) Selection() *SelectManager {
 func (self *UpdateManager) Insertion() *InsertManager {
 	m := Insertion(self.Tree.Table)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
 // Deletion returns a *DeleteManager while keeping
-// wheres, limit and adapter
+// copies of wheres, limit and adapter
 func (self *UpdateManager) Deletion() *DeleteManager {
 	m := Deletion(self.Tree.Table)
-	m.Tree.Wheres = self.Tree.Wheres
-	m.Tree.Limit = self.Tree.Limit
+	m.Tree.Wheres = cloneSlice(self.Tree.Wheres)
+	m.Tree.Limit = cloneLimit(self.Tree.Limit)
 	m.Adapter = self.Adapter
+	m.immutable = self.immutable
+	m.hooks = self.hooks
 	return m
 }
 
+// Hooks adds hooks called by ToSql and ToSqlWith, see Hook.
+func (self *UpdateManager) Hooks(hooks ...Hook) *UpdateManager {
+	self = self.chain()
+	self.hooks = appendHooks(self.hooks, hooks...)
+	return self
+}
+
+// Clone returns a copy of the manager with a deep copy of its Tree.
+// Modifying the clone does not affect the original and vice versa.
+func (self *UpdateManager) Clone() *UpdateManager {
+	m := *self
+	m.Tree = cloneUpdateStatement(self.Tree)
+	return &m
+}
+
+// Immutable switches the manager into immutable mode:
+// each chained call returns a modified clone and leaves the manager untouched.
+// Thus a base query can be branched into several variants.
+func (self *UpdateManager) Immutable() *UpdateManager {
+	self.immutable = true
+	return self
+}
+
+// chain returns the manager chained calls modify,
+// a clone in immutable mode, the manager itself otherwise.
+func (self *UpdateManager) chain() *UpdateManager {
+	if self.immutable {
+		return self.Clone()
+	}
+	return self
+}
+
+// Err returns the first error recorded while building the query e.g. an unexpected argument type.
+// ToSql returns it as well.
+func (self *UpdateManager) Err() error {
+	return self.err
+}
+
 // ToSql calls a visitor's Accept method based on the manager's SQL adapter.
+// The Tree is not modified, so ToSql is safe to be called repeatedly and concurrently.
+// On error neither SQL nor arguments are returned.
 func (self *UpdateManager) ToSql() (string, []interface{}, error) {
-	return VisitorFor(self.Adapter).Accept(self.Tree)
+	return render(self.hooks, "UPDATE", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorFor(self.Adapter))
+	})
+}
+
+// ToSqlWith is like ToSql but renders the placeholders in `style` e.g. COLON_NUMBER for Oracle.
+func (self *UpdateManager) ToSqlWith(style PlaceholderStyle) (string, []interface{}, error) {
+	return render(self.hooks, "UPDATE", self.Tree.Table, func() (string, []interface{}, error) {
+		return self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(style)))
+	})
+}
+
+// ToDebugSql returns the SQL with the args inlined for logs and EXPLAIN copy-paste, see Interpolate.
+// NEVER execute it - use ToSql instead. Hooks are not called.
+func (self *UpdateManager) ToDebugSql() (string, error) {
+	sql, args, err := self.accept(VisitorWith(self.Adapter, NewPlaceholderCollector(QUESTION_MARK)))
+	if err != nil {
+		return "", err
+	}
+	return Interpolate(sql, args, self.Adapter)
+}
+
+func (self *UpdateManager) accept(visitor VisitorInterface) (string, []interface{}, error) {
+	if self.err != nil {
+		return "", nil, self.err
+	}
+	if err := validateSchema(self.Tree); err != nil {
+		return "", nil, err
+	}
+	return visitor.Accept(self.Tree)
 }
 
 func (self *UpdateManager) Table() *TableNode {
@@ -110,6 +307,7 @@ func Modification(relation *TableNode) (m *UpdateManager) {
 	m = new(UpdateManager)
 	m.Tree = UpdateStatement(relation)
 	m.Adapter = relation.Adapter
+	m.hooks = relation.hooks
 
 	return
 }
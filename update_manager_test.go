@@ -1,6 +1,7 @@
 package codex
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -110,3 +111,132 @@ func TestUpdateManagerDeletion(t *testing.T) {
 	assert.Equal(t, `DELETE FROM "users" WHERE ("users"."owner_id"=?) AND (id > ?) LIMIT ?`, sql)
 	assert.Equal(t, []interface{}{77, 2, 1}, args)
 }
+
+func TestUpdateManagerImmutable(t *testing.T) {
+	users := Table("users")
+	base := users.Set("name").To("x").Immutable()
+	a := base.Where(users.Col("id").Eq(1))
+	b := base.Where(users.Col("id").Eq(2)).Limit(1)
+
+	sql, args, err := base.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=? `, sql)
+	assert.Equal(t, []interface{}{"x"}, args)
+
+	sql, args, err = a.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"x", 1}, args)
+
+	sql, args, err = b.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=? WHERE ("users"."id"=?) LIMIT ?`, sql)
+	assert.Equal(t, []interface{}{"x", 2, 1}, args)
+}
+
+func TestUpdateManagerClone(t *testing.T) {
+	users := Table("users")
+	m := users.Set("name").To("x")
+	c := m.Clone().Where("id = ?", 1)
+	assert.Empty(t, m.Tree.Wheres)
+	assert.Len(t, c.Tree.Wheres, 1)
+}
+
+func TestUpdateManagerSetStruct(t *testing.T) {
+	users := Table("users")
+	age := 42
+
+	sql, args, err := users.Modification().SetStruct(&structUser{Id: 7, Name: "Jon", Age: &age}, UpdateOptions{}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=?,"age"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"Jon", &age, int64(7)}, args)
+
+	sql, args, err = users.Modification().SetStruct(structUser{Id: 7, Name: "Jon", Nick: "j"}, UpdateOptions{Columns: []string{"nick"}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "nick"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"j", int64(7)}, args)
+
+	// listed omitempty columns are set when zero
+	sql, args, err = users.Modification().SetStruct(structUser{Id: 7}, UpdateOptions{Columns: []string{"nick"}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "nick"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"", int64(7)}, args)
+}
+
+func TestUpdateManagerSetStructZeroPrimaryKey(t *testing.T) {
+	users := Table("users")
+	sql, args, err := users.Modification().SetStruct(structUser{Name: "Jon"}, UpdateOptions{}).ToSql()
+	assert.True(t, errors.Is(err, ErrMissingPrimaryKey))
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	type ref struct {
+		Uuid *string `db:"uuid,pk"`
+		Name string  `db:"name"`
+	}
+	_, _, err = users.Modification().SetStruct(&ref{Name: "Jon"}, UpdateOptions{}).ToSql()
+	assert.True(t, errors.Is(err, ErrMissingPrimaryKey))
+}
+
+func TestUpdateManagerSetStructChangedToFalse(t *testing.T) {
+	type flag struct {
+		Id     int64 `db:"id,pk"`
+		Active bool  `db:"active,omitempty"`
+	}
+	flags := Dialect(POSTGRES).Table("flags")
+
+	sql, args, err := flags.Modification().SetStruct(flag{Id: 1}, UpdateOptions{Original: flag{Id: 1, Active: true}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "flags" SET "active"=$1 WHERE ("flags"."id"=$2)`, sql)
+	assert.Equal(t, []interface{}{false, int64(1)}, args)
+}
+
+func TestUpdateManagerSetStructChanged(t *testing.T) {
+	users := Table("users")
+	loaded := structUser{Id: 7, Name: "Jon", Nick: "j"}
+	changed := loaded
+	changed.Name = "John"
+
+	sql, args, err := users.Modification().SetStruct(changed, UpdateOptions{Original: loaded}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"John", int64(7)}, args)
+
+	// changes to zero values are set, omitempty is ignored
+	changed = loaded
+	changed.Nick = ""
+	sql, args, err = users.Modification().SetStruct(changed, UpdateOptions{Original: loaded}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "nick"=? WHERE ("users"."id"=?)`, sql)
+	assert.Equal(t, []interface{}{"", int64(7)}, args)
+
+	_, _, err = users.Modification().SetStruct(loaded, UpdateOptions{Original: &loaded}).ToSql()
+	assert.True(t, errors.Is(err, ErrNothingToSet))
+
+	_, _, err = users.Modification().SetStruct(loaded, UpdateOptions{Original: structAudit{}}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+
+	_, _, err = users.Modification().SetStruct("jon", UpdateOptions{}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnexpectedType))
+}
+
+func TestUpdateManagerSetAttribute(t *testing.T) {
+	users := Dialect(POSTGRES).Table("users")
+	sql, args, err := users.Set(users.Col("name")).To("a").Where(users.Col("id").Eq(1)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name"=$1 WHERE ("users"."id"=$2)`, sql)
+	assert.Equal(t, []interface{}{"a", 1}, args)
+
+	mysql := Dialect(MYSQL).Table("users")
+	sql, _, err = mysql.Set(mysql.Col("name")).To("a").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE `users` SET `name`=? ", sql)
+}
+
+func TestUpdateManagerSetMap(t *testing.T) {
+	users := Dialect(MYSQL).Table("users")
+	sql, args, err := users.Modification().SetMap(map[string]interface{}{"name": "Jon", "age": 42}).Where(users.Col("id").Eq(7)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE `users` SET `age`=?,`name`=? WHERE (`users`.`id`=?)", sql)
+	assert.Equal(t, []interface{}{42, "Jon", 7}, args)
+}
@@ -4,6 +4,7 @@ package codex
 type UpdateStatementNode struct {
 	Table  *TableNode    // Pointer to the Table the Delete Statement is acting on.
 	Values []interface{} // Values is an array of expressions/nodes.
+	Froms  []interface{} // Additional table sources of UPDATE ... FROM.
 	Wheres []interface{} // Wheres is an array of expressions/nodes.
 	Limit  *LimitNode    // Potential Limit node for limiting the number of rows effected.
 }
@@ -13,6 +14,7 @@ func UpdateStatement(relation *TableNode) (statement *UpdateStatementNode) {
 	statement = new(UpdateStatementNode)
 	statement.Table = relation
 	statement.Values = make([]interface{}, 0)
+	statement.Froms = make([]interface{}, 0)
 	statement.Wheres = make([]interface{}, 0)
 	return
 }
@@ -2,8 +2,9 @@ package codex
 
 // ValuesNode is a specific BinaryNode.
 type ValuesNode struct {
-	Expressions []interface{} // Array of expressions/nodes, normally assignments.
-	Columns     []interface{} // Array of columns the expressions effect.
+	Expressions []interface{}   // Array of expressions/nodes, normally assignments.
+	Columns     []interface{}   // Array of columns the expressions effect.
+	Rows        [][]interface{} // Further rows of a multi row insert, see InsertManager.Row.
 }
 
 // ValuesNode factory method.
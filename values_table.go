@@ -0,0 +1,84 @@
+package codex
+
+// ValuesTableNode is a VALUES list used as table source,
+// renders to `(VALUES (?,?),(?,?)) AS "alias"("col1","col2")` in FROM and JOIN clauses.
+type ValuesTableNode struct {
+	Rows    [][]interface{} // Rows of values, bound as arguments.
+	Columns []string        // Column names of the values list.
+	Types   []string        // Optional SQL types the values of each column are casted to, "" for no cast.
+	Table   *TableNode      // Table named by the alias, columns of the values list are scoped to it.
+}
+
+// Row appends a row of values.
+func (v *ValuesTableNode) Row(values ...interface{}) *ValuesTableNode {
+	v.Rows = append(v.Rows, values)
+	return v
+}
+
+// Cast sets the SQL types the values are casted to, in order of the columns.
+// An empty string leaves the values of that column uncasted.
+//
+//	ValuesTable("v", "id", "qty").Cast("int", "").Row(1, 2)
+//	// (VALUES (CAST(? AS int),?)) AS "v"("id","qty")
+func (v *ValuesTableNode) Cast(types ...string) *ValuesTableNode {
+	v.Types = types
+	return v
+}
+
+// Col returns a Column scoped to the values list e.g. renders to '"alias"."name"' sql
+func (v *ValuesTableNode) Col(name string) *AttributeNode {
+	return v.Table.Col(name)
+}
+
+// Star returns a * scoped to the values list e.g. renders to '"alias".*' sql
+func (v *ValuesTableNode) Star() *AttributeNode {
+	return v.Table.Star()
+}
+
+// Select returns a SelectManager selecting from the values list
+// appends the columns
+func (v *ValuesTableNode) Select(cols ...interface{}) *SelectManager {
+	// convert string to AttributeNode
+	for i, col := range cols {
+		if str, ok := col.(string); ok {
+			cols[i] = v.Col(str)
+		}
+	}
+
+	return v.Selection().Select(cols...)
+}
+
+// Where Returns a pointer to a SelectManager with the initial filter provided.
+// see SelectManager.Where()
+func (v *ValuesTableNode) Where(expr interface{}, args ...interface{}) *SelectManager {
+	return v.Selection().Where(expr, args...)
+}
+
+// Returns a pointer to a SelectManager with an initial InnerJoinNode.
+func (v *ValuesTableNode) InnerJoin(expr interface{}) *SelectManager {
+	return v.Selection().InnerJoin(expr)
+}
+
+// Returns a pointer to a SelectManager with an initial OuterJoinNode.
+func (v *ValuesTableNode) OuterJoin(expr interface{}) *SelectManager {
+	return v.Selection().OuterJoin(expr)
+}
+
+// Returns a pointer to a SelectManager selecting from the values list
+func (v *ValuesTableNode) Selection() *SelectManager {
+	m := Selection(v.Table)
+	m.Tree.Source.Left = v
+	return m
+}
+
+// ValuesTableNode factory method.
+//
+//	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+//	// (VALUES (?,?),(?,?)) AS "v"("id","qty")
+func ValuesTable(alias string, columns ...string) *ValuesTableNode {
+	return &ValuesTableNode{
+		Rows:    make([][]interface{}, 0),
+		Columns: columns,
+		Table:   Table(alias),
+	}
+}
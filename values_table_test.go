@@ -0,0 +1,84 @@
+package codex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValuesTableFactory(t *testing.T) {
+	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+	assert.Equal(t, "v", v.Table.Name)
+	assert.Equal(t, []string{"id", "qty"}, v.Columns)
+	assert.Equal(t, [][]interface{}{{1, 10}, {2, 20}}, v.Rows)
+	assert.Empty(t, v.Types)
+}
+
+func TestValuesTableSelect(t *testing.T) {
+	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+
+	sql, args, err := v.Select("id").Where(v.Col("qty").Gt(15)).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "v"."id" FROM (VALUES (?,?),(?,?)) AS "v"("id","qty") WHERE ("v"."qty">?)`, sql)
+	assert.Equal(t, []interface{}{1, 10, 2, 20, 15}, args)
+}
+
+func TestValuesTableCast(t *testing.T) {
+	v := ValuesTable("v", "id", "qty").Cast("int", "").Row(1, 10).Row(2, 20)
+
+	sql, args, err := NewToSqlVisitor().Accept(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `(VALUES (CAST(? AS int),?),(CAST(? AS int),?)) AS "v"("id","qty")`, sql)
+	assert.Equal(t, []interface{}{1, 10, 2, 20}, args)
+}
+
+func TestValuesTablePostgresJoin(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	items := psql.Table("items")
+	v := ValuesTable("v", "id", "qty").Cast("bigint", "int").Row(1, 10).Row(2, 20)
+
+	sql, args, err := items.Select(items.Col("id"), v.Col("qty")).
+		InnerJoin(v).On(v.Col("id").Eq(items.Col("id"))).
+		Where(items.Col("active").Eq(true)).
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "items"."id","v"."qty" FROM "items" INNER JOIN (VALUES (CAST($1 AS bigint),CAST($2 AS int)),(CAST($3 AS bigint),CAST($4 AS int))) AS "v"("id","qty") ON "v"."id"="items"."id" WHERE ("items"."active"=$5)`, sql)
+	assert.Equal(t, []interface{}{1, 10, 2, 20, true}, args)
+}
+
+func TestValuesTablePostgresUpdateFrom(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	items := psql.Table("items")
+	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+
+	sql, args, err := items.Set("qty").To(v.Col("qty")).From(v).
+		Where(items.Col("id").Eq(v.Col("id"))).
+		Where("items.locked = ?", false).
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `UPDATE "items" SET "qty"="v"."qty" FROM (VALUES ($1,$2),($3,$4)) AS "v"("id","qty") WHERE ("items"."id"="v"."id") AND (items.locked = $5)`, sql)
+	assert.Equal(t, []interface{}{1, 10, 2, 20, false}, args)
+}
+
+func TestValuesTableMySql(t *testing.T) {
+	mysql := Dialect(MYSQL)
+	items := mysql.Table("items")
+	v := ValuesTable("v", "id", "qty").Row(1, 10).Row(2, 20)
+
+	sql, args, err := items.InnerJoin(v).On(v.Col("id").Eq(items.Col("id"))).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `items`.* FROM `items` INNER JOIN (VALUES ROW(?,?),ROW(?,?)) AS `v`(`id`,`qty`) ON `v`.`id`=`items`.`id`", sql)
+	assert.Equal(t, []interface{}{1, 10, 2, 20}, args)
+}
+
+func TestValuesTableMySqlUpdate(t *testing.T) {
+	mysql := Dialect(MYSQL)
+	items := mysql.Table("items")
+	v := ValuesTable("v", "id", "qty").Cast("SIGNED", "SIGNED").Row(1, 10)
+
+	sql, args, err := items.Set(items.Col("qty")).To(v.Col("qty")).From(v).
+		Where(items.Col("id").Eq(v.Col("id"))).
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE `items`,(VALUES ROW(CAST(? AS SIGNED),CAST(? AS SIGNED))) AS `v`(`id`,`qty`) SET `items`.`qty`=`v`.`qty` WHERE (`items`.`id`=`v`.`id`)", sql)
+	assert.Equal(t, []interface{}{1, 10}, args)
+}
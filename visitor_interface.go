@@ -21,8 +21,6 @@ type VisitorInterface interface {
 	VisitLimit(*LimitNode, VisitorInterface) error
 	VisitOffset(*OffsetNode, VisitorInterface) error
 	VisitHaving(*HavingNode, VisitorInterface) error
-	VisitAscending(*AscendingNode, VisitorInterface) error
-	VisitDescending(*DescendingNode, VisitorInterface) error
 
 	// Binary node visitors.
 	VisitAs(*AsNode, VisitorInterface) error
@@ -43,10 +41,15 @@ type VisitorInterface interface {
 	VisitInnerJoin(*InnerJoinNode, VisitorInterface) error
 	VisitOuterJoin(*OuterJoinNode, VisitorInterface) error
 	VisitJoinSource(*JoinSourceNode, VisitorInterface) error
+	VisitDerivedTable(*DerivedTableNode, VisitorInterface) error
+	VisitValuesTable(*ValuesTableNode, VisitorInterface) error
 	VisitValues(*ValuesNode, VisitorInterface) error
 	VisitUnion(*UnionNode, VisitorInterface) error
+	VisitUnionAll(*UnionAllNode, VisitorInterface) error
 	VisitIntersect(*IntersectNode, VisitorInterface) error
+	VisitIntersectAll(*IntersectAllNode, VisitorInterface) error
 	VisitExcept(*ExceptNode, VisitorInterface) error
+	VisitExceptAll(*ExceptAllNode, VisitorInterface) error
 	VisitBinaryLiteral(*BinaryLiteralNode, VisitorInterface) error
 
 	// Nary node visitors.
@@ -59,6 +62,15 @@ type VisitorInterface interface {
 	// Function node visitor.
 	VisitFunction(*FunctionNode, VisitorInterface) error
 
+	// DDL node visitors.
+	VisitCreateTableStatement(*CreateTableStatementNode, VisitorInterface) error
+	VisitAlterTableStatement(*AlterTableStatementNode, VisitorInterface) error
+	VisitCreateIndexStatement(*CreateIndexStatementNode, VisitorInterface) error
+	VisitDropTableStatement(*DropTableStatementNode, VisitorInterface) error
+	VisitDropIndexStatement(*DropIndexStatementNode, VisitorInterface) error
+	VisitColumnDef(*ColumnDef, VisitorInterface) error
+	VisitAlterColumn(*AlterColumnNode, VisitorInterface) error
+
 	// Helpers.
 	QuoteTableName(interface{}, VisitorInterface) error
 	QuoteColumnName(interface{}, VisitorInterface) error
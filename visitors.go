@@ -1,13 +1,43 @@
 package codex
 
 // VisitorFor returns a AST visitor for the adapter argument.
+// Visitors of strict adapters are Strict, see DbDialect.Strict.
 func VisitorFor(adapter adapter) VisitorInterface {
-	switch adapter {
+	strict := adapter&strictAdapter != 0
+	switch adapter.dialect() {
 	case MYSQL:
-		return NewMySqlVisitor()
+		v := NewMySqlVisitor()
+		v.Strict = strict
+		return v
 	case POSTGRES:
-		return NewPostgresVisitor()
+		v := NewPostgresVisitor()
+		v.Strict = strict
+		return v
 	default:
-		return NewToSqlVisitor()
+		v := NewToSqlVisitor()
+		v.Strict = strict
+		return v
+	}
+}
+
+// VisitorWith returns a AST visitor for the adapter argument collecting the SQL with `collector`
+// e.g. a PlaceholderCollector.
+func VisitorWith(adapter adapter, collector CollectorInterface) VisitorInterface {
+	strict := adapter&strictAdapter != 0
+	switch adapter.dialect() {
+	case MYSQL:
+		v := NewMySqlVisitor()
+		v.CollectorInterface = collector
+		v.Strict = strict
+		return v
+	case POSTGRES:
+		v := &PostgresVisitor{NewToSqlVisitor(collector), 0}
+		v.adapter = POSTGRES
+		v.Strict = strict
+		return v
+	default:
+		v := NewToSqlVisitor(collector)
+		v.Strict = strict
+		return v
 	}
 }
@@ -0,0 +1,448 @@
+package codex
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Walk traverses the AST node o in depth-first order, starting with o itself.
+// fn is called for each node and argument value, if fn returns false
+// the children of the node are skipped. Slices are traversed element by element,
+// fn is not called for the slices themselves.
+//
+//	tables := map[string]bool{}
+//	Walk(m.Tree, func(o interface{}) bool {
+//		if t, ok := o.(*TableNode); ok {
+//			tables[t.Name] = true
+//		}
+//		return true
+//	})
+func Walk(o interface{}, fn func(interface{}) bool) {
+	if s, ok := o.([]interface{}); ok {
+		for _, e := range s {
+			Walk(e, fn)
+		}
+		return
+	}
+
+	if isNilNode(o) || !fn(o) {
+		return
+	}
+
+	if u := unaryOf(o); u != nil {
+		Walk(u.Expr, fn)
+		return
+	}
+	if b := binaryOf(o); b != nil {
+		Walk(b.Left, fn)
+		Walk(b.Right, fn)
+		return
+	}
+
+	switch o := o.(type) {
+	case *LiteralNode:
+		Walk(o.Args, fn)
+	case *AttributeNode:
+		Walk(o.Table, fn)
+		Walk(o.Name, fn)
+	case *JoinSourceNode:
+		Walk(o.Left, fn)
+		Walk(o.Right, fn)
+	case *ValuesNode:
+		Walk(o.Columns, fn)
+		Walk(o.Expressions, fn)
+		for _, row := range o.Rows {
+			Walk(row, fn)
+		}
+	case *DerivedTableNode:
+		Walk(o.Expr, fn)
+		Walk(o.Table, fn)
+	case *ValuesTableNode:
+		for _, row := range o.Rows {
+			Walk(row, fn)
+		}
+		Walk(o.Table, fn)
+	case *FunctionNode:
+		Walk(o.Args, fn)
+		Walk(o.Alias, fn)
+	case *SelectStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Cols, fn)
+		Walk(o.Source, fn)
+		Walk(o.Wheres, fn)
+		Walk(o.Groups, fn)
+		Walk(o.Having, fn)
+		Walk(o.Combinators, fn)
+		Walk(o.Orders, fn)
+		Walk(o.Limit, fn)
+		Walk(o.Offset, fn)
+	case *InsertStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Columns, fn)
+		Walk(o.Values, fn)
+		Walk(o.Returning, fn)
+	case *UpdateStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Values, fn)
+		Walk(o.Froms, fn)
+		Walk(o.Wheres, fn)
+		Walk(o.Limit, fn)
+	case *DeleteStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Wheres, fn)
+		Walk(o.Limit, fn)
+	case *CreateTableStatementNode:
+		Walk(o.Table, fn)
+		for _, column := range o.Columns {
+			Walk(column, fn)
+		}
+		Walk(o.Constraints, fn)
+	case *AlterTableStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Actions, fn)
+	case *CreateIndexStatementNode:
+		Walk(o.Table, fn)
+		Walk(o.Columns, fn)
+		Walk(o.Wheres, fn)
+	case *DropTableStatementNode:
+		for _, table := range o.Tables {
+			Walk(table, fn)
+		}
+	case *DropIndexStatementNode:
+		Walk(o.Table, fn)
+	case *ColumnDef:
+		Walk(o.Default, fn)
+		Walk(o.References, fn)
+	case *AddColumnNode:
+		Walk(o.Column, fn)
+	case *AlterColumnNode:
+		Walk(o.Column, fn)
+		Walk(o.From, fn)
+	case *ColumnDefaultNode:
+		Walk(o.Default, fn)
+	case *AddConstraintNode:
+		Walk(o.Constraint, fn)
+	case *ForeignKeyNode:
+		Walk(o.Table, fn)
+	case *CheckNode:
+		Walk(o.Expr, fn)
+	case *SelectManager:
+		Walk(o.Tree, fn)
+	}
+}
+
+// Rewrite returns a transformed deep copy of the AST node o, o itself stays untouched.
+// The tree is traversed depth-first, children are rewritten before their parent.
+// fn is called for each node and argument value and returns its replacement,
+// returning the node unchanged keeps it. Returning nil removes the node from
+// slices e.g. a condition from Wheres.
+//
+// Fields having a concrete node type e.g. SelectStatementNode.Limit must be replaced
+// with a node of the same type (or nil), otherwise Rewrite panics.
+// TableNodes are shared with o (see CloneNode), return a new TableNode instead of modifying it.
+//
+//	// rename column "name" to "full_name"
+//	tree := Rewrite(m.Tree, func(o interface{}) interface{} {
+//		if c, ok := o.(*ColumnNode); ok && c.Expr == "name" {
+//			return Column("full_name")
+//		}
+//		return o
+//	}).(*SelectStatementNode)
+func Rewrite(o interface{}, fn func(interface{}) interface{}) interface{} {
+	return rewrite(CloneNode(o), fn)
+}
+
+// rewrite transforms the tree o in place.
+func rewrite(o interface{}, fn func(interface{}) interface{}) interface{} {
+	if s, ok := o.([]interface{}); ok {
+		return rewriteSlice(s, fn)
+	}
+
+	if isNilNode(o) {
+		return o
+	}
+
+	if u := unaryOf(o); u != nil {
+		u.Expr = rewrite(u.Expr, fn)
+		return fn(o)
+	}
+	if b := binaryOf(o); b != nil {
+		b.Left = rewrite(b.Left, fn)
+		b.Right = rewrite(b.Right, fn)
+		return fn(o)
+	}
+
+	switch o := o.(type) {
+	case *LiteralNode:
+		o.Args = rewriteSlice(o.Args, fn)
+	case *AttributeNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Name = rewrite(o.Name, fn)
+	case *JoinSourceNode:
+		o.Left = rewrite(o.Left, fn)
+		o.Right = rewriteSlice(o.Right, fn)
+	case *ValuesNode:
+		o.Columns = rewriteSlice(o.Columns, fn)
+		o.Expressions = rewriteSlice(o.Expressions, fn)
+		for i, row := range o.Rows {
+			o.Rows[i] = rewriteSlice(row, fn)
+		}
+	case *DerivedTableNode:
+		o.Expr = rewriteSelectStatement(o.Expr, fn)
+		o.Table = rewriteTable(o.Table, fn)
+	case *ValuesTableNode:
+		for i, row := range o.Rows {
+			o.Rows[i] = rewriteSlice(row, fn)
+		}
+		o.Table = rewriteTable(o.Table, fn)
+	case *FunctionNode:
+		o.Args = rewriteSlice(o.Args, fn)
+		o.Alias = rewrite(o.Alias, fn)
+	case *SelectStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Cols = rewriteSlice(o.Cols, fn)
+		if o.Source != nil {
+			o.Source = mustRewriteTo(rewrite(o.Source, fn), (*JoinSourceNode)(nil)).(*JoinSourceNode)
+		}
+		o.Wheres = rewriteSlice(o.Wheres, fn)
+		o.Groups = rewriteSlice(o.Groups, fn)
+		o.Having = rewrite(o.Having, fn)
+		o.Combinators = rewriteSlice(o.Combinators, fn)
+		o.Orders = rewriteSlice(o.Orders, fn)
+		o.Limit = rewriteLimit(o.Limit, fn)
+		if o.Offset != nil {
+			o.Offset = mustRewriteTo(rewrite(o.Offset, fn), (*OffsetNode)(nil)).(*OffsetNode)
+		}
+	case *InsertStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Columns = rewriteSlice(o.Columns, fn)
+		if o.Values != nil {
+			o.Values = mustRewriteTo(rewrite(o.Values, fn), (*ValuesNode)(nil)).(*ValuesNode)
+		}
+		o.Returning = rewrite(o.Returning, fn)
+	case *UpdateStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Values = rewriteSlice(o.Values, fn)
+		o.Froms = rewriteSlice(o.Froms, fn)
+		o.Wheres = rewriteSlice(o.Wheres, fn)
+		o.Limit = rewriteLimit(o.Limit, fn)
+	case *DeleteStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Wheres = rewriteSlice(o.Wheres, fn)
+		o.Limit = rewriteLimit(o.Limit, fn)
+	case *CreateTableStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		columns := o.Columns[:0]
+		for _, column := range o.Columns {
+			if column = rewriteColumnDef(column, fn); column != nil {
+				columns = append(columns, column)
+			}
+		}
+		o.Columns = columns
+		o.Constraints = rewriteSlice(o.Constraints, fn)
+	case *AlterTableStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Actions = rewriteSlice(o.Actions, fn)
+	case *CreateIndexStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+		o.Columns = rewriteSlice(o.Columns, fn)
+		o.Wheres = rewriteSlice(o.Wheres, fn)
+	case *DropTableStatementNode:
+		tables := o.Tables[:0]
+		for _, table := range o.Tables {
+			if table = rewriteTable(table, fn); table != nil {
+				tables = append(tables, table)
+			}
+		}
+		o.Tables = tables
+	case *DropIndexStatementNode:
+		o.Table = rewriteTable(o.Table, fn)
+	case *ColumnDef:
+		o.Default = rewrite(o.Default, fn)
+		if o.References != nil {
+			o.References = mustRewriteTo(rewrite(o.References, fn), (*ForeignKey)(nil)).(*ForeignKey)
+		}
+	case *AddColumnNode:
+		o.Column = rewriteColumnDef(o.Column, fn)
+	case *AlterColumnNode:
+		o.Column = rewriteColumnDef(o.Column, fn)
+		o.From = rewriteColumnDef(o.From, fn)
+	case *ColumnDefaultNode:
+		o.Default = rewrite(o.Default, fn)
+	case *AddConstraintNode:
+		o.Constraint = rewrite(o.Constraint, fn)
+	case *ForeignKeyNode:
+		o.Table = rewriteTable(o.Table, fn)
+	case *CheckNode:
+		o.Expr = rewrite(o.Expr, fn)
+	case *SelectManager:
+		o.Tree = rewriteSelectStatement(o.Tree, fn)
+	}
+
+	return fn(o)
+}
+
+// rewriteSlice rewrites the elements of s in place, nil results are removed.
+func rewriteSlice(s []interface{}, fn func(interface{}) interface{}) []interface{} {
+	if s == nil {
+		return nil
+	}
+	n := s[:0]
+	for _, e := range s {
+		if e = rewrite(e, fn); e != nil {
+			n = append(n, e)
+		}
+	}
+	return n
+}
+
+func rewriteTable(o *TableNode, fn func(interface{}) interface{}) *TableNode {
+	if o == nil {
+		return nil
+	}
+	return mustRewriteTo(rewrite(o, fn), (*TableNode)(nil)).(*TableNode)
+}
+
+func rewriteColumnDef(o *ColumnDef, fn func(interface{}) interface{}) *ColumnDef {
+	if o == nil {
+		return nil
+	}
+	return mustRewriteTo(rewrite(o, fn), (*ColumnDef)(nil)).(*ColumnDef)
+}
+
+func rewriteLimit(o *LimitNode, fn func(interface{}) interface{}) *LimitNode {
+	if o == nil {
+		return nil
+	}
+	return mustRewriteTo(rewrite(o, fn), (*LimitNode)(nil)).(*LimitNode)
+}
+
+func rewriteSelectStatement(o *SelectStatementNode, fn func(interface{}) interface{}) *SelectStatementNode {
+	if o == nil {
+		return nil
+	}
+	return mustRewriteTo(rewrite(o, fn), (*SelectStatementNode)(nil)).(*SelectStatementNode)
+}
+
+// mustRewriteTo returns o if it has the type of want, a typed nil of want if o is nil
+// and panics otherwise.
+func mustRewriteTo(o interface{}, want interface{}) interface{} {
+	if o == nil {
+		return want
+	}
+	if reflect.TypeOf(o) != reflect.TypeOf(want) {
+		panic(fmt.Sprintf("codex.Rewrite() expected %T but got %#v", want, o))
+	}
+	return o
+}
+
+// isNilNode reports whether o is nil or a nil pointer of a node type.
+func isNilNode(o interface{}) bool {
+	switch o := o.(type) {
+	case nil:
+		return true
+	case *TableNode:
+		return o == nil
+	case *LimitNode:
+		return o == nil
+	case *OffsetNode:
+		return o == nil
+	case *JoinSourceNode:
+		return o == nil
+	case *ValuesNode:
+		return o == nil
+	case *SelectStatementNode:
+		return o == nil
+	case *ColumnDef:
+		return o == nil
+	case *ForeignKey:
+		return o == nil
+	}
+	return false
+}
+
+// unaryOf returns the UnaryNode of o if o is an unary node, nil otherwise.
+func unaryOf(o interface{}) *UnaryNode {
+	switch o := o.(type) {
+	case *UnaryNode:
+		return o
+	case *GroupingNode:
+		return (*UnaryNode)(o)
+	case *NotNode:
+		return (*UnaryNode)(o)
+	case *OnNode:
+		return (*UnaryNode)(o)
+	case *ColumnNode:
+		return (*UnaryNode)(o)
+	case *StarNode:
+		return (*UnaryNode)(o)
+	case *BindingNode:
+		return (*UnaryNode)(o)
+	case *LimitNode:
+		return (*UnaryNode)(o)
+	case *OffsetNode:
+		return (*UnaryNode)(o)
+	case *HavingNode:
+		return (*UnaryNode)(o)
+	case *AscendingNode:
+		return (*UnaryNode)(o)
+	case *DescendingNode:
+		return (*UnaryNode)(o)
+	}
+	return nil
+}
+
+// binaryOf returns the BinaryNode of o if o is a binary node, nil otherwise.
+func binaryOf(o interface{}) *BinaryNode {
+	switch o := o.(type) {
+	case *BinaryNode:
+		return o
+	case *AsNode:
+		return (*BinaryNode)(o)
+	case *BetweenNode:
+		return (*BinaryNode)(o)
+	case *AssignmentNode:
+		return (*BinaryNode)(o)
+	case *EqualNode:
+		return (*BinaryNode)(o)
+	case *NotEqualNode:
+		return (*BinaryNode)(o)
+	case *GreaterThanNode:
+		return (*BinaryNode)(o)
+	case *GreaterThanOrEqualNode:
+		return (*BinaryNode)(o)
+	case *LessThanNode:
+		return (*BinaryNode)(o)
+	case *LessThanOrEqualNode:
+		return (*BinaryNode)(o)
+	case *InNode:
+		return (*BinaryNode)(o)
+	case *LikeNode:
+		return (*BinaryNode)(o)
+	case *UnlikeNode:
+		return (*BinaryNode)(o)
+	case *OrNode:
+		return (*BinaryNode)(o)
+	case *AndNode:
+		return (*BinaryNode)(o)
+	case *InnerJoinNode:
+		return (*BinaryNode)(o)
+	case *OuterJoinNode:
+		return (*BinaryNode)(o)
+	case *UnionNode:
+		return (*BinaryNode)(o)
+	case *UnionAllNode:
+		return (*BinaryNode)(o)
+	case *IntersectNode:
+		return (*BinaryNode)(o)
+	case *IntersectAllNode:
+		return (*BinaryNode)(o)
+	case *ExceptNode:
+		return (*BinaryNode)(o)
+	case *ExceptAllNode:
+		return (*BinaryNode)(o)
+	case *BinaryLiteralNode:
+		return (*BinaryNode)(o)
+	}
+	return nil
+}
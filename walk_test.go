@@ -0,0 +1,248 @@
+package codex
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWalkCollectsTablesAndArgs(t *testing.T) {
+	users := Table("users")
+	orders := Table("orders")
+	m := users.Select(users.Col("id"), Count(orders.Col("id"))).
+		InnerJoin(orders).On(orders.Col("user_id").Eq(users.Col("id"))).
+		Where(users.Col("state").In("a", "b")).
+		Group(users.Col("id")).
+		Limit(10)
+
+	tables := map[string]int{}
+	args := []interface{}{}
+	Walk(m.Tree, func(o interface{}) bool {
+		switch o := o.(type) {
+		case *TableNode:
+			tables[o.Name]++
+		case string, int:
+			args = append(args, o)
+		}
+		return true
+	})
+
+	assert.Equal(t, 2, len(tables))
+	assert.True(t, tables["orders"] > 0)
+	// column names are strings within ColumnNodes as well
+	assert.Contains(t, args, "a")
+	assert.Contains(t, args, "b")
+	assert.Contains(t, args, 10)
+}
+
+func TestWalkSkipsChildren(t *testing.T) {
+	users := Table("users")
+	sub := users.Select("id").Where(users.Col("id").Eq(1))
+	m := Table("orders").Where(Table("orders").Col("user_id").In(sub))
+
+	count := 0
+	Walk(m, func(o interface{}) bool {
+		if _, ok := o.(*SelectManager); ok && o != m {
+			return false
+		}
+		if _, ok := o.(*EqualNode); ok {
+			count++
+		}
+		return true
+	})
+	assert.Equal(t, 0, count)
+}
+
+func TestWalkEveryNode(t *testing.T) {
+	users := Table("users")
+	v := ValuesTable("v", "id").Row(1)
+	d := From(users.Select("id"), "d")
+	m := users.Select(users.Col("name").As("n")).
+		InnerJoin(v).On(v.Col("id").Eq(users.Col("id"))).
+		OuterJoin(d).On(d.Col("id").Eq(users.Col("id"))).
+		Where(Not(users.Col("a").Like("x%")).Or(users.Col("b").Unlike("y"))).
+		Where(users.Col("c").Gte(1).And(users.Col("c").Lte(2))).
+		Having(Max(users.Col("c")).Gt(0)).
+		Order(users.Col("c").Asc()).
+		UnionAll(users.Select("id")).
+		Offset(1)
+
+	types := map[string]bool{}
+	Walk(m.Tree, func(o interface{}) bool {
+		types[fmt.Sprintf("%T", o)] = true
+		return true
+	})
+	for _, name := range []string{"*codex.ValuesTableNode", "*codex.DerivedTableNode", "*codex.AsNode",
+		"*codex.NotNode", "*codex.LikeNode", "*codex.UnlikeNode", "*codex.OrNode", "*codex.AndNode",
+		"*codex.HavingNode", "*codex.FunctionNode", "*codex.AscendingNode", "*codex.UnionAllNode",
+		"*codex.OffsetNode", "*codex.OnNode", "*codex.OuterJoinNode", "*codex.InnerJoinNode"} {
+		assert.True(t, types[name], name)
+	}
+}
+
+func TestRewriteRenamesColumn(t *testing.T) {
+	users := Table("users")
+	m := users.Select("name").Where(users.Col("name").Eq("Jon"))
+
+	tree := Rewrite(m.Tree, func(o interface{}) interface{} {
+		if c, ok := o.(*ColumnNode); ok && c.Expr == "name" {
+			return Column("full_name")
+		}
+		return o
+	}).(*SelectStatementNode)
+
+	sql, args, err := (&SelectManager{Tree: tree}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."full_name" FROM "users" WHERE ("users"."full_name"=?)`, sql)
+	assert.Equal(t, []interface{}{"Jon"}, args)
+
+	// original untouched
+	sql, _, err = m.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users"."name" FROM "users" WHERE ("users"."name"=?)`, sql)
+}
+
+func TestRewriteInjectsTenantFilter(t *testing.T) {
+	users := Table("users")
+	m := users.Where(users.Col("id").Eq(1))
+
+	tree := Rewrite(m.Tree, func(o interface{}) interface{} {
+		if stm, ok := o.(*SelectStatementNode); ok {
+			stm.Wheres = append(stm.Wheres, Grouping(stm.Table.Col("tenant_id").Eq(7)))
+		}
+		return o
+	}).(*SelectStatementNode)
+
+	sql, args, err := (&SelectManager{Tree: tree}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."id"=?) AND ("users"."tenant_id"=?)`, sql)
+	assert.Equal(t, []interface{}{1, 7}, args)
+	assert.Len(t, m.Tree.Wheres, 1)
+}
+
+func TestRewriteRemovesNodes(t *testing.T) {
+	users := Table("users")
+	m := users.Where("a = ?", 1).Where(users.Col("b").Eq(2)).Limit(5)
+
+	tree := Rewrite(m.Tree, func(o interface{}) interface{} {
+		switch o := o.(type) {
+		case *GroupingNode:
+			if _, ok := o.Expr.(*LiteralNode); ok {
+				return nil
+			}
+		case *LimitNode:
+			return nil
+		}
+		return o
+	}).(*SelectStatementNode)
+
+	sql, args, err := (&SelectManager{Tree: tree}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "users".* FROM "users" WHERE ("users"."b"=?)`, sql)
+	assert.Equal(t, []interface{}{2}, args)
+}
+
+func TestRewriteReplacesTable(t *testing.T) {
+	users := Table("users")
+	m := users.Insert("Jon").Into("name")
+
+	tree := Rewrite(m.Tree, func(o interface{}) interface{} {
+		if t, ok := o.(*TableNode); ok && t.Name == "users" {
+			return Table("users").InSchema("tenant7")
+		}
+		return o
+	}).(*InsertStatementNode)
+
+	sql, args, err := (&InsertManager{Tree: tree}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "tenant7"."users" ("name") VALUES (?)`, sql)
+	assert.Equal(t, []interface{}{"Jon"}, args)
+}
+
+func TestRewriteWrongTypePanics(t *testing.T) {
+	m := Table("users").Selection().Limit(1)
+	assert.Panics(t, func() {
+		Rewrite(m.Tree, func(o interface{}) interface{} {
+			if _, ok := o.(*LimitNode); ok {
+				return Offset(1)
+			}
+			return o
+		})
+	})
+}
+
+func TestWalkColumnDefinitions(t *testing.T) {
+	users := Table("users")
+	m := users.CreateTable().Columns(
+		&ColumnDef{Name: "id", Type: "bigint", PrimaryKey: true},
+		&ColumnDef{Name: "team_id", Type: "bigint", References: &ForeignKey{Table: "teams", Column: "id"}},
+		&ColumnDef{Name: "created_at", Type: "timestamptz", Default: Literal("now()")},
+	)
+
+	var refs []*ForeignKey
+	var defaults []string
+	Walk(m.Tree, func(o interface{}) bool {
+		switch o := o.(type) {
+		case *ForeignKey:
+			refs = append(refs, o)
+		case *LiteralNode:
+			defaults = append(defaults, o.Sql)
+		}
+		return true
+	})
+	assert.Equal(t, []*ForeignKey{{Table: "teams", Column: "id"}}, refs)
+	assert.Equal(t, []string{"now()"}, defaults)
+}
+
+func TestRewriteDdl(t *testing.T) {
+	psql := Dialect(POSTGRES)
+	users := psql.Table("users")
+	tenant := func(o interface{}) interface{} {
+		if t, ok := o.(*TableNode); ok {
+			return psql.Table(t.Name).InSchema("tenant_7")
+		}
+		return o
+	}
+	render := func(o interface{}) string {
+		sql, _, err := renderDdl(nil, "", nil, POSTGRES, nil, o)
+		assert.Nil(t, err)
+		return sql
+	}
+
+	create := users.CreateTable().Columns(
+		&ColumnDef{Name: "id", Type: "bigint", PrimaryKey: true},
+		&ColumnDef{Name: "nick", Type: "text", Default: "x"},
+	).Constraints(Foreign("id").References(psql.Table("accounts")))
+	tree := Rewrite(create.Tree, func(o interface{}) interface{} {
+		if c, ok := o.(*ColumnDef); ok && c.Name == "nick" {
+			c.Name = "nickname"
+		}
+		return tenant(o)
+	})
+	assert.Equal(t, `CREATE TABLE "tenant_7"."users" ("id" bigint NOT NULL PRIMARY KEY,"nickname" text NOT NULL DEFAULT 'x',`+
+		`FOREIGN KEY ("id") REFERENCES "tenant_7"."accounts")`, render(tree))
+	// original and its column definitions untouched
+	assert.Equal(t, `CREATE TABLE "users" ("id" bigint NOT NULL PRIMARY KEY,"nick" text NOT NULL DEFAULT 'x',`+
+		`FOREIGN KEY ("id") REFERENCES "accounts")`, render(create.Tree))
+
+	// removing a column definition
+	tree = Rewrite(create.Tree, func(o interface{}) interface{} {
+		if c, ok := o.(*ColumnDef); ok && c.Name == "nick" {
+			return nil
+		}
+		return o
+	})
+	assert.Len(t, tree.(*CreateTableStatementNode).Columns, 1)
+	assert.Len(t, create.Tree.Columns, 2)
+
+	alter := users.AlterTable().AddColumn(&ColumnDef{Name: "age", Type: "int"}).
+		AddConstraint(Check("age > 0"))
+	assert.Equal(t, `ALTER TABLE "tenant_7"."users" ADD COLUMN "age" int NOT NULL,ADD CHECK (age > 0)`, render(Rewrite(alter.Tree, tenant)))
+
+	index := users.CreateIndex("users_nick").On("nick").Where(users.Col("nick").Neq(""))
+	assert.Equal(t, `CREATE INDEX "users_nick" ON "tenant_7"."users" ("nick") WHERE ("tenant_7"."users"."nick"!='')`, render(Rewrite(index.Tree, tenant)))
+
+	drop := DropTable(users, psql.Table("accounts"))
+	assert.Equal(t, `DROP TABLE "tenant_7"."users","tenant_7"."accounts"`, render(Rewrite(drop.Tree, tenant)))
+	assert.Equal(t, `DROP TABLE "users","accounts"`, render(drop.Tree))
+}